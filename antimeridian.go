@@ -0,0 +1,457 @@
+/* functions for geographic geometries that cross the antimeridian */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// wrapPosition returns a position with its longitude in [-180, 180]
+func wrapPosition(pos []float64) []float64 {
+	if len(pos) < 2 || (pos[0] >= -180 && pos[0] <= 180) {
+		return pos
+	}
+	wrapped := append([]float64{}, pos...)
+	wrapped[0] = math.Remainder(pos[0], 360)
+	return wrapped
+}
+
+// withX returns a copy of a position with its first coordinate replaced
+func withX(pos []float64, x float64) []float64 {
+	out := append([]float64{}, pos...)
+	out[0] = x
+	return out
+}
+
+// cutLine splits a line of longitude and latitude positions wherever it
+// crosses the antimeridian, taking each edge the shorter way around. The
+// crossing latitude is interpolated linearly in longitude and latitude, and
+// parts with fewer than two positions are dropped.
+func cutLine(line [][]float64) [][][]float64 {
+	if len(line) == 0 {
+		return nil
+	}
+	var parts [][][]float64
+	part := [][]float64{wrapPosition(line[0])}
+	finish := func() {
+		if len(part) > 1 {
+			parts = append(parts, part)
+		}
+	}
+	for i := 1; i < len(line); i++ {
+		p, q := part[len(part)-1], wrapPosition(line[i])
+		dlon := q[0] - p[0]
+		if math.Abs(dlon) <= 180 {
+			if !samePosition(p, q) {
+				part = append(part, q)
+			}
+			continue
+		}
+		// the meridian crossed is on the same side as p
+		edge := math.Copysign(180, p[0])
+		qx := q[0] - math.Copysign(360, dlon)
+		crossing := interpolate(p, withX(q, qx), (edge-p[0])/(qx-p[0]))
+		crossing[0] = edge
+		if !samePosition(p, crossing) {
+			part = append(part, crossing)
+		}
+		finish()
+		part = [][]float64{withX(crossing, -edge)}
+		if !samePosition(part[0], q) {
+			part = append(part, q)
+		}
+	}
+	finish()
+	return parts
+}
+
+// unwrapRing returns a copy of an open ring with longitudes shifted by
+// multiples of 360 so that no edge spans more than 180 degrees and the
+// first longitude is in [lon0, lon0+360). It also returns the net change in
+// longitude around the ring, which is ±360 for rings around a pole.
+func unwrapRing(ring [][]float64, lon0 float64) ([][]float64, float64) {
+	ring = openRing(ring)
+	out := make([][]float64, len(ring))
+	for i, pos := range ring {
+		if i == 0 {
+			out[0] = withX(pos, lon0+math.Mod(math.Mod(pos[0]-lon0, 360)+360, 360))
+			continue
+		}
+		out[i] = withX(pos, out[i-1][0]+math.Remainder(pos[0]-ring[i-1][0], 360))
+	}
+	n := len(ring)
+	net := out[n-1][0] + math.Remainder(ring[0][0]-ring[n-1][0], 360) - out[0][0]
+	return out, net
+}
+
+// closeOverPole closes an unwrapped ring that encircles a pole. The ring is
+// rotated to start where it crosses an antimeridian and closed along that
+// meridian and the line of latitude at the pole, so that it lies within a
+// single band of longitude.
+func closeOverPole(ring [][]float64, net float64, pole float64) [][]float64 {
+	n := len(ring)
+	next := func(i int) []float64 {
+		if i == n-1 {
+			return withX(ring[0], ring[0][0]+net)
+		}
+		return ring[i+1]
+	}
+	var start int
+	var crossing []float64
+	for i := range ring {
+		p, q := ring[i], next(i)
+		lo, hi := math.Min(p[0], q[0]), math.Max(p[0], q[0])
+		meridian := 180 + 360*math.Ceil((lo-180)/360)
+		if meridian <= hi && p[0] != q[0] {
+			crossing = interpolate(p, q, (meridian-p[0])/(q[0]-p[0]))
+			crossing[0] = meridian
+			start = i + 1
+			break
+		}
+	}
+
+	closed := [][]float64{crossing}
+	add := func(pos []float64) {
+		if !samePosition(pos, closed[len(closed)-1]) {
+			closed = append(closed, pos)
+		}
+	}
+	for i := start; i < n; i++ {
+		add(ring[i])
+	}
+	for i := 0; i < start; i++ {
+		add(withX(ring[i], ring[i][0]+net))
+	}
+	end := withX(crossing, crossing[0]+net)
+	add(end)
+	poleEnd := withX(end, end[0])
+	poleEnd[1] = pole
+	poleStart := withX(crossing, crossing[0])
+	poleStart[1] = pole
+	return append(closed, poleEnd, poleStart)
+}
+
+// cutPolygon splits a polygon of longitude and latitude positions along the
+// antimeridian. Rings are unwrapped so that their edges are continuous, the
+// result is clipped to each 360 degree band of longitude it reaches, and
+// the parts are shifted back into [-180, 180]. Following the right-hand
+// rule of RFC 7946, a shell that runs eastward around the globe contains
+// the north pole and one that runs westward contains the south pole.
+func cutPolygon(rings [][][]float64) [][][][]float64 {
+	if len(rings) == 0 || len(openRing(rings[0])) < 3 {
+		return nil
+	}
+	var unwrapped [][][]float64
+	lonmin, lonmax := math.Inf(1), math.Inf(-1)
+	for i, ring := range rings {
+		if len(openRing(ring)) < 3 {
+			continue
+		}
+		lon0 := -180.0
+		if i != 0 {
+			lon0 = lonmin
+		}
+		ring, net := unwrapRing(ring, lon0)
+		if math.Abs(net) > 180 {
+			pole := -90.0
+			if (net > 0) == (i == 0) {
+				pole = 90
+			}
+			ring = closeOverPole(ring, net, pole)
+		}
+		for _, pos := range ring {
+			if i == 0 {
+				lonmin = math.Min(lonmin, pos[0])
+			}
+			lonmax = math.Max(lonmax, pos[0])
+		}
+		unwrapped = append(unwrapped, ring)
+	}
+
+	var parts [][][][]float64
+	kmin := int(math.Floor((lonmin + 180) / 360))
+	kmax := int(math.Ceil((lonmax - 180) / 360))
+	for k := kmin; k <= kmax; k++ {
+		shift := 360 * float64(k)
+		for _, part := range clipPolygon(unwrapped, [4]float64{shift - 180, -90, shift + 180, 90}) {
+			shifted := make([][][]float64, len(part))
+			for i, ring := range part {
+				shifted[i] = make([][]float64, len(ring))
+				for j, pos := range ring {
+					shifted[i][j] = withX(pos, pos[0]-shift)
+				}
+				if len(shifted[i]) != 0 && !samePosition(shifted[i][0], shifted[i][len(ring)-1]) {
+					shifted[i] = append(shifted[i], shifted[i][0])
+				}
+			}
+			parts = append(parts, shifted)
+		}
+	}
+	return parts
+}
+
+func cutGeo(g *Geo) (*Geo, error) {
+	switch g.Type {
+	case "Point":
+		return &Geo{Type: "Point", Point: &Point{g.Point.CRSReferencable, wrapPosition(g.Point.Coordinates)}}, nil
+	case "MultiPoint":
+		mp := &MultiPoint{g.MultiPoint.CRSReferencable, make([][]float64, len(g.MultiPoint.Coordinates))}
+		for i, pos := range g.MultiPoint.Coordinates {
+			mp.Coordinates[i] = wrapPosition(pos)
+		}
+		return &Geo{Type: "MultiPoint", MultiPoint: mp}, nil
+	case "LineString":
+		parts := cutLine(g.LineString.Coordinates)
+		if len(parts) == 1 {
+			return &Geo{Type: "LineString", LineString: &LineString{g.LineString.CRSReferencable, parts[0]}}, nil
+		}
+		if parts == nil {
+			parts = [][][]float64{}
+		}
+		return &Geo{Type: "MultiLineString",
+			MultiLineString: &MultiLineString{g.LineString.CRSReferencable, parts}}, nil
+	case "MultiLineString":
+		mls := &MultiLineString{g.MultiLineString.CRSReferencable, [][][]float64{}}
+		for _, line := range g.MultiLineString.Coordinates {
+			mls.Coordinates = append(mls.Coordinates, cutLine(line)...)
+		}
+		return &Geo{Type: "MultiLineString", MultiLineString: mls}, nil
+	case "Polygon":
+		parts := cutPolygon(g.Polygon.Coordinates)
+		if len(parts) == 1 {
+			return &Geo{Type: "Polygon", Polygon: &Polygon{g.Polygon.CRSReferencable, parts[0]}}, nil
+		}
+		if parts == nil {
+			parts = [][][][]float64{}
+		}
+		return &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{g.Polygon.CRSReferencable, parts}}, nil
+	case "MultiPolygon":
+		mp := &MultiPolygon{g.MultiPolygon.CRSReferencable, [][][][]float64{}}
+		for _, poly := range g.MultiPolygon.Coordinates {
+			mp.Coordinates = append(mp.Coordinates, cutPolygon(poly)...)
+		}
+		return &Geo{Type: "MultiPolygon", MultiPolygon: mp}, nil
+	case "GeometryCollection":
+		coll := &GeometryCollection{g.GeometryCollection.CRSReferencable,
+			make([]*Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			cut, err := cutGeo(member)
+			if err != nil {
+				return nil, err
+			}
+			coll.Geometries[i] = cut
+		}
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		cut, err := cutGeo(&g.Feature.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		f := *g.Feature
+		f.Geometry = *cut
+		return &Geo{Type: "Feature", Feature: &f}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{g.FeatureCollection.CRSReferencable,
+			make([]Feature, len(g.FeatureCollection.Features))}
+		for i, f := range g.FeatureCollection.Features {
+			cut, err := cutGeo(&f.Geometry)
+			if err != nil {
+				return nil, err
+			}
+			f.Geometry = *cut
+			coll.Features[i] = f
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+// CutAntimeridian returns the Point with its longitude wrapped into
+// [-180, 180]
+func (g *Point) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "Point", Point: g})
+}
+
+// CutAntimeridian returns the LineString split where it crosses the
+// antimeridian as a MultiLineString, or a LineString if it does not cross
+func (g *LineString) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "LineString", LineString: g})
+}
+
+// CutAntimeridian returns the Polygon split where it crosses the
+// antimeridian as a MultiPolygon, or a Polygon if it does not cross. A
+// Polygon around a pole is closed along the meridians at ±180 and the line
+// of latitude at the pole.
+func (g *Polygon) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// CutAntimeridian returns the MultiPoint with its longitudes wrapped into
+// [-180, 180]
+func (g *MultiPoint) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "MultiPoint", MultiPoint: g})
+}
+
+// CutAntimeridian returns the MultiLineString with each line split where it
+// crosses the antimeridian
+func (g *MultiLineString) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "MultiLineString", MultiLineString: g})
+}
+
+// CutAntimeridian returns the MultiPolygon with each polygon split where it
+// crosses the antimeridian
+func (g *MultiPolygon) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// CutAntimeridian returns a GeometryCollection with every member cut at the
+// antimeridian
+func (coll *GeometryCollection) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+}
+
+// CutAntimeridian returns a copy of the Feature with its geometry cut at the
+// antimeridian
+func (f *Feature) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "Feature", Feature: f})
+}
+
+// CutAntimeridian returns a FeatureCollection with every Feature cut at the
+// antimeridian
+func (coll *FeatureCollection) CutAntimeridian() (*Geo, error) {
+	return cutGeo(&Geo{Type: "FeatureCollection", FeatureCollection: coll})
+}
+
+// CutAntimeridian splits a geometry of longitude and latitude positions
+// wherever it crosses the antimeridian, as recommended by section 3.1.9 of
+// RFC 7946. Each edge is taken to be the shorter way around the globe, and
+// longitudes in the result are in [-180, 180]. LineStrings and Polygons that
+// cross become MultiLineStrings and MultiPolygons, with parts meeting at
+// ±180. Polygons that contain a pole are identified by their shell running
+// all the way around the globe, and follow the right-hand rule: eastward
+// for the north pole and westward for the south pole.
+func (g *Geo) CutAntimeridian() (*Geo, error) {
+	return cutGeo(g)
+}
+
+func geographicBbox(g *Geo) (*Bbox, error) {
+	cut, err := cutGeo(g)
+	if err != nil {
+		return nil, err
+	}
+	c := new(components)
+	if err := c.add(cut); err != nil {
+		return nil, err
+	}
+
+	// each part occupies an interval of longitude
+	var intervals [][2]float64
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	addPart := func(positions [][]float64) {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, pos := range positions {
+			lo, hi = math.Min(lo, pos[0]), math.Max(hi, pos[0])
+			ymin, ymax = math.Min(ymin, pos[1]), math.Max(ymax, pos[1])
+		}
+		if lo <= hi {
+			intervals = append(intervals, [2]float64{lo, hi})
+		}
+	}
+	for _, pos := range c.points {
+		addPart([][]float64{pos})
+	}
+	for _, line := range c.lines {
+		addPart(line)
+	}
+	for _, poly := range c.polygons {
+		if len(poly) != 0 {
+			addPart(poly[0])
+		}
+	}
+	if len(intervals) == 0 {
+		return nil, errors.New("bounding box of empty geometry")
+	}
+
+	// the box is the complement of the largest gap between the intervals
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+	east := intervals[0][1]
+	xmin, xmax := intervals[0][0], 0.0
+	gap := math.Inf(-1)
+	for _, interval := range intervals[1:] {
+		if interval[0]-east > gap {
+			gap = interval[0] - east
+			xmin, xmax = interval[0], east
+		}
+		east = math.Max(east, interval[1])
+	}
+	if intervals[0][0]+360-east >= gap {
+		xmin, xmax = intervals[0][0], east
+	}
+	return &Bbox{xmin, ymin, xmax, ymax}, nil
+}
+
+// GeographicBbox returns the bounding box of the Point
+func (p *Point) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "Point", Point: p})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the LineString. See Geo.GeographicBbox.
+func (g *LineString) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "LineString", LineString: g})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the Polygon. See Geo.GeographicBbox.
+func (g *Polygon) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the MultiPoint. See Geo.GeographicBbox.
+func (g *MultiPoint) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "MultiPoint", MultiPoint: g})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the MultiLineString. See Geo.GeographicBbox.
+func (g *MultiLineString) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "MultiLineString", MultiLineString: g})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the MultiPolygon. See Geo.GeographicBbox.
+func (g *MultiPolygon) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// every member of the GeometryCollection. See Geo.GeographicBbox.
+func (coll *GeometryCollection) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// the Feature geometry. See Geo.GeographicBbox.
+func (f *Feature) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&f.Geometry)
+}
+
+// GeographicBbox returns the smallest bounding box in longitude containing
+// every Feature. See Geo.GeographicBbox.
+func (coll *FeatureCollection) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(&Geo{Type: "FeatureCollection", FeatureCollection: coll})
+}
+
+// GeographicBbox returns the bounding box of a geometry of longitude and
+// latitude positions that takes the shortest span of longitude, allowing it
+// to cross the antimeridian. Following section 5.2 of RFC 7946, a box
+// crossing the antimeridian has xmin greater than xmax, so that a geometry
+// from 170°E to 170°W has xmin 170 and xmax -170. Edges are taken to be the
+// shorter way around the globe as in CutAntimeridian, and geometries
+// containing a pole span all longitudes and extend to ±90.
+func (g *Geo) GeographicBbox() (*Bbox, error) {
+	return geographicBbox(g)
+}
@@ -0,0 +1,181 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCutAntimeridianLineString(t *testing.T) {
+	line := &LineString{Coordinates: [][]float64{{170, 10}, {-170, 20}, {-160, 20}}}
+	cut, err := line.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "MultiLineString" {
+		t.Fatal("expected a MultiLineString, got", cut.Type)
+	}
+	expected := [][][]float64{{{170, 10}, {180, 15}}, {{-180, 15}, {-170, 20}, {-160, 20}}}
+	if fmt.Sprint(cut.MultiLineString.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", cut.MultiLineString.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	// westward, with longitudes beyond 180
+	line = &LineString{Coordinates: [][]float64{{190, 0}, {170, 10}}}
+	cut, err = line.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = [][][]float64{{{-170, 0}, {-180, 5}}, {{180, 5}, {170, 10}}}
+	if cut.Type != "MultiLineString" || fmt.Sprint(cut.MultiLineString.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", cut)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestCutAntimeridianLineStringUncrossed(t *testing.T) {
+	line := &LineString{Coordinates: [][]float64{{-10, 0}, {10, 0}, {180, 5}}}
+	cut, err := line.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "LineString" || fmt.Sprint(cut.LineString.Coordinates) != fmt.Sprint(line.Coordinates) {
+		fmt.Println("recieved    ", cut)
+		t.Fail()
+	}
+}
+
+func TestCutAntimeridianPolygon(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}}}
+	cut, err := poly.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "MultiPolygon" || len(cut.MultiPolygon.Coordinates) != 2 {
+		t.Fatal("expected a MultiPolygon with two parts")
+	}
+	for _, part := range cut.MultiPolygon.Coordinates {
+		bb, _ := (&Polygon{Coordinates: part}).Bbox()
+		if ringArea(part[0]) != 200 || (*bb != Bbox{170, -10, 180, 10} && *bb != Bbox{-180, -10, -170, 10}) {
+			fmt.Println("recieved    ", part)
+			t.Fail()
+		}
+	}
+}
+
+func TestCutAntimeridianPolygonHole(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{160, -10}, {-160, -10}, {-160, 10}, {160, 10}, {160, -10}},
+		{{175, -5}, {175, 5}, {-175, 5}, {-175, -5}, {175, -5}},
+	}}
+	cut, err := poly.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "MultiPolygon" || len(cut.MultiPolygon.Coordinates) != 2 {
+		t.Fatal("expected a MultiPolygon with two parts")
+	}
+	var area float64
+	for _, part := range cut.MultiPolygon.Coordinates {
+		for _, ring := range part {
+			area += ringArea(ring)
+		}
+	}
+	if area != 800-100 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", 700)
+		t.Fail()
+	}
+}
+
+func TestCutAntimeridianPolygonPole(t *testing.T) {
+	// an eastward ring around the north pole
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 80}, {90, 80}, {180, 80}, {-90, 80}, {0, 80}}}}
+	cut, err := poly.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", cut.Type)
+	}
+	bb, _ := cut.Polygon.Bbox()
+	if ringArea(cut.Polygon.Coordinates[0]) != 3600 || *bb != (Bbox{-180, 80, 180, 90}) {
+		fmt.Println("recieved    ", cut.Polygon.Coordinates)
+		t.Fail()
+	}
+
+	// a westward ring around the south pole crossing between vertices
+	poly = &Polygon{Coordinates: [][][]float64{{{-45, -70}, {-135, -70}, {135, -60}, {45, -60}, {-45, -70}}}}
+	cut, err = poly.CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cut.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", cut.Type)
+	}
+	bb, _ = cut.Polygon.Bbox()
+	if *bb != (Bbox{-180, -90, 180, -60}) || !isCounterClockwise(cut.Polygon.Coordinates[0]) {
+		fmt.Println("recieved    ", cut.Polygon.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestGeographicBbox(t *testing.T) {
+	line := &LineString{Coordinates: [][]float64{{170, 50}, {-170, 60}, {-160, 55}}}
+	bb, err := line.GeographicBbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *bb != (Bbox{170, 50, -160, 60}) {
+		fmt.Println("recieved    ", *bb)
+		t.Fail()
+	}
+
+	mp := &MultiPoint{Coordinates: [][]float64{{-10, 0}, {20, 5}, {100, 0}}}
+	bb, err = mp.GeographicBbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *bb != (Bbox{-10, 0, 100, 5}) {
+		fmt.Println("recieved    ", *bb)
+		t.Fail()
+	}
+
+	// the largest gap is between -170 and 20
+	mp = &MultiPoint{Coordinates: [][]float64{{-170, 0}, {20, 5}, {100, 0}}}
+	bb, err = mp.GeographicBbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *bb != (Bbox{20, 0, -170, 5}) {
+		fmt.Println("recieved    ", *bb)
+		t.Fail()
+	}
+}
+
+func TestBboxPolygon(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 0}, {4, 0}, {4, 3}, {0, 3}, {0, 0}}}}
+	bb, _ := poly.Bbox()
+	if *bb != (Bbox{0, 0, 4, 3}) {
+		fmt.Println("recieved    ", *bb)
+		t.Fail()
+	}
+	mp := &MultiPolygon{Coordinates: [][][][]float64{poly.Coordinates,
+		{{{5, 5}, {6, 5}, {6, 7}, {5, 5}}}}}
+	bb, _ = mp.Bbox()
+	if *bb != (Bbox{0, 0, 6, 7}) {
+		fmt.Println("recieved    ", *bb)
+		t.Fail()
+	}
+	coll := &GeometryCollection{Geometries: []*Geo{
+		{Type: "Polygon", Polygon: poly},
+		{Type: "Point", Point: &Point{Coordinates: []float64{-1, 8}}},
+	}}
+	bb, err := coll.Bbox()
+	if err != nil || *bb != (Bbox{-1, 0, 4, 8}) {
+		fmt.Println("recieved    ", bb, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,55 @@
+/* boolean operations on polygonal geometries */
+package geojson
+
+import "errors"
+
+// overlay combines two polygonal geometries, keeping the parts of the plane
+// for which keep returns true given whether a position is inside a and b
+func overlay(a, b *Geo, keep func(inA, inB bool) bool) (*Geo, error) {
+	var edges []overlayEdge
+	for op, g := range []*Geo{a, b} {
+		c := new(components)
+		if err := c.add(g); err != nil {
+			return nil, err
+		}
+		if len(c.points) != 0 || len(c.lines) != 0 {
+			return nil, errors.New("boolean operations require Polygons or MultiPolygons")
+		}
+		for _, poly := range c.polygons {
+			edges = appendPolygonEdges(edges, poly, op)
+		}
+	}
+	polygons := overlayAreas(edges, func(w [2]int) bool {
+		return keep(w[0] > 0, w[1] > 0)
+	})
+	return areasGeo(polygons), nil
+}
+
+// Union returns the area covered by either of two Polygons or MultiPolygons
+// (or Features and collections of them) as a Polygon or MultiPolygon.
+// Results of the boolean operations have counter-clockwise shells with
+// their clockwise holes, and are an empty MultiPolygon when nothing
+// remains. Edges that are shared or partly overlap are merged, and parts
+// that overlap within a single MultiPolygon are combined. Positions are
+// snapped to a grid with a spacing of about 1e-12 of the largest
+// coordinate, so that edges closer than that are treated as coincident.
+func Union(a, b *Geo) (*Geo, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Intersection returns the area covered by both of two polygonal
+// geometries. Parts that only share edges or vertices are not included.
+func Intersection(a, b *Geo) (*Geo, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference returns the area covered by polygonal geometry a and not by b
+func Difference(a, b *Geo) (*Geo, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// SymDifference returns the area covered by exactly one of two polygonal
+// geometries, their exclusive or
+func SymDifference(a, b *Geo) (*Geo, error) {
+	return overlay(a, b, func(inA, inB bool) bool { return inA != inB })
+}
@@ -0,0 +1,239 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOverlaySquares(t *testing.T) {
+	a := polygonGeo(square(0, 0, 2))
+	b := polygonGeo(square(1, 1, 2))
+	cases := []struct {
+		op       func(a, b *Geo) (*Geo, error)
+		typ      string
+		expected float64
+	}{
+		{Union, "Polygon", 7},
+		{Intersection, "Polygon", 1},
+		{Difference, "Polygon", 3},
+		// two L shapes meeting at their corners
+		{SymDifference, "MultiPolygon", 6},
+	}
+	for i, c := range cases {
+		g, err := c.op(a, b)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+			continue
+		}
+		if g.Type != c.typ || g.Area() != c.expected {
+			fmt.Println("case", i)
+			fmt.Println("recieved    ", g.Type, g.Area())
+			fmt.Println("but expected", c.typ, c.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestOverlaySharedEdges(t *testing.T) {
+	a := polygonGeo(square(0, 0, 1))
+	b := polygonGeo(square(1, 0, 1))
+	g, err := Union(a, b)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	// the shared edge and its collinear vertices disappear
+	if g.Type != "Polygon" || len(g.Polygon.Coordinates[0]) != 5 || g.Area() != 2 {
+		fmt.Println("recieved    ", g.Type, g.Area())
+		t.Fail()
+	}
+
+	g, err = Intersection(a, b)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if g.Type != "MultiPolygon" || len(g.MultiPolygon.Coordinates) != 0 {
+		fmt.Println("recieved    ", g.Type)
+		t.Fail()
+	}
+
+	// partly overlapping collinear edges
+	b = polygonGeo([][]float64{{1, 0}, {3, 0}, {3, 1}, {1, 1}, {1, 0}})
+	g, err = Union(polygonGeo(square(0, 0, 2)), b)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if g.Type != "Polygon" || g.Area() != 5 || len(g.Polygon.Coordinates[0]) != 7 {
+		fmt.Println("recieved    ", g.Polygon.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestOverlayIdentical(t *testing.T) {
+	a := polygonGeo(square(0, 0, 1))
+	for i, op := range []func(a, b *Geo) (*Geo, error){Union, Intersection, Difference, SymDifference} {
+		g, err := op(a, a)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		expected := 1.0
+		if i >= 2 {
+			expected = 0
+		}
+		if g.Area() != expected {
+			fmt.Println("case", i)
+			fmt.Println("recieved    ", g.Area())
+			fmt.Println("but expected", expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestOverlayHoles(t *testing.T) {
+	holed := polygonGeo(square(0, 0, 10), [][]float64{{3, 3}, {3, 7}, {7, 7}, {7, 3}, {3, 3}})
+	island := polygonGeo(square(4, 4, 2))
+
+	g, err := Union(holed, island)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if g.Type != "MultiPolygon" || len(g.MultiPolygon.Coordinates) != 2 || g.Area() != 88 {
+		t.Fatal("expected the island to be a separate part")
+	}
+	for _, poly := range g.MultiPolygon.Coordinates {
+		if len(poly) == 2 && isCounterClockwise(poly[1]) {
+			t.Fail()
+		}
+	}
+
+	// cutting a square out of the middle makes a hole
+	g, err = Difference(polygonGeo(square(0, 0, 10)), island)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if g.Type != "Polygon" || len(g.Polygon.Coordinates) != 2 || g.Area() != 96 {
+		t.Fail()
+	}
+
+	// an overlap with the hole only covers the shell
+	g, err = Intersection(holed, polygonGeo(square(2, 2, 6)))
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if g.Area() != 36-16 {
+		fmt.Println("recieved    ", g.Area())
+		t.Fail()
+	}
+}
+
+func TestOverlayRejectsLines(t *testing.T) {
+	if _, err := Union(polygonGeo(square(0, 0, 1)), lineGeo([]float64{0, 0}, []float64{1, 1})); err == nil {
+		t.Fail()
+	}
+}
+
+// starPolygon returns a random star-shaped polygon about (x, y)
+func starPolygon(rng *rand.Rand, x, y float64) *Geo {
+	n := 5 + rng.Intn(20)
+	ring := make([][]float64, n+1)
+	for i := 0; i != n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		r := 1 + 4*rng.Float64()
+		ring[i] = []float64{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	ring[n] = ring[0]
+	return polygonGeo(ring)
+}
+
+func TestOverlayAreaIdentities(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for trial := 0; trial != 50; trial++ {
+		a := starPolygon(rng, 0, 0)
+		b := starPolygon(rng, 3*rng.Float64(), 3*rng.Float64())
+		union, _ := Union(a, b)
+		inter, _ := Intersection(a, b)
+		diff, _ := Difference(a, b)
+		xor, _ := SymDifference(a, b)
+		areaA, areaB := a.Area(), b.Area()
+		tol := 1e-9 * (areaA + areaB)
+		if math.Abs(union.Area()-(areaA+areaB-inter.Area())) > tol ||
+			math.Abs(diff.Area()-(areaA-inter.Area())) > tol ||
+			math.Abs(xor.Area()-(union.Area()-inter.Area())) > tol {
+			fmt.Println("trial", trial, "areas", areaA, areaB, union.Area(), inter.Area(), diff.Area(), xor.Area())
+			t.Fail()
+		}
+	}
+}
+
+// shiftedPolygon returns a copy of a polygon with every position moved by
+// dx, dy
+func shiftedPolygon(g *Geo, dx, dy float64) *Geo {
+	rings := make([][][]float64, len(g.Polygon.Coordinates))
+	for i, ring := range g.Polygon.Coordinates {
+		for _, pos := range ring {
+			rings[i] = append(rings[i], []float64{pos[0] + dx, pos[1] + dy})
+		}
+	}
+	return polygonGeo(rings...)
+}
+
+func TestOverlayNearCoincident(t *testing.T) {
+	// stars in longitude and latitude, projected metres and small units,
+	// with copies shifted or perturbed by about the round-off in their
+	// coordinates
+	rng := rand.New(rand.NewSource(34))
+	for trial := 0; trial != 600; trial++ {
+		x, y, r := -122.4, 37.7, 0.01
+		switch trial % 3 {
+		case 1:
+			x, y, r = 500000, 4000000, 1000
+		case 2:
+			x, y, r = 0, 0, 1
+		}
+		n := 5 + rng.Intn(40)
+		ring := make([][]float64, n+1)
+		for i := 0; i != n; i++ {
+			angle := 2 * math.Pi * float64(i) / float64(n)
+			ri := r * (1 + 4*rng.Float64())
+			ring[i] = []float64{x + ri*math.Cos(angle), y + ri*math.Sin(angle)}
+		}
+		ring[n] = ring[0]
+		a := polygonGeo(ring)
+		shift := math.Max(math.Abs(x), 1) * math.Pow(10, -9-4*rng.Float64())
+		b := shiftedPolygon(a, shift*rng.NormFloat64(), shift*rng.NormFloat64())
+		if trial%2 == 0 {
+			for _, pos := range b.Polygon.Coordinates[0][:n] {
+				pos[0] += shift * rng.NormFloat64()
+				pos[1] += shift * rng.NormFloat64()
+			}
+			b.Polygon.Coordinates[0][n] = b.Polygon.Coordinates[0][0]
+		}
+
+		union, _ := Union(a, b)
+		inter, _ := Intersection(a, b)
+		diff, _ := Difference(a, b)
+		xor, _ := SymDifference(a, b)
+		mp := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{
+			a.Polygon.Coordinates, b.Polygon.Coordinates}}}
+		valid, _ := mp.MakeValid()
+		areaA, areaB := a.Area(), b.Area()
+		tol := 1e-6 * areaA
+		if math.Abs(union.Area()-(areaA+areaB-inter.Area())) > tol ||
+			math.Abs(diff.Area()-(areaA-inter.Area())) > tol ||
+			math.Abs(xor.Area()-(union.Area()-inter.Area())) > tol ||
+			math.Abs(inter.Area()-areaA) > 1e-3*areaA ||
+			math.Abs(valid.Area()-union.Area()) > tol {
+			fmt.Println("trial", trial, "areas", areaA, union.Area(), inter.Area(), diff.Area(), xor.Area(), valid.Area())
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,403 @@
+/* functions for buffering geometries by a distance */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// JoinStyle selects how the offset edges of a buffer meet at the outside of
+// a vertex
+type JoinStyle int
+
+const (
+	// JoinRound joins edges with a circular arc
+	JoinRound JoinStyle = iota
+	// JoinMitre extends edges until they meet, subject to the mitre limit
+	JoinMitre
+	// JoinBevel joins edges with a straight line across the corner
+	JoinBevel
+)
+
+// CapStyle selects how the ends of buffered lines are closed
+type CapStyle int
+
+const (
+	// CapRound closes ends with a semicircle
+	CapRound CapStyle = iota
+	// CapFlat closes ends with a line through the endpoint
+	CapFlat
+	// CapSquare closes ends with a half-square extending past the endpoint
+	CapSquare
+)
+
+// BufferOptions controls the shape of a buffer. The zero value gives round
+// joins and caps approximated with eight segments per quarter circle.
+type BufferOptions struct {
+	// QuadrantSegments is the number of segments used to approximate a
+	// quarter circle, 8 if zero
+	QuadrantSegments int
+	Join             JoinStyle
+	Cap              CapStyle
+	// MitreLimit is the greatest ratio of the distance from a vertex to its
+	// mitre point and the buffer distance, 5 if zero. Mitres that would
+	// exceed it are cut square to the corner at that distance.
+	MitreLimit float64
+	// Geodesic treats coordinates as longitude and latitude and the buffer
+	// distance as metres on the WGS84 ellipsoid
+	Geodesic bool
+}
+
+const (
+	defaultQuadrantSegments = 8
+	defaultMitreLimit       = 5.0
+)
+
+// bufferBuilder collects the polygonal pieces whose union forms the buffer
+// of a set of points and lines
+type bufferBuilder struct {
+	distance   float64
+	join       JoinStyle
+	cap        CapStyle
+	quadSegs   int
+	mitreLimit float64
+	edges      []overlayEdge
+}
+
+// addPiece adds a simple polygon to the union, in either orientation
+func (b *bufferBuilder) addPiece(ring [][]float64) {
+	b.edges = appendRingEdges(b.edges, ring, 1, true)
+}
+
+// offset returns pos moved distance along the unit vector (dx, dy)
+func (b *bufferBuilder) offset(pos []float64, dx, dy float64) []float64 {
+	return []float64{pos[0] + b.distance*dx, pos[1] + b.distance*dy}
+}
+
+// arc returns positions around centre from angle a0, turning through sweep
+// radians, including both ends
+func (b *bufferBuilder) arc(centre []float64, a0, sweep float64) [][]float64 {
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2) * float64(b.quadSegs)))
+	if n < 1 {
+		n = 1
+	}
+	positions := make([][]float64, n+1)
+	for i := 0; i <= n; i++ {
+		a := a0 + sweep*float64(i)/float64(n)
+		positions[i] = b.offset(centre, math.Cos(a), math.Sin(a))
+	}
+	return positions
+}
+
+func (b *bufferBuilder) addPoint(pos []float64) {
+	switch b.cap {
+	case CapRound:
+		b.addPiece(b.arc(pos, 0, 2*math.Pi)[:4*b.quadSegs])
+	case CapSquare:
+		b.addPiece([][]float64{
+			b.offset(pos, 1, 1), b.offset(pos, -1, 1), b.offset(pos, -1, -1), b.offset(pos, 1, -1),
+		})
+	}
+}
+
+// addCap closes the end of a line at pos, where (ux, uy) points away from
+// the line
+func (b *bufferBuilder) addCap(pos []float64, ux, uy float64) {
+	switch b.cap {
+	case CapRound:
+		b.addPiece(b.arc(pos, math.Atan2(uy, ux)-math.Pi/2, math.Pi))
+	case CapSquare:
+		b.addPiece([][]float64{
+			b.offset(pos, uy, -ux), b.offset(pos, ux+uy, uy-ux),
+			b.offset(pos, ux-uy, uy+ux), b.offset(pos, -uy, ux),
+		})
+	}
+}
+
+// addJoin fills the gap between the offset edges on the outside of the turn
+// at v, where (u1x, u1y) and (u2x, u2y) are the incoming and outgoing unit
+// directions
+func (b *bufferBuilder) addJoin(v []float64, u1x, u1y, u2x, u2y float64) {
+	turn := u1x*u2y - u1y*u2x
+	dot := u1x*u2x + u1y*u2y
+	if turn == 0 {
+		if dot < 0 && b.join == JoinRound {
+			// the line doubles back on itself
+			b.addPiece(b.arc(v, math.Atan2(u1y, u1x)-math.Pi/2, math.Pi))
+		}
+		return
+	}
+	// unit normals pointing to the outside of the turn
+	n1x, n1y, n2x, n2y := u1y, -u1x, u2y, -u2x
+	if turn < 0 {
+		n1x, n1y, n2x, n2y = -n1x, -n1y, -n2x, -n2y
+	}
+	p1 := b.offset(v, n1x, n1y)
+	p2 := b.offset(v, n2x, n2y)
+
+	switch b.join {
+	case JoinRound:
+		sweep := math.Atan2(turn, dot)
+		b.addPiece(append([][]float64{v}, b.arc(v, math.Atan2(n1y, n1x), sweep)...))
+	case JoinMitre:
+		bx, by := n1x+n2x, n1y+n2y
+		blen := math.Hypot(bx, by)
+		if blen == 0 {
+			return
+		}
+		bx, by = bx/blen, by/blen
+		cosHalf := blen / 2
+		ratio := 1 / cosHalf
+		if ratio <= b.mitreLimit {
+			b.addPiece([][]float64{v, p1, b.offset(v, bx*ratio, by*ratio), p2})
+			return
+		}
+		// cut the mitre square to the bisector at the limit
+		m := b.offset(v, bx*ratio, by*ratio)
+		t := (b.mitreLimit - cosHalf) / (ratio - cosHalf)
+		c1 := []float64{p1[0] + t*(m[0]-p1[0]), p1[1] + t*(m[1]-p1[1])}
+		c2 := []float64{p2[0] + t*(m[0]-p2[0]), p2[1] + t*(m[1]-p2[1])}
+		b.addPiece([][]float64{v, p1, c1, c2, p2})
+	case JoinBevel:
+		b.addPiece([][]float64{v, p1, p2})
+	}
+}
+
+// addPath adds the buffer of a line, or of a ring if closed is true
+func (b *bufferBuilder) addPath(path [][]float64, closed bool) {
+	positions := make([][]float64, 0, len(path))
+	for _, pos := range path {
+		if len(positions) == 0 || !samePosition(pos, positions[len(positions)-1]) {
+			positions = append(positions, pos)
+		}
+	}
+	if len(positions) > 2 && samePosition(positions[0], positions[len(positions)-1]) {
+		// lines that return to their start are joined rather than capped
+		closed = true
+		positions = positions[:len(positions)-1]
+	} else if closed && len(positions) > 1 && samePosition(positions[0], positions[len(positions)-1]) {
+		positions = positions[:len(positions)-1]
+	}
+	n := len(positions)
+	switch {
+	case n == 0:
+		return
+	case n == 1:
+		if !closed {
+			b.addPoint(positions[0])
+		}
+		return
+	case n == 2:
+		closed = false
+	}
+
+	nseg := n - 1
+	if closed {
+		nseg = n
+	}
+	ux := make([]float64, nseg)
+	uy := make([]float64, nseg)
+	for i := 0; i != nseg; i++ {
+		p, q := positions[i], positions[(i+1)%n]
+		length := math.Hypot(q[0]-p[0], q[1]-p[1])
+		ux[i], uy[i] = (q[0]-p[0])/length, (q[1]-p[1])/length
+		b.addPiece([][]float64{
+			b.offset(p, uy[i], -ux[i]), b.offset(q, uy[i], -ux[i]),
+			b.offset(q, -uy[i], ux[i]), b.offset(p, -uy[i], ux[i]),
+		})
+	}
+	for i := 1; i != nseg; i++ {
+		b.addJoin(positions[i], ux[i-1], uy[i-1], ux[i], uy[i])
+	}
+	if closed {
+		b.addJoin(positions[0], ux[nseg-1], uy[nseg-1], ux[0], uy[0])
+	} else {
+		b.addCap(positions[0], -ux[0], -uy[0])
+		b.addCap(positions[n-1], ux[nseg-1], uy[nseg-1])
+	}
+}
+
+// areasGeo wraps polygons as a Polygon if there is one, or a MultiPolygon
+// otherwise
+func areasGeo(polygons [][][][]float64) *Geo {
+	if len(polygons) == 1 {
+		return &Geo{Type: "Polygon", Polygon: &Polygon{Coordinates: polygons[0]}}
+	}
+	if polygons == nil {
+		polygons = [][][][]float64{}
+	}
+	return &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: polygons}}
+}
+
+// bufferComponents returns the planar buffer of the components
+func bufferComponents(c *components, distance float64, opts BufferOptions) [][][][]float64 {
+	b := &bufferBuilder{
+		distance:   math.Abs(distance),
+		join:       opts.Join,
+		cap:        opts.Cap,
+		quadSegs:   opts.QuadrantSegments,
+		mitreLimit: opts.MitreLimit,
+	}
+	for _, poly := range c.polygons {
+		b.edges = appendPolygonEdges(b.edges, poly, 0)
+	}
+	if distance < 0 {
+		// erode polygons by removing the buffer of their boundaries
+		for _, poly := range c.polygons {
+			for _, ring := range poly {
+				b.addPath(ring, true)
+			}
+		}
+		return overlayAreas(b.edges, func(w [2]int) bool { return w[0] > 0 && w[1] == 0 })
+	}
+	if distance > 0 {
+		for _, pos := range c.points {
+			b.addPoint(pos)
+		}
+		for _, line := range c.lines {
+			b.addPath(line, false)
+		}
+		for _, poly := range c.polygons {
+			for _, ring := range poly {
+				b.addPath(ring, true)
+			}
+		}
+	}
+	return overlayAreas(b.edges, func(w [2]int) bool { return w[0] > 0 || w[1] > 0 })
+}
+
+// mapPositions returns a copy of the components with f applied to every
+// position
+func (c *components) mapPositions(f func([]float64) []float64) *components {
+	out := &components{points: mapPath(c.points, f)}
+	for _, line := range c.lines {
+		out.lines = append(out.lines, mapPath(line, f))
+	}
+	for _, poly := range c.polygons {
+		out.polygons = append(out.polygons, mapPaths(poly, f))
+	}
+	return out
+}
+
+func buffer(g *Geo, distance float64, opts BufferOptions) (*Geo, error) {
+	if math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return nil, errors.New("buffer distance must be finite")
+	}
+	if opts.QuadrantSegments < 0 || opts.MitreLimit < 0 {
+		return nil, errors.New("buffer options must not be negative")
+	}
+	if opts.Join < JoinRound || opts.Join > JoinBevel {
+		return nil, fmt.Errorf("unknown join style: %d", opts.Join)
+	}
+	if opts.Cap < CapRound || opts.Cap > CapSquare {
+		return nil, fmt.Errorf("unknown cap style: %d", opts.Cap)
+	}
+	if opts.QuadrantSegments == 0 {
+		opts.QuadrantSegments = defaultQuadrantSegments
+	}
+	if opts.MitreLimit == 0 {
+		opts.MitreLimit = defaultMitreLimit
+	}
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return nil, err
+	}
+	if c.dimension() == -1 {
+		return nil, errors.New("buffer of empty geometry")
+	}
+	if !opts.Geodesic {
+		return areasGeo(bufferComponents(c, distance, opts)), nil
+	}
+
+	// buffer in an azimuthal equidistant projection about the centroid
+	centre, err := centroid(g)
+	if err != nil {
+		return nil, err
+	}
+	lon0, lat0 := centre.Coordinates[0], centre.Coordinates[1]
+	projected := c.mapPositions(func(pos []float64) []float64 {
+		s12, azi1, _ := geod.WGS84.Inverse(lat0, lon0, pos[1], pos[0])
+		sin, cos := math.Sincos(azi1 * math.Pi / 180)
+		return []float64{s12 * sin, s12 * cos}
+	})
+	polygons := bufferComponents(projected, distance, opts)
+	for _, poly := range polygons {
+		for _, ring := range poly {
+			for i, pos := range ring {
+				azi := math.Atan2(pos[0], pos[1]) * 180 / math.Pi
+				lat, lon, _ := geod.WGS84.Direct(lat0, lon0, azi, math.Hypot(pos[0], pos[1]))
+				ring[i] = []float64{lon, lat}
+			}
+		}
+	}
+	return areasGeo(polygons), nil
+}
+
+// Buffer returns the area within distance of the Point, as a circle or a
+// square depending on the cap style. See Geo.Buffer.
+func (g *Point) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "Point", Point: g}, distance, opts)
+}
+
+// Buffer returns the area within distance of the LineString. See
+// Geo.Buffer.
+func (g *LineString) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "LineString", LineString: g}, distance, opts)
+}
+
+// Buffer returns the Polygon grown by distance, or shrunk if distance is
+// negative. See Geo.Buffer.
+func (g *Polygon) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "Polygon", Polygon: g}, distance, opts)
+}
+
+// Buffer returns the area within distance of the MultiPoint. See
+// Geo.Buffer.
+func (g *MultiPoint) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "MultiPoint", MultiPoint: g}, distance, opts)
+}
+
+// Buffer returns the area within distance of the MultiLineString. See
+// Geo.Buffer.
+func (g *MultiLineString) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "MultiLineString", MultiLineString: g}, distance, opts)
+}
+
+// Buffer returns the MultiPolygon grown by distance, or shrunk if distance
+// is negative. See Geo.Buffer.
+func (g *MultiPolygon) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "MultiPolygon", MultiPolygon: g}, distance, opts)
+}
+
+// Buffer returns the union of the buffers of the members of the
+// GeometryCollection. See Geo.Buffer.
+func (coll *GeometryCollection) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&Geo{Type: "GeometryCollection", GeometryCollection: coll}, distance, opts)
+}
+
+// Buffer returns the buffer of the Feature geometry. See Geo.Buffer.
+func (f *Feature) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(&f.Geometry, distance, opts)
+}
+
+// Buffer returns the area within distance of any geometry, Feature or
+// FeatureCollection, as a Polygon or a MultiPolygon. Overlapping parts are
+// merged, and the result has counter-clockwise shells and clockwise holes.
+//
+// A negative distance shrinks polygons and removes any part narrower than
+// twice the distance, and gives nothing for points and lines. A distance of
+// zero returns the union of the polygons. When nothing remains the result is
+// an empty MultiPolygon.
+//
+// With opts.Geodesic the geometry is buffered in an azimuthal equidistant
+// projection centred on its centroid, so the distance is in metres and is
+// exact from the centre and accurate to a small fraction for geometries
+// spanning up to several hundred kilometres.
+//
+// The computation is quadratic in the number of vertices in the worst case.
+func (g *Geo) Buffer(distance float64, opts BufferOptions) (*Geo, error) {
+	return buffer(g, distance, opts)
+}
@@ -0,0 +1,241 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// polygonArea8 is the area of the regular 32-gon inscribed in a unit
+// circle, which approximates circles with the default quadrant segments
+var polygonArea8 = 16 * math.Sin(math.Pi/16)
+
+func bufferArea(t *testing.T, g *Geo, distance float64, opts BufferOptions) float64 {
+	buf, err := g.Buffer(distance, opts)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+		return 0
+	}
+	switch buf.Type {
+	case "Polygon":
+		for i, ring := range buf.Polygon.Coordinates {
+			if isCounterClockwise(ring) != (i == 0) {
+				fmt.Println("ring", i, "has the wrong winding")
+				t.Fail()
+			}
+		}
+	case "MultiPolygon":
+		for _, poly := range buf.MultiPolygon.Coordinates {
+			for i, ring := range poly {
+				if isCounterClockwise(ring) != (i == 0) {
+					fmt.Println("ring", i, "has the wrong winding")
+					t.Fail()
+				}
+			}
+		}
+	}
+	return buf.Area()
+}
+
+func expectArea(t *testing.T, name string, area, expected float64) {
+	if math.Abs(area-expected) > 1e-9 {
+		fmt.Println(name)
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestBufferPoint(t *testing.T) {
+	pt := pointGeo(3, 4)
+	expectArea(t, "round", bufferArea(t, pt, 2, BufferOptions{}), 4*polygonArea8)
+	expectArea(t, "square", bufferArea(t, pt, 2, BufferOptions{Cap: CapSquare}), 16)
+
+	buf, err := pt.Buffer(2, BufferOptions{QuadrantSegments: 2})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "Polygon" || len(buf.Polygon.Coordinates[0]) != 9 {
+		fmt.Println("recieved    ", buf.Polygon.Coordinates)
+		t.Fail()
+	}
+
+	buf, err = pt.Buffer(2, BufferOptions{Cap: CapFlat})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "MultiPolygon" || len(buf.MultiPolygon.Coordinates) != 0 {
+		t.Fail()
+	}
+}
+
+func TestBufferLineCaps(t *testing.T) {
+	ls := lineGeo([]float64{0, 0}, []float64{10, 0})
+	expectArea(t, "flat", bufferArea(t, ls, 1, BufferOptions{Cap: CapFlat}), 20)
+	expectArea(t, "square", bufferArea(t, ls, 1, BufferOptions{Cap: CapSquare}), 24)
+	expectArea(t, "round", bufferArea(t, ls, 1, BufferOptions{}), 20+polygonArea8)
+}
+
+func TestBufferLineJoins(t *testing.T) {
+	ls := lineGeo([]float64{0, 0}, []float64{10, 0}, []float64{10, 10})
+	// two 20 unit rectangles overlapping by 1, plus the outer corner
+	expectArea(t, "mitre", bufferArea(t, ls, 1, BufferOptions{Cap: CapFlat, Join: JoinMitre}), 40)
+	expectArea(t, "bevel", bufferArea(t, ls, 1, BufferOptions{Cap: CapFlat, Join: JoinBevel}), 39.5)
+	expectArea(t, "round", bufferArea(t, ls, 1, BufferOptions{Cap: CapFlat}), 39+polygonArea8/4)
+
+	// a sharp spike is cut at the mitre limit
+	spike := lineGeo([]float64{0, 0}, []float64{10, 0}, []float64{0, 1})
+	buf, err := spike.Buffer(1, BufferOptions{Cap: CapFlat, Join: JoinMitre, MitreLimit: 2})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	// the clipping line is 2 units out along the bisector, which is tilted
+	// slightly, so that its far corner reaches just beyond x = 12
+	xmax := math.Inf(-1)
+	for _, pos := range buf.Polygon.Coordinates[0] {
+		xmax = math.Max(xmax, pos[0])
+	}
+	if math.Abs(xmax-12.0424237) > 1e-6 {
+		fmt.Println("recieved    ", xmax)
+		fmt.Println("but expected", 12.0424237)
+		t.Fail()
+	}
+}
+
+func TestBufferClosedLine(t *testing.T) {
+	ls := lineGeo([]float64{0, 0}, []float64{10, 0}, []float64{10, 10}, []float64{0, 10}, []float64{0, 0})
+	buf, err := ls.Buffer(1, BufferOptions{Join: JoinMitre})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "Polygon" || len(buf.Polygon.Coordinates) != 2 {
+		t.Fatal("expected a Polygon with a hole")
+	}
+	expectArea(t, "ring", buf.Area(), 144-64)
+}
+
+func TestBufferSelfIntersectingLine(t *testing.T) {
+	ls := lineGeo([]float64{0, 0}, []float64{10, 0}, []float64{5, 5}, []float64{5, -5})
+	buf, err := ls.Buffer(0.5, BufferOptions{})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", buf.Type)
+	}
+	// the area is less than that of the separate segment buffers
+	length := ls.Length()
+	if area := buf.Area(); area <= 0 || area >= length+math.Pi*0.25 {
+		fmt.Println("recieved    ", area)
+		t.Fail()
+	}
+	for _, pos := range ls.LineString.Coordinates {
+		if pointInPolygon(pos, buf.Polygon.Coordinates) != 1 {
+			fmt.Println(pos, "is not inside the buffer")
+			t.Fail()
+		}
+	}
+}
+
+func TestBufferPolygon(t *testing.T) {
+	sq := polygonGeo(square(0, 0, 10))
+	expectArea(t, "round", bufferArea(t, sq, 1, BufferOptions{}), 140+polygonArea8)
+	expectArea(t, "mitre", bufferArea(t, sq, 1, BufferOptions{Join: JoinMitre}), 144)
+	expectArea(t, "zero", bufferArea(t, sq, 0, BufferOptions{}), 100)
+	expectArea(t, "negative", bufferArea(t, sq, -1, BufferOptions{}), 64)
+
+	holed := polygonGeo(square(0, 0, 10), [][]float64{{3, 3}, {3, 7}, {7, 7}, {7, 3}, {3, 3}})
+	buf, err := holed.Buffer(-1, BufferOptions{})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "Polygon" || len(buf.Polygon.Coordinates) != 2 {
+		t.Fatal("expected a Polygon with a hole")
+	}
+	// the hole grows by one unit with rounded corners
+	expectArea(t, "holed", bufferArea(t, holed, -1, BufferOptions{}), 64-(32+polygonArea8))
+
+	expectArea(t, "eroded", bufferArea(t, polygonGeo(square(0, 0, 2)), -1.5, BufferOptions{}), 0)
+}
+
+func TestBufferNegativeSplits(t *testing.T) {
+	// two squares joined by a narrow corridor
+	dumbbell := polygonGeo([][]float64{
+		{0, 0}, {4, 0}, {4, 1.5}, {6, 1.5}, {6, 0}, {10, 0},
+		{10, 4}, {6, 4}, {6, 2.5}, {4, 2.5}, {4, 4}, {0, 4}, {0, 0},
+	})
+	buf, err := dumbbell.Buffer(-0.6, BufferOptions{Join: JoinMitre})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if buf.Type != "MultiPolygon" || len(buf.MultiPolygon.Coordinates) != 2 {
+		t.Fatal("expected two parts")
+	}
+	expectArea(t, "dumbbell", buf.Area(), 2*2.8*2.8)
+}
+
+func TestBufferOverlappingPolygons(t *testing.T) {
+	mp := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{
+		{square(0, 0, 2)}, {square(1, 1, 2)},
+	}}}
+	expectArea(t, "union", bufferArea(t, mp, 0, BufferOptions{}), 7)
+}
+
+func TestBufferGeodesic(t *testing.T) {
+	pt := pointGeo(10, 60)
+	buf, err := pt.Buffer(1000, BufferOptions{Geodesic: true})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	for _, pos := range buf.Polygon.Coordinates[0] {
+		s12, _, _ := geod.WGS84.Inverse(60, 10, pos[1], pos[0])
+		if math.Abs(s12-1000) > 1e-6 {
+			fmt.Println("recieved    ", s12)
+			fmt.Println("but expected", 1000)
+			t.Fail()
+			break
+		}
+	}
+	if area := buf.GeodesicArea(); math.Abs(area/(1e6*polygonArea8)-1) > 1e-4 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", 1e6*polygonArea8)
+		t.Fail()
+	}
+
+	ls := lineGeo([]float64{10, 60}, []float64{10.1, 60})
+	buf, err = ls.Buffer(500, BufferOptions{Cap: CapFlat, Geodesic: true})
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := 1000 * ls.GeodesicLength()
+	if area := buf.GeodesicArea(); math.Abs(area/expected-1) > 1e-3 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestBufferInvalidOptions(t *testing.T) {
+	pt := pointGeo(0, 0)
+	if _, err := pt.Buffer(1, BufferOptions{QuadrantSegments: -1}); err == nil {
+		t.Fail()
+	}
+	if _, err := pt.Buffer(1, BufferOptions{Join: JoinStyle(7)}); err == nil {
+		t.Fail()
+	}
+	if _, err := pt.Buffer(math.NaN(), BufferOptions{}); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,327 @@
+/* functions for computing centroids and interior points of geometries */
+package geojson
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// centroidSum accumulates weighted position sums for each dimension, so that
+// lower-dimensional parts can be ignored when higher-dimensional parts exist
+type centroidSum struct {
+	area, ax, ay   float64
+	length, lx, ly float64
+	count, px, py  float64
+}
+
+func (s *centroidSum) addPoint(pos []float64) {
+	s.count++
+	s.px += pos[0]
+	s.py += pos[1]
+}
+
+func (s *centroidSum) addLine(line [][]float64) {
+	var length float64
+	for i := 1; i < len(line); i++ {
+		seg := math.Hypot(line[i][0]-line[i-1][0], line[i][1]-line[i-1][1])
+		length += seg
+		s.lx += seg * 0.5 * (line[i][0] + line[i-1][0])
+		s.ly += seg * 0.5 * (line[i][1] + line[i-1][1])
+	}
+	s.length += length
+	if length == 0 {
+		for _, pos := range line {
+			s.addPoint(pos)
+		}
+	}
+}
+
+// addRing adds the area-weighted centroid of a ring, with sign +1 for shells
+// and -1 for holes. Coordinates are taken relative to the first vertex to
+// limit round-off.
+func (s *centroidSum) addRing(ring [][]float64, sign float64) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+	x0, y0 := ring[0][0], ring[0][1]
+	var a, cx, cy float64
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		xj, yj := ring[j][0]-x0, ring[j][1]-y0
+		xi, yi := ring[i][0]-x0, ring[i][1]-y0
+		c := xj*yi - xi*yj
+		a += c
+		cx += (xj + xi) * c
+		cy += (yj + yi) * c
+	}
+	if a == 0 {
+		return 0
+	}
+	area := math.Abs(a / 2)
+	s.area += sign * area
+	s.ax += sign * area * (cx/(3*a) + x0)
+	s.ay += sign * area * (cy/(3*a) + y0)
+	return area
+}
+
+func (s *centroidSum) addPolygon(rings [][][]float64) {
+	var area float64
+	for i, ring := range rings {
+		if i == 0 {
+			area += s.addRing(ring, 1)
+		} else {
+			area -= s.addRing(ring, -1)
+		}
+	}
+	if area == 0 {
+		// collapsed polygons contribute their boundary instead
+		for _, ring := range rings {
+			s.addLine(ring)
+		}
+	}
+}
+
+func (s *centroidSum) point() (*Point, error) {
+	switch {
+	case s.area != 0:
+		return &Point{Coordinates: []float64{s.ax / s.area, s.ay / s.area}}, nil
+	case s.length != 0:
+		return &Point{Coordinates: []float64{s.lx / s.length, s.ly / s.length}}, nil
+	case s.count != 0:
+		return &Point{Coordinates: []float64{s.px / s.count, s.py / s.count}}, nil
+	}
+	return nil, errors.New("centroid of empty geometry")
+}
+
+func centroid(g *Geo) (*Point, error) {
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return nil, err
+	}
+	s := new(centroidSum)
+	for _, pos := range c.points {
+		s.addPoint(pos)
+	}
+	for _, line := range c.lines {
+		s.addLine(line)
+	}
+	for _, poly := range c.polygons {
+		s.addPolygon(poly)
+	}
+	return s.point()
+}
+
+// Centroid returns the Point itself
+func (g *Point) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "Point", Point: g})
+}
+
+// Centroid returns the length-weighted centre of the LineString
+func (g *LineString) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "LineString", LineString: g})
+}
+
+// Centroid returns the area-weighted centre of the Polygon, accounting for
+// holes
+func (g *Polygon) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// Centroid returns the mean position of the MultiPoint
+func (g *MultiPoint) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "MultiPoint", MultiPoint: g})
+}
+
+// Centroid returns the length-weighted centre of the MultiLineString
+func (g *MultiLineString) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "MultiLineString", MultiLineString: g})
+}
+
+// Centroid returns the area-weighted centre of the MultiPolygon
+func (g *MultiPolygon) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// Centroid returns the centroid of the highest-dimension members of the
+// GeometryCollection, so that points and lines do not pull the centroid of
+// polygons
+func (coll *GeometryCollection) Centroid() (*Point, error) {
+	return centroid(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+}
+
+// Centroid returns the centroid of the Feature geometry
+func (f *Feature) Centroid() (*Point, error) {
+	return centroid(&f.Geometry)
+}
+
+// Centroid returns the centroid of any geometry, Feature or FeatureCollection
+func (g *Geo) Centroid() (*Point, error) {
+	return centroid(g)
+}
+
+// scanLineY returns a y ordinate near the middle of the polygons that does
+// not pass through any vertex
+func scanLineY(polygons [][][][]float64) float64 {
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	for _, poly := range polygons {
+		for _, pos := range poly[0] {
+			ymin = math.Min(ymin, pos[1])
+			ymax = math.Max(ymax, pos[1])
+		}
+	}
+	centre := 0.5 * (ymin + ymax)
+	lo, hi := ymin, ymax
+	for _, poly := range polygons {
+		for _, ring := range poly {
+			for _, pos := range ring {
+				if pos[1] <= centre && pos[1] > lo {
+					lo = pos[1]
+				} else if pos[1] > centre && pos[1] < hi {
+					hi = pos[1]
+				}
+			}
+		}
+	}
+	return 0.5 * (lo + hi)
+}
+
+// interiorPointPolygon returns the midpoint of the widest interior section
+// of a horizontal line through a polygon, and the width of that section
+func interiorPointPolygon(rings [][][]float64) ([]float64, float64) {
+	y := scanLineY([][][][]float64{rings})
+	crossings := []float64{}
+	for _, ring := range rings {
+		n := len(ring)
+		for i, j := 0, n-1; i < n; j, i = i, i+1 {
+			a, b := ring[j], ring[i]
+			if (a[1] > y) != (b[1] > y) {
+				crossings = append(crossings, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	sort.Float64s(crossings)
+	var best []float64
+	width := -1.0
+	for k := 0; k+1 < len(crossings); k += 2 {
+		if w := crossings[k+1] - crossings[k]; w > width {
+			width = w
+			best = []float64{0.5 * (crossings[k] + crossings[k+1]), y}
+		}
+	}
+	return best, width
+}
+
+// nearestPosition returns the candidate closest to pos
+func nearestPosition(pos []float64, candidates [][]float64) []float64 {
+	var best []float64
+	dmin := math.Inf(1)
+	for _, c := range candidates {
+		if d := math.Hypot(c[0]-pos[0], c[1]-pos[1]); d < dmin {
+			dmin = d
+			best = c
+		}
+	}
+	return best
+}
+
+func pointOnSurface(g *Geo) (*Point, error) {
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return nil, err
+	}
+
+	var best []float64
+	width := 0.0
+	for _, poly := range c.polygons {
+		if len(poly) == 0 || polygonArea(poly) == 0 {
+			continue
+		}
+		if pos, w := interiorPointPolygon(poly); pos != nil && w > width {
+			best, width = pos, w
+		}
+	}
+	if best != nil {
+		return &Point{Coordinates: best}, nil
+	}
+
+	// no polygon has any area, so fall back to lines and then points
+	ctr, err := centroid(g)
+	if err != nil {
+		return nil, err
+	}
+	lines := c.lines
+	for _, poly := range c.polygons {
+		lines = append(lines, poly...)
+	}
+	interior := [][]float64{}
+	endpoints := [][]float64{}
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		if len(line) > 2 {
+			interior = append(interior, line[1:len(line)-1]...)
+		}
+		endpoints = append(endpoints, line[0], line[len(line)-1])
+	}
+	if len(interior) != 0 {
+		best = nearestPosition(ctr.Coordinates, interior)
+	} else if len(endpoints) != 0 {
+		best = nearestPosition(ctr.Coordinates, endpoints)
+	} else {
+		best = nearestPosition(ctr.Coordinates, c.points)
+	}
+	return &Point{Coordinates: []float64{best[0], best[1]}}, nil
+}
+
+// PointOnSurface returns the Point itself
+func (g *Point) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "Point", Point: g})
+}
+
+// PointOnSurface returns an interior vertex of the LineString close to its
+// centroid, or an endpoint if there are no interior vertices
+func (g *LineString) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "LineString", LineString: g})
+}
+
+// PointOnSurface returns a point guaranteed to lie in the interior of the
+// Polygon, even when it is concave or has holes
+func (g *Polygon) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// PointOnSurface returns the member of the MultiPoint closest to its centroid
+func (g *MultiPoint) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "MultiPoint", MultiPoint: g})
+}
+
+// PointOnSurface returns a vertex of the MultiLineString close to its
+// centroid
+func (g *MultiLineString) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "MultiLineString", MultiLineString: g})
+}
+
+// PointOnSurface returns a point guaranteed to lie in the interior of one of
+// the polygons of the MultiPolygon, preferring the widest
+func (g *MultiPolygon) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// PointOnSurface returns a point on the highest-dimension members of the
+// GeometryCollection
+func (coll *GeometryCollection) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+}
+
+// PointOnSurface returns a point on the Feature geometry
+func (f *Feature) PointOnSurface() (*Point, error) {
+	return pointOnSurface(&f.Geometry)
+}
+
+// PointOnSurface returns a point guaranteed to lie on any geometry, Feature
+// or FeatureCollection
+func (g *Geo) PointOnSurface() (*Point, error) {
+	return pointOnSurface(g)
+}
@@ -0,0 +1,143 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// horseshoe is concave, with a centroid that falls in the gap between its arms
+var horseshoe = [][][]float64{{
+	{0, 0}, {10, 0}, {10, 10}, {8, 10}, {8, 2}, {2, 2}, {2, 10}, {0, 10}, {0, 0},
+}}
+
+func TestCentroidPolygon(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{0, 0}, {0, 4}, {2, 4}, {2, 0}, {0, 0}},
+	}}
+	// the left half is a hole, so the centroid moves right
+	pt, err := poly.Centroid()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if pt.Coordinates[0] != 3 || pt.Coordinates[1] != 2 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+}
+
+func TestCentroidLineString(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {2, 0}, {2, 1}}}
+	pt, err := ls.Centroid()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if math.Abs(pt.Coordinates[0]-4.0/3) > 1e-12 || math.Abs(pt.Coordinates[1]-1.0/6) > 1e-12 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+}
+
+func TestCentroidGeometryCollection(t *testing.T) {
+	coll := &GeometryCollection{Geometries: []*Geo{
+		{Type: "Point", Point: &Point{Coordinates: []float64{100, 100}}},
+		{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{50, 50}, {60, 60}}}},
+		{Type: "Polygon", Polygon: &Polygon{Coordinates: [][][]float64{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}},
+	}}
+	// only the polygon contributes
+	pt, err := coll.Centroid()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if pt.Coordinates[0] != 1 || pt.Coordinates[1] != 1 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+
+	if _, err = (&MultiPoint{}).Centroid(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPointOnSurfaceConcave(t *testing.T) {
+	poly := &Polygon{Coordinates: horseshoe}
+	ctr, _ := poly.Centroid()
+	if pointInPolygon(ctr.Coordinates, horseshoe) > 0 {
+		t.Fatal("test polygon centroid should be outside")
+	}
+	pt, err := poly.PointOnSurface()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if pointInPolygon(pt.Coordinates, horseshoe) != 1 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+}
+
+func TestPointOnSurfaceHole(t *testing.T) {
+	mpoly := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{20, 20}, {21, 20}, {21, 21}, {20, 21}, {20, 20}}},
+		{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			{{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}},
+		},
+	}}
+	pt, err := mpoly.PointOnSurface()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	inside := false
+	for _, poly := range mpoly.Coordinates {
+		if pointInPolygon(pt.Coordinates, poly) == 1 {
+			inside = true
+		}
+	}
+	if !inside {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+}
+
+func TestPointOnSurfaceLine(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {1, 5}, {2, 0}, {3, 5}}}
+	pt, err := ls.PointOnSurface()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if pt.Coordinates[0] != 1 && pt.Coordinates[0] != 2 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+}
+
+func TestPoleOfInaccessibility(t *testing.T) {
+	poly := &Polygon{Coordinates: horseshoe}
+	pt, err := poly.PoleOfInaccessibility(0.01)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if pointInPolygon(pt.Coordinates, horseshoe) != 1 {
+		fmt.Println("recieved", pt)
+		t.Fail()
+	}
+	// the widest part of the horseshoe is its base, of half-width 1, but the
+	// corners give a little more room
+	d := signedOutlineDistance(pt.Coordinates, [][][][]float64{horseshoe})
+	if d < 1 {
+		fmt.Println("recieved", pt, "at distance", d)
+		t.Fail()
+	}
+
+	if _, err = poly.PoleOfInaccessibility(0); err == nil {
+		t.Fail()
+	}
+}
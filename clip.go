@@ -0,0 +1,327 @@
+/* functions for clipping geometries to a rectangle */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+)
+
+// clipRect returns the extent of a Bbox for clipping
+func clipRect(bb *Bbox) ([4]float64, error) {
+	if bb == nil {
+		return [4]float64{}, errors.New("nil Bbox")
+	}
+	if bb.xmin > bb.xmax || bb.ymin > bb.ymax {
+		return [4]float64{}, errors.New("cannot clip to an inverted Bbox")
+	}
+	return [4]float64{bb.xmin, bb.ymin, bb.xmax, bb.ymax}, nil
+}
+
+func insideRect(pos []float64, rect [4]float64) bool {
+	return pos[0] >= rect[0] && pos[0] <= rect[2] && pos[1] >= rect[1] && pos[1] <= rect[3]
+}
+
+// interpolate returns the position a fraction t of the way from p0 to p1,
+// in every dimension the two share
+func interpolate(p0, p1 []float64, t float64) []float64 {
+	n := len(p0)
+	if len(p1) < n {
+		n = len(p1)
+	}
+	pos := make([]float64, n)
+	for i := range pos {
+		switch t {
+		case 0:
+			pos[i] = p0[i]
+		case 1:
+			pos[i] = p1[i]
+		default:
+			pos[i] = p0[i] + t*(p1[i]-p0[i])
+		}
+	}
+	return pos
+}
+
+// clipLine returns the parts of a line within the rectangle. A line that
+// leaves and re-enters gives several parts, and parts that only touch the
+// rectangle at a single position are dropped.
+func clipLine(line [][]float64, rect [4]float64) [][][]float64 {
+	var parts [][][]float64
+	var part [][]float64
+	finish := func() {
+		if len(part) > 1 {
+			parts = append(parts, part)
+		}
+		part = nil
+	}
+	if len(line) == 1 && insideRect(line[0], rect) {
+		return [][][]float64{{line[0]}}
+	}
+	for i := 1; i < len(line); i++ {
+		t0, t1, ok := liangBarsky(line[i-1], line[i], rect)
+		if !ok {
+			finish()
+			continue
+		}
+		start := interpolate(line[i-1], line[i], t0)
+		if t0 > 0 || len(part) == 0 {
+			finish()
+			part = [][]float64{start}
+		}
+		end := interpolate(line[i-1], line[i], t1)
+		if !samePosition(end, part[len(part)-1]) {
+			part = append(part, end)
+		}
+		if t1 < 1 {
+			finish()
+		}
+	}
+	finish()
+	return parts
+}
+
+// clipRing clips an unclosed ring to the rectangle using the
+// Sutherland-Hodgman algorithm. Where the ring leaves and re-enters, the
+// result runs along the rectangle edge between the crossings.
+func clipRing(ring [][]float64, rect [4]float64) [][]float64 {
+	// each edge of the rectangle as an inside test and an intersection
+	type clipEdge struct {
+		inside func([]float64) bool
+		cross  func(p, q []float64) []float64
+	}
+	atX := func(x float64) func(p, q []float64) []float64 {
+		return func(p, q []float64) []float64 {
+			pos := interpolate(p, q, (x-p[0])/(q[0]-p[0]))
+			pos[0] = x
+			return pos
+		}
+	}
+	atY := func(y float64) func(p, q []float64) []float64 {
+		return func(p, q []float64) []float64 {
+			pos := interpolate(p, q, (y-p[1])/(q[1]-p[1]))
+			pos[1] = y
+			return pos
+		}
+	}
+	edges := []clipEdge{
+		{func(p []float64) bool { return p[0] >= rect[0] }, atX(rect[0])},
+		{func(p []float64) bool { return p[0] <= rect[2] }, atX(rect[2])},
+		{func(p []float64) bool { return p[1] >= rect[1] }, atY(rect[1])},
+		{func(p []float64) bool { return p[1] <= rect[3] }, atY(rect[3])},
+	}
+	out := ring
+	for _, edge := range edges {
+		in := out
+		out = nil
+		for i := range in {
+			cur := in[i]
+			prev := in[(i+len(in)-1)%len(in)]
+			if edge.inside(cur) {
+				if !edge.inside(prev) {
+					out = append(out, edge.cross(prev, cur))
+				}
+				out = append(out, cur)
+			} else if edge.inside(prev) {
+				out = append(out, edge.cross(prev, cur))
+			}
+		}
+	}
+	return out
+}
+
+// clipPolygon returns the parts of a polygon within the rectangle. Polygons
+// that cross the rectangle edge are rebuilt with counter-clockwise shells and
+// clockwise holes, separating parts that Sutherland-Hodgman joins along the
+// rectangle edge.
+func clipPolygon(rings [][][]float64, rect [4]float64) [][][][]float64 {
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return nil
+	}
+	inside := true
+	for _, ring := range rings {
+		for _, pos := range ring {
+			if !insideRect(pos, rect) {
+				inside = false
+			}
+		}
+	}
+	if inside {
+		return [][][][]float64{rings}
+	}
+
+	var edges []overlayEdge
+	for i, ring := range rings {
+		clipped := clipRing(openRing(ring), rect)
+		if len(clipped) < 3 || ringArea(clipped) == 0 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+		edges = appendRingEdges(edges, clipped, 0, i == 0)
+	}
+	return overlayAreas(edges, func(w [2]int) bool { return w[0] > 0 })
+}
+
+// isEmpty returns true if a geometry has no positions
+func (g *Geo) isEmpty() bool {
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return false
+	}
+	return c.dimension() == -1
+}
+
+func clipGeo(g *Geo, rect [4]float64) (*Geo, error) {
+	switch g.Type {
+	case "Point":
+		pt := g.Point
+		if len(pt.Coordinates) >= 2 && insideRect(pt.Coordinates, rect) {
+			return &Geo{Type: "Point", Point: &Point{pt.CRSReferencable, pt.Coordinates}}, nil
+		}
+		return &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{pt.CRSReferencable, [][]float64{}}}, nil
+	case "MultiPoint":
+		mp := &MultiPoint{g.MultiPoint.CRSReferencable, [][]float64{}}
+		for _, pos := range g.MultiPoint.Coordinates {
+			if insideRect(pos, rect) {
+				mp.Coordinates = append(mp.Coordinates, pos)
+			}
+		}
+		return &Geo{Type: "MultiPoint", MultiPoint: mp}, nil
+	case "LineString":
+		parts := clipLine(g.LineString.Coordinates, rect)
+		if len(parts) == 1 {
+			return &Geo{Type: "LineString", LineString: &LineString{g.LineString.CRSReferencable, parts[0]}}, nil
+		}
+		if parts == nil {
+			parts = [][][]float64{}
+		}
+		return &Geo{Type: "MultiLineString",
+			MultiLineString: &MultiLineString{g.LineString.CRSReferencable, parts}}, nil
+	case "MultiLineString":
+		mls := &MultiLineString{g.MultiLineString.CRSReferencable, [][][]float64{}}
+		for _, line := range g.MultiLineString.Coordinates {
+			mls.Coordinates = append(mls.Coordinates, clipLine(line, rect)...)
+		}
+		return &Geo{Type: "MultiLineString", MultiLineString: mls}, nil
+	case "Polygon":
+		parts := clipPolygon(g.Polygon.Coordinates, rect)
+		if len(parts) == 1 {
+			return &Geo{Type: "Polygon", Polygon: &Polygon{g.Polygon.CRSReferencable, parts[0]}}, nil
+		}
+		if parts == nil {
+			parts = [][][][]float64{}
+		}
+		return &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{g.Polygon.CRSReferencable, parts}}, nil
+	case "MultiPolygon":
+		mp := &MultiPolygon{g.MultiPolygon.CRSReferencable, [][][][]float64{}}
+		for _, poly := range g.MultiPolygon.Coordinates {
+			mp.Coordinates = append(mp.Coordinates, clipPolygon(poly, rect)...)
+		}
+		return &Geo{Type: "MultiPolygon", MultiPolygon: mp}, nil
+	case "GeometryCollection":
+		coll := &GeometryCollection{g.GeometryCollection.CRSReferencable, []*Geo{}}
+		for _, member := range g.GeometryCollection.Geometries {
+			clipped, err := clipGeo(member, rect)
+			if err != nil {
+				return nil, err
+			}
+			if !clipped.isEmpty() {
+				coll.Geometries = append(coll.Geometries, clipped)
+			}
+		}
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		clipped, err := clipGeo(&g.Feature.Geometry, rect)
+		if err != nil {
+			return nil, err
+		}
+		f := *g.Feature
+		f.Geometry = *clipped
+		return &Geo{Type: "Feature", Feature: &f}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{g.FeatureCollection.CRSReferencable, []Feature{}}
+		for _, f := range g.FeatureCollection.Features {
+			clipped, err := clipGeo(&f.Geometry, rect)
+			if err != nil {
+				return nil, err
+			}
+			if !clipped.isEmpty() {
+				f.Geometry = *clipped
+				coll.Features = append(coll.Features, f)
+			}
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+func clipToBbox(g *Geo, bb *Bbox) (*Geo, error) {
+	rect, err := clipRect(bb)
+	if err != nil {
+		return nil, err
+	}
+	return clipGeo(g, rect)
+}
+
+// ClipToBbox returns the Point if it lies within bb, including its edges,
+// and an empty MultiPoint otherwise
+func (g *Point) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "Point", Point: g}, bb)
+}
+
+// ClipToBbox returns the part of the LineString within bb. The result is a
+// LineString, or a MultiLineString if the line leaves and re-enters bb or
+// misses it entirely.
+func (g *LineString) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "LineString", LineString: g}, bb)
+}
+
+// ClipToBbox returns the part of the Polygon within bb. The result is a
+// Polygon, or a MultiPolygon if clipping splits it into several parts or
+// nothing remains.
+func (g *Polygon) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "Polygon", Polygon: g}, bb)
+}
+
+// ClipToBbox returns a MultiPoint of the positions within bb
+func (g *MultiPoint) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "MultiPoint", MultiPoint: g}, bb)
+}
+
+// ClipToBbox returns a MultiLineString of the parts of the lines within bb
+func (g *MultiLineString) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "MultiLineString", MultiLineString: g}, bb)
+}
+
+// ClipToBbox returns a MultiPolygon of the parts of the polygons within bb
+func (g *MultiPolygon) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "MultiPolygon", MultiPolygon: g}, bb)
+}
+
+// ClipToBbox returns a GeometryCollection of the members clipped to bb,
+// leaving out those that fall entirely outside it
+func (coll *GeometryCollection) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "GeometryCollection", GeometryCollection: coll}, bb)
+}
+
+// ClipToBbox returns a copy of the Feature with its geometry clipped to bb
+func (f *Feature) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "Feature", Feature: f}, bb)
+}
+
+// ClipToBbox returns a FeatureCollection of the Features clipped to bb,
+// leaving out those that fall entirely outside it
+func (coll *FeatureCollection) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(&Geo{Type: "FeatureCollection", FeatureCollection: coll}, bb)
+}
+
+// ClipToBbox returns the part of any geometry, Feature or FeatureCollection
+// within bb, including its edges. Lines are clipped with the Liang-Barsky
+// algorithm and polygons with Sutherland-Hodgman; polygons that cross the
+// edge of bb are rebuilt with counter-clockwise shells and clockwise holes.
+// Geometries keep their type where possible and otherwise become the
+// corresponding Multi type, which is empty when nothing remains.
+func (g *Geo) ClipToBbox(bb *Bbox) (*Geo, error) {
+	return clipToBbox(g, bb)
+}
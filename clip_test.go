@@ -0,0 +1,177 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBboxExtent(t *testing.T) {
+	xmin, ymin, xmax, ymax := NewBbox(-1, -2, 3, 4).Extent()
+	if xmin != -1 || ymin != -2 || xmax != 3 || ymax != 4 {
+		fmt.Println("recieved    ", xmin, ymin, xmax, ymax)
+		t.Fail()
+	}
+}
+
+func TestClipLineString(t *testing.T) {
+	line := &LineString{Coordinates: [][]float64{{-1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 5}}}
+	clipped, err := line.ClipToBbox(NewBbox(0, 0, 2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "LineString" {
+		t.Fatal("expected a LineString, got", clipped.Type)
+	}
+	if len(clipped.LineString.Coordinates) != 2 {
+		fmt.Println("recieved    ", clipped.LineString.Coordinates)
+		t.Fail()
+	}
+
+	// leaving and re-entering splits the line
+	clipped, err = line.ClipToBbox(NewBbox(0, 0, 2, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "MultiLineString" {
+		t.Fatal("expected a MultiLineString, got", clipped.Type)
+	}
+	expected := [][][]float64{{{0, 1}, {2, 1}}, {{2, 3}, {1, 3}, {1, 5}}}
+	if fmt.Sprint(clipped.MultiLineString.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", clipped.MultiLineString.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	// passing outside gives an empty MultiLineString
+	clipped, err = line.ClipToBbox(NewBbox(10, 10, 12, 12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "MultiLineString" || len(clipped.MultiLineString.Coordinates) != 0 {
+		fmt.Println("recieved    ", clipped.Type)
+		t.Fail()
+	}
+}
+
+func TestClipLineStringThirdDimension(t *testing.T) {
+	line := &LineString{Coordinates: [][]float64{{0, 0, 0}, {4, 0, 8}}}
+	clipped, err := line.ClipToBbox(NewBbox(1, -1, 3, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]float64{{1, 0, 2}, {3, 0, 6}}
+	if fmt.Sprint(clipped.LineString.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", clipped.LineString.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestClipPolygonInside(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{1, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}}}}
+	clipped, err := poly.ClipToBbox(NewBbox(0, 0, 4, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "Polygon" || fmt.Sprint(clipped.Polygon.Coordinates) != fmt.Sprint(poly.Coordinates) {
+		fmt.Println("recieved    ", clipped.Type)
+		t.Fail()
+	}
+}
+
+func TestClipPolygonSplit(t *testing.T) {
+	// a U shape whose arms are separated by clipping off its base
+	poly := &Polygon{Coordinates: [][][]float64{{
+		{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0},
+	}}}
+	clipped, err := poly.ClipToBbox(NewBbox(-1, 2, 4, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "MultiPolygon" {
+		t.Fatal("expected a MultiPolygon, got", clipped.Type)
+	}
+	if len(clipped.MultiPolygon.Coordinates) != 2 {
+		fmt.Println("recieved    ", clipped.MultiPolygon.Coordinates)
+		t.Fail()
+	}
+	for _, part := range clipped.MultiPolygon.Coordinates {
+		if !isCounterClockwise(part[0]) || ringArea(part[0]) != 1 {
+			fmt.Println("recieved    ", part)
+			t.Fail()
+		}
+	}
+}
+
+func TestClipPolygonHole(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}},
+	}}
+	clipped, err := poly.ClipToBbox(NewBbox(2, 2, 12, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "Polygon" || len(clipped.Polygon.Coordinates) != 2 {
+		t.Fatal("expected a Polygon with a hole")
+	}
+	if ringArea(clipped.Polygon.Coordinates[0]) != 48 || ringArea(clipped.Polygon.Coordinates[1]) != -4 {
+		fmt.Println("recieved    ", clipped.Polygon.Coordinates)
+		t.Fail()
+	}
+
+	// a hole cut by the box edge opens the shell
+	clipped, err = poly.ClipToBbox(NewBbox(5, 0, 10, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "Polygon" || len(clipped.Polygon.Coordinates) != 1 ||
+		ringArea(clipped.Polygon.Coordinates[0]) != 48 {
+		fmt.Println("recieved    ", clipped.Polygon.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestClipPoint(t *testing.T) {
+	pt := &Point{Coordinates: []float64{5, 5}}
+	clipped, err := pt.ClipToBbox(NewBbox(0, 0, 5, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "Point" {
+		fmt.Println("recieved    ", clipped.Type)
+		t.Fail()
+	}
+	clipped, err = pt.ClipToBbox(NewBbox(0, 0, 4, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clipped.Type != "MultiPoint" || len(clipped.MultiPoint.Coordinates) != 0 {
+		fmt.Println("recieved    ", clipped.Type)
+		t.Fail()
+	}
+}
+
+func TestClipFeatureCollection(t *testing.T) {
+	fc := &FeatureCollection{Features: []Feature{
+		{ID: "a", Geometry: Geo{Type: "Point", Point: &Point{Coordinates: []float64{1, 1}}},
+			Properties: map[string]interface{}{"name": "inside"}},
+		{ID: "b", Geometry: Geo{Type: "Point", Point: &Point{Coordinates: []float64{9, 9}}}},
+	}}
+	clipped, err := fc.ClipToBbox(NewBbox(0, 0, 2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := clipped.FeatureCollection.Features
+	if len(features) != 1 || features[0].ID != "a" || features[0].Properties["name"] != "inside" {
+		fmt.Println("recieved    ", features)
+		t.Fail()
+	}
+}
+
+func TestClipInvertedBbox(t *testing.T) {
+	pt := &Point{Coordinates: []float64{1, 1}}
+	if _, err := pt.ClipToBbox(NewBbox(2, 0, 0, 2)); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,337 @@
+/* functions for interpreting and normalizing CRS members */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NewNamedCRS returns a named CRS member, such as for
+// "urn:ogc:def:crs:EPSG::3857"
+func NewNamedCRS(name string) *CRS {
+	return &CRS{Type: "name", Properties: map[string]string{"name": name}}
+}
+
+// NewLinkedCRS returns a linked CRS member referring to a definition at
+// href, in a format such as "proj4", "ogcwkt" or "esriwkt"
+func NewLinkedCRS(href, linkType string) *CRS {
+	props := map[string]string{"href": href}
+	if linkType != "" {
+		props["type"] = linkType
+	}
+	return &CRS{Type: "link", Properties: props}
+}
+
+// CRSIdentifier is the authority and code of a coordinate reference
+// system, such as EPSG and 4326 or OGC and CRS84
+type CRSIdentifier struct {
+	Authority string
+	Code      string
+	// Legacy is true for the "EPSG:4326" form, which by convention has
+	// longitude and latitude order whatever the authority's definition
+	Legacy bool
+}
+
+func (id CRSIdentifier) String() string {
+	return id.Authority + ":" + id.Code
+}
+
+var (
+	// urn:ogc:def:crs:EPSG::4326 and urn:ogc:def:crs:OGC:1.3:CRS84
+	crsURN = regexp.MustCompile(`(?i)^urn:ogc:def:crs:([a-z0-9]+):[^:]*:([a-z0-9.]+)$`)
+	// http://www.opengis.net/def/crs/EPSG/0/4326
+	crsURI = regexp.MustCompile(`(?i)^https?://www\.opengis\.net/def/crs/([a-z0-9]+)/[^/]*/([a-z0-9.]+)$`)
+	// EPSG:4326
+	crsLegacy = regexp.MustCompile(`(?i)^([a-z]+):([a-z0-9.]+)$`)
+	// http://spatialreference.org/ref/epsg/4326/ and its format variants
+	crsSpatialReference = regexp.MustCompile(`(?i)^https?://(?:www\.)?spatialreference\.org/ref/([a-z]+)/([0-9]+)(?:/[a-z0-9]*)*/?$`)
+)
+
+// ParseCRSName parses the name of a named CRS in the URN, OGC URI or legacy
+// "EPSG:4326" forms. "CRS84" alone is understood as OGC:CRS84.
+func ParseCRSName(name string) (CRSIdentifier, error) {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, "CRS84") {
+		return CRSIdentifier{Authority: "OGC", Code: "CRS84"}, nil
+	}
+	for _, re := range []*regexp.Regexp{crsURN, crsURI, crsLegacy} {
+		if m := re.FindStringSubmatch(name); m != nil {
+			id := CRSIdentifier{Authority: strings.ToUpper(m[1]), Code: m[2], Legacy: re == crsLegacy}
+			if id.Authority == "OGC" {
+				id.Code = strings.ToUpper(id.Code)
+			}
+			return id, nil
+		}
+	}
+	return CRSIdentifier{}, fmt.Errorf("unrecognized CRS name '%s'", name)
+}
+
+// Identifier returns the authority and code of a named CRS, or of a linked
+// CRS whose href is a spatialreference.org or OGC URI
+func (c *CRS) Identifier() (CRSIdentifier, error) {
+	if c == nil {
+		return CRSIdentifier{Authority: "OGC", Code: "CRS84"}, nil
+	}
+	switch strings.ToLower(c.Type) {
+	case "name":
+		name, ok := c.Properties["name"]
+		if !ok {
+			return CRSIdentifier{}, errors.New("named CRS has no name property")
+		}
+		return ParseCRSName(name)
+	case "link":
+		href := strings.TrimSpace(c.Properties["href"])
+		if m := crsSpatialReference.FindStringSubmatch(href); m != nil {
+			return CRSIdentifier{Authority: strings.ToUpper(m[1]), Code: m[2]}, nil
+		}
+		if m := crsURI.FindStringSubmatch(href); m != nil {
+			return CRSIdentifier{Authority: strings.ToUpper(m[1]), Code: m[2]}, nil
+		}
+		return CRSIdentifier{}, fmt.Errorf("linked CRS '%s' has no known identifier", href)
+	}
+	return CRSIdentifier{}, fmt.Errorf("unhandled CRS type: '%s'", c.Type)
+}
+
+// Link returns the href and type of a linked CRS
+func (c *CRS) Link() (href, linkType string, ok bool) {
+	if c == nil || !strings.EqualFold(c.Type, "link") {
+		return "", "", false
+	}
+	href, ok = c.Properties["href"]
+	return href, c.Properties["type"], ok
+}
+
+// AxisOrder is the order of the first two coordinates of positions
+type AxisOrder int
+
+const (
+	// EastNorth is longitude, latitude or easting, northing order
+	EastNorth AxisOrder = iota
+	// NorthEast is latitude, longitude or northing, easting order
+	NorthEast
+)
+
+func (o AxisOrder) String() string {
+	if o == NorthEast {
+		return "NorthEast"
+	}
+	return "EastNorth"
+}
+
+// latitudeFirst are the codes of common EPSG geographic systems, whose
+// definitions have latitude first
+var latitudeFirst = map[int]bool{
+	4019: true, // GRS 1980
+	4148: true, // Hartebeesthoek94
+	4167: true, // NZGD2000
+	4171: true, // RGF93
+	4230: true, // ED50
+	4258: true, // ETRS89
+	4267: true, // NAD27
+	4269: true, // NAD83
+	4275: true, // NTF
+	4277: true, // OSGB 1936
+	4283: true, // GDA94
+	4301: true, // Tokyo
+	4314: true, // DHDN
+	4322: true, // WGS 72
+	4326: true, // WGS 84
+	4490: true, // CGCS2000
+	4612: true, // JGD2000
+	4617: true, // NAD83(CSRS)
+	4674: true, // SIRGAS 2000
+	4759: true, // NAD83(NSRS2007)
+	4937: true, // ETRS89 with height
+	4959: true, // NZGD2000 with height
+	4979: true, // WGS 84 with height
+	6318: true, // NAD83(2011)
+	6668: true, // JGD2011
+	7843: true, // GDA2020 with height
+	7844: true, // GDA2020
+}
+
+// axisOrder returns the axis order defined by the authority for an
+// identifier. Known EPSG geographic systems have latitude first; other
+// systems, including geocentric and projected ones, are taken to have
+// easting first.
+func (id CRSIdentifier) axisOrder() AxisOrder {
+	if id.Authority != "EPSG" || id.Legacy {
+		return EastNorth
+	}
+	var code int
+	if _, err := fmt.Sscanf(id.Code, "%d", &code); err == nil && latitudeFirst[code] {
+		return NorthEast
+	}
+	return EastNorth
+}
+
+// AxisOrder returns the order of the coordinates in positions referenced
+// to the CRS. Systems identified in the URN or URI forms use the order of
+// their authority's definition, so that "urn:ogc:def:crs:EPSG::4326" has
+// latitude first. The legacy "EPSG:4326" form, CRS84 and a nil CRS have
+// longitude first, as do CRS members that cannot be identified.
+func (c *CRS) AxisOrder() AxisOrder {
+	id, err := c.Identifier()
+	if err != nil {
+		return EastNorth
+	}
+	return id.axisOrder()
+}
+
+// crsAliases are systems known by more than one identifier
+var crsAliases = map[string]string{
+	"OGC:CRS84": "EPSG:4326",
+	"OGC:CRS83": "EPSG:4269",
+	"OGC:CRS27": "EPSG:4267",
+}
+
+// Equivalent returns true if positions referenced to one CRS can be used
+// unchanged with the other, because both identify the same system with the
+// same axis order. A nil CRS is CRS84, the default in RFC 7946.
+// Unidentified CRS members are equivalent only to identical ones.
+func (c *CRS) Equivalent(other *CRS) bool {
+	a, errA := c.Identifier()
+	b, errB := other.Identifier()
+	if errA != nil || errB != nil {
+		if c == nil || other == nil || !strings.EqualFold(c.Type, other.Type) ||
+			len(c.Properties) != len(other.Properties) {
+			return false
+		}
+		for k, v := range c.Properties {
+			if other.Properties[k] != v {
+				return false
+			}
+		}
+		return true
+	}
+	return a.axisOrder() == b.axisOrder() && a.system() == b.system()
+}
+
+// system returns a canonical name for the system an identifier refers to
+func (id CRSIdentifier) system() string {
+	s := id.String()
+	if alias, ok := crsAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+// isWGS84Geographic returns true if the identifier is for WGS84 longitude
+// and latitude, in either order, with or without height
+func (id CRSIdentifier) isWGS84Geographic() bool {
+	switch id.system() {
+	case "EPSG:4326", "EPSG:4979":
+		return true
+	}
+	return false
+}
+
+// CRS84Options controls NormalizeCRS84
+type CRS84Options struct {
+	// LegacyLatLon reads positions referenced to the legacy "EPSG:4326"
+	// form as latitude, longitude rather than longitude, latitude
+	LegacyLatLon bool
+}
+
+// normalizeCRS84 returns a copy of g in CRS84 without CRS members, given
+// the CRS inherited from its parent
+func normalizeCRS84(g *Geo, inherited *CRS, opts CRS84Options) (*Geo, error) {
+	own, err := g.GetCRS()
+	if err != nil {
+		return nil, err
+	}
+	crs := inherited
+	if own != nil {
+		crs = own
+	}
+
+	switch g.Type {
+	case "GeometryCollection":
+		coll := &GeometryCollection{Geometries: make([]*Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			normal, err := normalizeCRS84(member, crs, opts)
+			if err != nil {
+				return nil, err
+			}
+			coll.Geometries[i] = normal
+		}
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		normal, err := normalizeCRS84(&g.Feature.Geometry, crs, opts)
+		if err != nil {
+			return nil, err
+		}
+		f := *g.Feature
+		f.CRS = nil
+		f.Geometry = *normal
+		return &Geo{Type: "Feature", Feature: &f}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{Features: make([]Feature, len(g.FeatureCollection.Features))}
+		for i := range g.FeatureCollection.Features {
+			normal, err := normalizeCRS84(&Geo{Type: "Feature", Feature: &g.FeatureCollection.Features[i]}, crs, opts)
+			if err != nil {
+				return nil, err
+			}
+			coll.Features[i] = *normal.Feature
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+
+	id, err := crs.Identifier()
+	if err != nil {
+		return nil, err
+	}
+	if !id.isWGS84Geographic() {
+		return nil, fmt.Errorf("cannot normalize %s to CRS84 without reprojecting", id)
+	}
+	swap := id.axisOrder() == NorthEast || (id.Legacy && id.Authority == "EPSG" && opts.LegacyLatLon)
+	normal, err := g.MapPositions(func(pos []float64) []float64 {
+		if swap && len(pos) >= 2 {
+			pos[0], pos[1] = pos[1], pos[0]
+		}
+		return pos
+	})
+	if err != nil {
+		return nil, err
+	}
+	setCRS(normal, nil)
+	return normal, nil
+}
+
+// setCRS sets the CRS member of an object
+func setCRS(g *Geo, crs *CRS) {
+	switch g.Type {
+	case "Point":
+		g.Point.CRS = crs
+	case "MultiPoint":
+		g.MultiPoint.CRS = crs
+	case "LineString":
+		g.LineString.CRS = crs
+	case "MultiLineString":
+		g.MultiLineString.CRS = crs
+	case "Polygon":
+		g.Polygon.CRS = crs
+	case "MultiPolygon":
+		g.MultiPolygon.CRS = crs
+	case "GeometryCollection":
+		g.GeometryCollection.CRS = crs
+	case "Feature":
+		g.Feature.CRS = crs
+	case "FeatureCollection":
+		g.FeatureCollection.CRS = crs
+	}
+}
+
+// NormalizeCRS84 returns a copy of any geometry, Feature or
+// FeatureCollection referenced to WGS84 longitude and latitude, as RFC 7946
+// requires, with all CRS members removed. Members without their own CRS
+// member use that of their parent, and the default for the whole object is
+// CRS84. Positions in latitude, longitude order, such as those referenced
+// to "urn:ogc:def:crs:EPSG::4326", have their first two coordinates
+// swapped. Data in any other system must be reprojected first, such as
+// with the proj package.
+func (g *Geo) NormalizeCRS84(opts CRS84Options) (*Geo, error) {
+	return normalizeCRS84(g, nil, opts)
+}
@@ -0,0 +1,132 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestParseCRSName(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		expected CRSIdentifier
+		order    AxisOrder
+	}{
+		{"urn:ogc:def:crs:EPSG::4326", CRSIdentifier{"EPSG", "4326", false}, NorthEast},
+		{"urn:ogc:def:crs:EPSG:6.6:3857", CRSIdentifier{"EPSG", "3857", false}, EastNorth},
+		{"urn:ogc:def:crs:OGC:1.3:CRS84", CRSIdentifier{"OGC", "CRS84", false}, EastNorth},
+		{"EPSG:4326", CRSIdentifier{"EPSG", "4326", true}, EastNorth},
+		{"epsg:32633", CRSIdentifier{"EPSG", "32633", true}, EastNorth},
+		{"http://www.opengis.net/def/crs/EPSG/0/4269", CRSIdentifier{"EPSG", "4269", false}, NorthEast},
+		{"CRS84", CRSIdentifier{"OGC", "CRS84", false}, EastNorth},
+		// geocentric and projected systems among the 4000s
+		{"urn:ogc:def:crs:EPSG::4978", CRSIdentifier{"EPSG", "4978", false}, EastNorth},
+		{"urn:ogc:def:crs:EPSG::4087", CRSIdentifier{"EPSG", "4087", false}, EastNorth},
+		{"urn:ogc:def:crs:EPSG::4979", CRSIdentifier{"EPSG", "4979", false}, NorthEast},
+	} {
+		id, err := ParseCRSName(c.name)
+		if err != nil || id != c.expected {
+			fmt.Println("recieved    ", id, err)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+		if order := NewNamedCRS(c.name).AxisOrder(); order != c.order {
+			fmt.Println("recieved    ", order, "for", c.name)
+			fmt.Println("but expected", c.order)
+			t.Fail()
+		}
+	}
+	if _, err := ParseCRSName("WGS 84"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLinkedCRS(t *testing.T) {
+	var crs CRS
+	err := json.Unmarshal([]byte(`{"type": "link", "properties": {"href": "http://spatialreference.org/ref/epsg/26910/proj4/", "type": "proj4"}}`), &crs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	href, linkType, ok := crs.Link()
+	if !ok || href != "http://spatialreference.org/ref/epsg/26910/proj4/" || linkType != "proj4" {
+		fmt.Println("recieved    ", href, linkType, ok)
+		t.Fail()
+	}
+	id, err := crs.Identifier()
+	if err != nil || id.String() != "EPSG:26910" {
+		fmt.Println("recieved    ", id, err)
+		t.Fail()
+	}
+	if _, err = NewLinkedCRS("data.crs", "ogcwkt").Identifier(); err == nil {
+		t.Fail()
+	}
+	if _, _, ok = NewNamedCRS("EPSG:4326").Link(); ok {
+		t.Fail()
+	}
+}
+
+func TestCRSEquivalent(t *testing.T) {
+	var none *CRS
+	crs84 := NewNamedCRS("urn:ogc:def:crs:OGC:1.3:CRS84")
+	legacy := NewNamedCRS("EPSG:4326")
+	urn := NewNamedCRS("urn:ogc:def:crs:EPSG::4326")
+	if !crs84.Equivalent(legacy) || !none.Equivalent(crs84) || !legacy.Equivalent(none) {
+		t.Fail()
+	}
+	// the same system with latitude first
+	if urn.Equivalent(legacy) || urn.Equivalent(none) {
+		t.Fail()
+	}
+	if !urn.Equivalent(NewNamedCRS("http://www.opengis.net/def/crs/EPSG/0/4326")) {
+		t.Fail()
+	}
+	if NewNamedCRS("EPSG:3857").Equivalent(legacy) {
+		t.Fail()
+	}
+	if !NewLinkedCRS("data.crs", "ogcwkt").Equivalent(NewLinkedCRS("data.crs", "ogcwkt")) ||
+		NewLinkedCRS("data.crs", "ogcwkt").Equivalent(NewLinkedCRS("data.crs", "esriwkt")) {
+		t.Fail()
+	}
+}
+
+func TestNormalizeCRS84(t *testing.T) {
+	// a legacy file with a latitude first member inside a collection
+	fc := &Geo{Type: "FeatureCollection", FeatureCollection: &FeatureCollection{
+		CRSReferencable: CRSReferencable{NewNamedCRS("EPSG:4326")},
+		Features: []Feature{
+			{ID: "a", Geometry: Geo{Type: "Point", Point: &Point{Coordinates: []float64{-79.98, 40.45, 300}}}},
+			{ID: "b", CRSReferencable: CRSReferencable{NewNamedCRS("urn:ogc:def:crs:EPSG::4326")},
+				Geometry: Geo{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{40.45, -79.98}, {40.46, -79.99}}}}},
+		},
+	}}
+	normal, err := fc.NormalizeCRS84(CRS84Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := normal.FeatureCollection.Features[0], normal.FeatureCollection.Features[1]
+	if fmt.Sprint(a.Geometry.Point.Coordinates) != "[-79.98 40.45 300]" ||
+		fmt.Sprint(b.Geometry.LineString.Coordinates) != "[[-79.98 40.45] [-79.99 40.46]]" {
+		fmt.Println("recieved    ", a.Geometry.Point.Coordinates, b.Geometry.LineString.Coordinates)
+		t.Fail()
+	}
+	if normal.FeatureCollection.CRS != nil || b.CRS != nil || b.ID != "b" {
+		t.Fail()
+	}
+	// the input is unchanged
+	if fc.FeatureCollection.Features[1].Geometry.LineString.Coordinates[0][0] != 40.45 {
+		t.Fail()
+	}
+
+	// the option reads the legacy form as latitude first
+	pt := &Geo{Type: "Point", Point: &Point{CRSReferencable{NewNamedCRS("EPSG:4326")}, []float64{40.45, -79.98}}}
+	normal, err = pt.NormalizeCRS84(CRS84Options{LegacyLatLon: true})
+	if err != nil || fmt.Sprint(normal.Point.Coordinates) != "[-79.98 40.45]" || normal.Point.CRS != nil {
+		fmt.Println("recieved    ", normal, err)
+		t.Fail()
+	}
+
+	projected := &Geo{Type: "Point", Point: &Point{CRSReferencable{NewNamedCRS("EPSG:3857")}, []float64{0, 0}}}
+	if _, err = projected.NormalizeCRS84(CRS84Options{}); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,301 @@
+/* functions for adding vertices so that no segment exceeds a length */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// densifyPath returns a copy of a path with evenly spaced positions inserted
+// into every segment longer than maxLength. The positions of the path are
+// copied, so that the result shares no memory with it.
+func (m lineMeasure) densifyPath(path [][]float64, maxLength float64) [][]float64 {
+	if len(path) == 0 {
+		return [][]float64{}
+	}
+	out := [][]float64{append([]float64{}, path[0]...)}
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		s12 := m.length(a, b)
+		n := math.Ceil(s12 / maxLength)
+		for k := 1.0; k < n; k++ {
+			out = append(out, m.along(a, b, k*s12/n, s12))
+		}
+		out = append(out, append([]float64{}, b...))
+	}
+	return out
+}
+
+func (m lineMeasure) densifyPaths(paths [][][]float64, maxLength float64) [][][]float64 {
+	out := make([][][]float64, len(paths))
+	for i, path := range paths {
+		out[i] = m.densifyPath(path, maxLength)
+	}
+	return out
+}
+
+func (m lineMeasure) densifyGeo(g *Geo, maxLength float64) (*Geo, error) {
+	if !(maxLength > 0) {
+		return nil, errors.New("maximum segment length must be positive")
+	}
+	switch g.Type {
+	case "Point":
+		return &Geo{Type: "Point", Point: &Point{g.Point.CRSReferencable,
+			append([]float64{}, g.Point.Coordinates...)}}, nil
+	case "MultiPoint":
+		return &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{g.MultiPoint.CRSReferencable,
+			mapPath(g.MultiPoint.Coordinates, func(pos []float64) []float64 { return pos })}}, nil
+	case "LineString":
+		return &Geo{Type: "LineString", LineString: &LineString{g.LineString.CRSReferencable,
+			m.densifyPath(g.LineString.Coordinates, maxLength)}}, nil
+	case "MultiLineString":
+		return &Geo{Type: "MultiLineString", MultiLineString: &MultiLineString{g.MultiLineString.CRSReferencable,
+			m.densifyPaths(g.MultiLineString.Coordinates, maxLength)}}, nil
+	case "Polygon":
+		return &Geo{Type: "Polygon", Polygon: &Polygon{g.Polygon.CRSReferencable,
+			m.densifyPaths(g.Polygon.Coordinates, maxLength)}}, nil
+	case "MultiPolygon":
+		coords := make([][][][]float64, len(g.MultiPolygon.Coordinates))
+		for i, poly := range g.MultiPolygon.Coordinates {
+			coords[i] = m.densifyPaths(poly, maxLength)
+		}
+		return &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{g.MultiPolygon.CRSReferencable, coords}}, nil
+	case "GeometryCollection":
+		coll := &GeometryCollection{g.GeometryCollection.CRSReferencable,
+			make([]*Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			dense, err := m.densifyGeo(member, maxLength)
+			if err != nil {
+				return nil, err
+			}
+			coll.Geometries[i] = dense
+		}
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		dense, err := m.densifyGeo(&g.Feature.Geometry, maxLength)
+		if err != nil {
+			return nil, err
+		}
+		f := *g.Feature
+		f.Geometry = *dense
+		return &Geo{Type: "Feature", Feature: &f}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{g.FeatureCollection.CRSReferencable,
+			make([]Feature, len(g.FeatureCollection.Features))}
+		for i, f := range g.FeatureCollection.Features {
+			dense, err := m.densifyGeo(&f.Geometry, maxLength)
+			if err != nil {
+				return nil, err
+			}
+			f.Geometry = *dense
+			coll.Features[i] = f
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+// Densify returns a copy of the Point, which has no segments
+func (g *Point) Densify(maxSegmentLength float64) (*Point, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "Point", Point: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Point, nil
+}
+
+// Densify returns a copy of the LineString with evenly spaced positions
+// inserted so that no segment is longer than maxSegmentLength, in the units
+// of the coordinates. Coordinates beyond the second are interpolated
+// linearly.
+func (g *LineString) Densify(maxSegmentLength float64) (*LineString, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "LineString", LineString: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.LineString, nil
+}
+
+// Densify returns a copy of the Polygon with positions inserted into the
+// edges of every ring. See LineString.Densify.
+func (g *Polygon) Densify(maxSegmentLength float64) (*Polygon, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "Polygon", Polygon: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Polygon, nil
+}
+
+// Densify returns a copy of the MultiPoint, which has no segments
+func (g *MultiPoint) Densify(maxSegmentLength float64) (*MultiPoint, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "MultiPoint", MultiPoint: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiPoint, nil
+}
+
+// Densify returns a copy of the MultiLineString with positions inserted
+// into every line. See LineString.Densify.
+func (g *MultiLineString) Densify(maxSegmentLength float64) (*MultiLineString, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "MultiLineString", MultiLineString: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiLineString, nil
+}
+
+// Densify returns a copy of the MultiPolygon with positions inserted into
+// the edges of every ring. See LineString.Densify.
+func (g *MultiPolygon) Densify(maxSegmentLength float64) (*MultiPolygon, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "MultiPolygon", MultiPolygon: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiPolygon, nil
+}
+
+// Densify returns a copy of the GeometryCollection with every member
+// densified. See LineString.Densify.
+func (coll *GeometryCollection) Densify(maxSegmentLength float64) (*GeometryCollection, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll},
+		maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.GeometryCollection, nil
+}
+
+// Densify returns a copy of the Feature with its geometry densified. See
+// LineString.Densify.
+func (f *Feature) Densify(maxSegmentLength float64) (*Feature, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "Feature", Feature: f}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Feature, nil
+}
+
+// Densify returns a copy of the FeatureCollection with the geometry of
+// every Feature densified. See LineString.Densify.
+func (coll *FeatureCollection) Densify(maxSegmentLength float64) (*FeatureCollection, error) {
+	dense, err := planarMeasure.densifyGeo(&Geo{Type: "FeatureCollection", FeatureCollection: coll},
+		maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.FeatureCollection, nil
+}
+
+// Densify returns a copy of any geometry, Feature or FeatureCollection with
+// evenly spaced positions inserted so that no segment is longer than
+// maxSegmentLength, in the units of the coordinates
+func (g *Geo) Densify(maxSegmentLength float64) (*Geo, error) {
+	return planarMeasure.densifyGeo(g, maxSegmentLength)
+}
+
+// GeodesicDensify returns a copy of the Point, which has no segments
+func (g *Point) GeodesicDensify(maxSegmentLength float64) (*Point, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "Point", Point: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Point, nil
+}
+
+// GeodesicDensify returns a copy of the LineString with positions inserted
+// along the geodesic between each pair of positions on the WGS84
+// ellipsoid, evenly spaced so that no segment is longer than
+// maxSegmentLength metres. See Geo.GeodesicDensify.
+func (g *LineString) GeodesicDensify(maxSegmentLength float64) (*LineString, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "LineString", LineString: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.LineString, nil
+}
+
+// GeodesicDensify returns a copy of the Polygon with positions inserted
+// along the geodesic edges of every ring. See Geo.GeodesicDensify.
+func (g *Polygon) GeodesicDensify(maxSegmentLength float64) (*Polygon, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "Polygon", Polygon: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Polygon, nil
+}
+
+// GeodesicDensify returns a copy of the MultiPoint, which has no segments
+func (g *MultiPoint) GeodesicDensify(maxSegmentLength float64) (*MultiPoint, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "MultiPoint", MultiPoint: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiPoint, nil
+}
+
+// GeodesicDensify returns a copy of the MultiLineString with positions
+// inserted along the geodesics of every line. See Geo.GeodesicDensify.
+func (g *MultiLineString) GeodesicDensify(maxSegmentLength float64) (*MultiLineString, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "MultiLineString", MultiLineString: g},
+		maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiLineString, nil
+}
+
+// GeodesicDensify returns a copy of the MultiPolygon with positions
+// inserted along the geodesic edges of every ring. See Geo.GeodesicDensify.
+func (g *MultiPolygon) GeodesicDensify(maxSegmentLength float64) (*MultiPolygon, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "MultiPolygon", MultiPolygon: g}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.MultiPolygon, nil
+}
+
+// GeodesicDensify returns a copy of the GeometryCollection with every
+// member densified. See Geo.GeodesicDensify.
+func (coll *GeometryCollection) GeodesicDensify(maxSegmentLength float64) (*GeometryCollection, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll},
+		maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.GeometryCollection, nil
+}
+
+// GeodesicDensify returns a copy of the Feature with its geometry
+// densified. See Geo.GeodesicDensify.
+func (f *Feature) GeodesicDensify(maxSegmentLength float64) (*Feature, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "Feature", Feature: f}, maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.Feature, nil
+}
+
+// GeodesicDensify returns a copy of the FeatureCollection with the geometry
+// of every Feature densified. See Geo.GeodesicDensify.
+func (coll *FeatureCollection) GeodesicDensify(maxSegmentLength float64) (*FeatureCollection, error) {
+	dense, err := geodesicMeasure.densifyGeo(&Geo{Type: "FeatureCollection", FeatureCollection: coll},
+		maxSegmentLength)
+	if err != nil {
+		return nil, err
+	}
+	return dense.FeatureCollection, nil
+}
+
+// GeodesicDensify returns a copy of any geometry, Feature or
+// FeatureCollection of longitude and latitude positions with positions
+// inserted along the geodesic, the shortest path on the WGS84 ellipsoid,
+// between each pair of positions. They are evenly spaced so that no
+// segment is longer than maxSegmentLength metres, so that edges keep their
+// true shape when the result is drawn in a projection such as Web
+// Mercator. Longitudes of inserted positions are in [-180, 180]; edges that
+// cross the antimeridian can be split afterwards with CutAntimeridian.
+func (g *Geo) GeodesicDensify(maxSegmentLength float64) (*Geo, error) {
+	return geodesicMeasure.densifyGeo(g, maxSegmentLength)
+}
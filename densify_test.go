@@ -0,0 +1,121 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+func TestDensifyLineString(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {3, 0}, {3, 1}}}
+	dense, err := ls.Densify(1.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]float64{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {3, 1}}
+	if fmt.Sprint(dense.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", dense.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	if _, err = ls.Densify(0); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDensifyPolygon(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}}
+	dense, err := poly.Densify(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := dense.Coordinates[0]
+	if len(ring) != 9 || ringArea(ring) != 16 || fmt.Sprint(ring[1]) != fmt.Sprint([]float64{2, 0}) {
+		fmt.Println("recieved    ", ring)
+		t.Fail()
+	}
+}
+
+func TestDensifyFeatureCollection(t *testing.T) {
+	fc := &FeatureCollection{Features: []Feature{
+		{ID: "a", Geometry: Geo{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{0, 0}, {0, 10}}}}},
+		{ID: "b", Geometry: Geo{Type: "Point", Point: &Point{Coordinates: []float64{1, 1}}}},
+	}}
+	dense, err := fc.Densify(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dense.Features) != 2 || len(dense.Features[0].Geometry.LineString.Coordinates) != 3 ||
+		dense.Features[1].ID != "b" {
+		fmt.Println("recieved    ", dense.Features)
+		t.Fail()
+	}
+}
+
+func TestDensifyCopies(t *testing.T) {
+	// changing the positions of the result leaves the input unchanged
+	g := &Geo{Type: "GeometryCollection", GeometryCollection: &GeometryCollection{Geometries: []*Geo{
+		{Type: "Point", Point: &Point{Coordinates: []float64{1, 1}}},
+		{Type: "MultiPoint", MultiPoint: &MultiPoint{Coordinates: [][]float64{{2, 2}}}},
+		{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{0, 0}, {0, 10}}}},
+		{Type: "Polygon", Polygon: &Polygon{Coordinates: [][][]float64{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}}}},
+	}}}
+	before, _ := json.Marshal(g)
+	for _, maxLength := range []float64{1, 100} {
+		dense, err := g.Densify(maxLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members := dense.GeometryCollection.Geometries
+		members[0].Point.Coordinates[0] = -1
+		var positions [][]float64
+		positions = append(positions, members[1].MultiPoint.Coordinates...)
+		positions = append(positions, members[2].LineString.Coordinates...)
+		positions = append(positions, members[3].Polygon.Coordinates[0]...)
+		for _, pos := range positions {
+			pos[0] = -1
+		}
+	}
+	after, _ := json.Marshal(g)
+	if string(after) != string(before) {
+		fmt.Println("recieved    ", string(after))
+		fmt.Println("but expected", string(before))
+		t.Fail()
+	}
+}
+
+func TestGeodesicDensify(t *testing.T) {
+	// a flight from New York to London
+	ls := &LineString{Coordinates: [][]float64{{-73.8, 40.6}, {-0.5, 51.6}}}
+	dense, err := ls.GeodesicDensify(500e3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s12, _, _ := geod.WGS84.Inverse(40.6, -73.8, 51.6, -0.5)
+	n := int(math.Ceil(s12 / 500e3))
+	if len(dense.Coordinates) != n+1 {
+		fmt.Println("recieved    ", len(dense.Coordinates))
+		fmt.Println("but expected", n+1)
+		t.Fail()
+	}
+	for i := 1; i < len(dense.Coordinates); i++ {
+		a, b := dense.Coordinates[i-1], dense.Coordinates[i]
+		s, _, _ := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+		if math.Abs(s-s12/float64(n)) > 1e-6 {
+			fmt.Println("recieved    ", s)
+			fmt.Println("but expected", s12/float64(n))
+			t.Fail()
+		}
+	}
+	// the great circle route passes well north of both ends
+	if dense.Coordinates[n/2][1] < 52 {
+		fmt.Println("recieved    ", dense.Coordinates[n/2])
+		t.Fail()
+	}
+	if math.Abs(dense.GeodesicLength()-s12) > 1e-6 {
+		t.Fail()
+	}
+}
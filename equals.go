@@ -0,0 +1,553 @@
+/* functions for comparing geometries and putting them into canonical form */
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// EqualsMode selects the comparison made by Equals
+type EqualsMode int
+
+const (
+	// EqualsExact requires the same types and the same positions in the same
+	// order, with the same number of coordinates
+	EqualsExact EqualsMode = iota
+	// EqualsTopological requires the geometries to cover the same set of
+	// points, whatever their types and structure
+	EqualsTopological
+	// EqualsTolerance requires the same types and structure, but ignores the
+	// start position and direction of rings, the direction of lines, the
+	// order of the parts of multi-part geometries and of the members of
+	// GeometryCollections, repeated positions, and differences in
+	// coordinates of up to Epsilon
+	EqualsTolerance
+)
+
+// EqualsOptions configures Equals
+type EqualsOptions struct {
+	Mode EqualsMode
+	// Epsilon is the largest difference in any coordinate that
+	// EqualsTolerance treats as equal
+	Epsilon float64
+}
+
+// Equals returns true if two geometries, Features or FeatureCollections are
+// equal under the comparison selected by opts. Features must also have the
+// same ID and Properties, except in EqualsTopological mode, which compares
+// only the points covered. CRS members are not compared.
+func Equals(a, b *Geo, opts EqualsOptions) (bool, error) {
+	switch opts.Mode {
+	case EqualsExact:
+		return equalGeo(a, b, 0, false)
+	case EqualsTolerance:
+		if opts.Epsilon < 0 || math.IsNaN(opts.Epsilon) {
+			return false, fmt.Errorf("invalid epsilon %v", opts.Epsilon)
+		}
+		return equalGeo(a, b, opts.Epsilon, true)
+	case EqualsTopological:
+		da, db, err := dimensions(a, b)
+		if err != nil {
+			return false, err
+		}
+		if da != db {
+			return false, nil
+		} else if da == -1 {
+			return true, nil
+		}
+		m, err := Relate(a, b)
+		if err != nil {
+			return false, err
+		}
+		return m.Matches("T*F**FFF*"), nil
+	}
+	return false, fmt.Errorf("unhandled equals mode: %d", opts.Mode)
+}
+
+func positionsEqual(a, b []float64, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(math.Abs(a[i]-b[i]) <= eps) {
+			return false
+		}
+	}
+	return true
+}
+
+func pathsEqual(a, b [][]float64, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !positionsEqual(a[i], b[i], eps) {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupePath drops positions within eps of the position before them
+func dedupePath(path [][]float64, eps float64) [][]float64 {
+	out := make([][]float64, 0, len(path))
+	for _, pos := range path {
+		if len(out) == 0 || !positionsEqual(out[len(out)-1], pos, eps) {
+			out = append(out, pos)
+		}
+	}
+	return out
+}
+
+// linesMatch compares lines in either direction
+func linesMatch(a, b [][]float64, eps float64) bool {
+	a, b = dedupePath(a, eps), dedupePath(b, eps)
+	if pathsEqual(a, b, eps) {
+		return true
+	}
+	if len(a) != len(b) {
+		return false
+	}
+	n := len(a)
+	for i := range a {
+		if !positionsEqual(a[i], b[n-1-i], eps) {
+			return false
+		}
+	}
+	return true
+}
+
+// distinctRing returns the distinct positions of a ring, without the closing
+// position
+func distinctRing(ring [][]float64, eps float64) [][]float64 {
+	ring = dedupePath(ring, eps)
+	if len(ring) > 1 && positionsEqual(ring[0], ring[len(ring)-1], eps) {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// ringsMatch compares rings from any start position and in either direction
+func ringsMatch(a, b [][]float64, eps float64) bool {
+	a, b = distinctRing(a, eps), distinctRing(b, eps)
+	n := len(a)
+	if n != len(b) {
+		return false
+	} else if n == 0 {
+		return true
+	}
+	for k := 0; k != n; k++ {
+		if !positionsEqual(a[0], b[k], eps) {
+			continue
+		}
+		forward, backward := true, true
+		for i := 1; i != n && (forward || backward); i++ {
+			forward = forward && positionsEqual(a[i], b[(k+i)%n], eps)
+			backward = backward && positionsEqual(a[i], b[(k-i+n)%n], eps)
+		}
+		if forward || backward {
+			return true
+		}
+	}
+	return false
+}
+
+// matchParts reports whether the parts of two multi-part geometries can be
+// paired one to one in any order. Pairs are found by augmenting paths, so
+// that a part paired early gives way when another part has no other match,
+// and match is called at most once for each pair.
+func matchParts(n, m int, match func(i, j int) bool) bool {
+	if n != m {
+		return false
+	}
+	// known is 0 for pairs not yet compared, 1 for matches and 2 otherwise
+	known := make([]int8, n*m)
+	matches := func(i, j int) bool {
+		if known[i*m+j] == 0 {
+			known[i*m+j] = 2
+			if match(i, j) {
+				known[i*m+j] = 1
+			}
+		}
+		return known[i*m+j] == 1
+	}
+	// owner is the part of the first geometry paired with each part of the
+	// second, or -1
+	owner := make([]int, m)
+	for j := range owner {
+		owner[j] = -1
+	}
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := 0; j != m; j++ {
+			if visited[j] || !matches(i, j) {
+				continue
+			}
+			visited[j] = true
+			if owner[j] == -1 || augment(owner[j], visited) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i != n; i++ {
+		if !augment(i, make([]bool, m)) {
+			return false
+		}
+	}
+	return true
+}
+
+func polygonsMatch(a, b [][][]float64, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	} else if len(a) == 0 {
+		return true
+	}
+	if !ringsMatch(a[0], b[0], eps) {
+		return false
+	}
+	return matchParts(len(a)-1, len(b)-1, func(i, j int) bool {
+		return ringsMatch(a[i+1], b[j+1], eps)
+	})
+}
+
+func featuresEqual(a, b *Feature, eps float64, tolerant bool) (bool, error) {
+	if a.ID != b.ID || !reflect.DeepEqual(a.Properties, b.Properties) {
+		return false, nil
+	}
+	return equalGeo(&a.Geometry, &b.Geometry, eps, tolerant)
+}
+
+// coordinates returns the coordinates of a geometry other than a
+// GeometryCollection
+func (g *Geo) coordinates() interface{} {
+	switch g.Type {
+	case "Point":
+		return g.Point.Coordinates
+	case "MultiPoint":
+		return g.MultiPoint.Coordinates
+	case "LineString":
+		return g.LineString.Coordinates
+	case "MultiLineString":
+		return g.MultiLineString.Coordinates
+	case "Polygon":
+		return g.Polygon.Coordinates
+	case "MultiPolygon":
+		return g.MultiPolygon.Coordinates
+	}
+	return nil
+}
+
+// equalGeo compares geometries exactly, or structurally within eps when
+// tolerant
+func equalGeo(a, b *Geo, eps float64, tolerant bool) (bool, error) {
+	if a.Type != b.Type {
+		return false, nil
+	}
+	switch a.Type {
+	case "Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		if !tolerant {
+			return reflect.DeepEqual(a.coordinates(), b.coordinates()), nil
+		}
+	}
+	switch a.Type {
+	case "Point":
+		return positionsEqual(a.Point.Coordinates, b.Point.Coordinates, eps), nil
+	case "MultiPoint":
+		pa, pb := a.MultiPoint.Coordinates, b.MultiPoint.Coordinates
+		return matchParts(len(pa), len(pb), func(i, j int) bool {
+			return positionsEqual(pa[i], pb[j], eps)
+		}), nil
+	case "LineString":
+		return linesMatch(a.LineString.Coordinates, b.LineString.Coordinates, eps), nil
+	case "MultiLineString":
+		la, lb := a.MultiLineString.Coordinates, b.MultiLineString.Coordinates
+		return matchParts(len(la), len(lb), func(i, j int) bool {
+			return linesMatch(la[i], lb[j], eps)
+		}), nil
+	case "Polygon":
+		return polygonsMatch(a.Polygon.Coordinates, b.Polygon.Coordinates, eps), nil
+	case "MultiPolygon":
+		pa, pb := a.MultiPolygon.Coordinates, b.MultiPolygon.Coordinates
+		return matchParts(len(pa), len(pb), func(i, j int) bool {
+			return polygonsMatch(pa[i], pb[j], eps)
+		}), nil
+	case "GeometryCollection":
+		ga, gb := a.GeometryCollection.Geometries, b.GeometryCollection.Geometries
+		var err error
+		ok := matchParts(len(ga), len(gb), func(i, j int) bool {
+			if (!tolerant && i != j) || err != nil {
+				return false
+			}
+			var eq bool
+			eq, err = equalGeo(ga[i], gb[j], eps, tolerant)
+			return eq
+		})
+		return ok && err == nil, err
+	case "Feature":
+		return featuresEqual(a.Feature, b.Feature, eps, tolerant)
+	case "FeatureCollection":
+		fa, fb := a.FeatureCollection.Features, b.FeatureCollection.Features
+		if len(fa) != len(fb) {
+			return false, nil
+		}
+		for i := range fa {
+			if eq, err := featuresEqual(&fa[i], &fb[i], eps, tolerant); !eq || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("unhandled type: '%s'", a.Type)
+}
+
+// comparePositions orders positions by their coordinates in turn, with
+// shorter positions first when one is a prefix of the other
+func comparePositions(a, b []float64) int {
+	for i := 0; i != len(a) && i != len(b); i++ {
+		if a[i] < b[i] {
+			return -1
+		} else if a[i] > b[i] {
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+func comparePaths(a, b [][]float64) int {
+	for i := 0; i != len(a) && i != len(b); i++ {
+		if c := comparePositions(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+func comparePolygons(a, b [][][]float64) int {
+	for i := 0; i != len(a) && i != len(b); i++ {
+		if c := comparePaths(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// copyPath returns a deep copy of a sequence of positions
+func copyPath(path [][]float64) [][]float64 {
+	return mapPath(path, func(pos []float64) []float64 { return pos })
+}
+
+func reversePath(path [][]float64) {
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+}
+
+// normalizeLine returns a copy of a line running from the lesser of its
+// end positions
+func normalizeLine(line [][]float64) [][]float64 {
+	out := copyPath(line)
+	if len(out) > 1 && comparePositions(out[len(out)-1], out[0]) < 0 {
+		reversePath(out)
+	}
+	return out
+}
+
+// normalizeRing returns a closed copy of a ring, counter-clockwise or
+// clockwise, starting from its least position
+func normalizeRing(ring [][]float64, ccw bool) [][]float64 {
+	out := copyPath(ring)
+	if len(out) > 1 && comparePositions(out[0], out[len(out)-1]) == 0 {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return out
+	}
+	if area := ringArea(out); (area < 0 && ccw) || (area > 0 && !ccw) {
+		reversePath(out)
+	}
+	least := 0
+	for i, pos := range out {
+		if comparePositions(pos, out[least]) < 0 {
+			least = i
+		}
+	}
+	out = append(out[least:], out[:least]...)
+	return append(out, copyPath(out[:1])...)
+}
+
+// normalizePolygon orients the shell counter-clockwise and the holes
+// clockwise, as RFC 7946 recommends, and sorts the holes
+func normalizePolygon(rings [][][]float64) [][][]float64 {
+	out := make([][][]float64, len(rings))
+	for i, ring := range rings {
+		out[i] = normalizeRing(ring, i == 0)
+	}
+	if len(out) > 1 {
+		holes := out[1:]
+		sort.SliceStable(holes, func(i, j int) bool { return comparePaths(holes[i], holes[j]) < 0 })
+	}
+	return out
+}
+
+// compareGeo orders geometries by type and then by their positions
+func compareGeo(a, b *Geo) int {
+	if a.Type != b.Type {
+		if a.Type < b.Type {
+			return -1
+		}
+		return 1
+	}
+	var pa, pb [][]float64
+	mapGeo(a, func(pos []float64) []float64 { pa = append(pa, pos); return pos })
+	mapGeo(b, func(pos []float64) []float64 { pb = append(pb, pos); return pos })
+	return comparePaths(pa, pb)
+}
+
+func normalizeGeo(g *Geo) (*Geo, error) {
+	switch g.Type {
+	case "Point":
+		return &Geo{Type: "Point", Point: g.Point.Normalize()}, nil
+	case "MultiPoint":
+		return &Geo{Type: "MultiPoint", MultiPoint: g.MultiPoint.Normalize()}, nil
+	case "LineString":
+		return &Geo{Type: "LineString", LineString: g.LineString.Normalize()}, nil
+	case "MultiLineString":
+		return &Geo{Type: "MultiLineString", MultiLineString: g.MultiLineString.Normalize()}, nil
+	case "Polygon":
+		return &Geo{Type: "Polygon", Polygon: g.Polygon.Normalize()}, nil
+	case "MultiPolygon":
+		return &Geo{Type: "MultiPolygon", MultiPolygon: g.MultiPolygon.Normalize()}, nil
+	case "GeometryCollection":
+		coll := &GeometryCollection{g.GeometryCollection.CRSReferencable,
+			make([]*Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			normalized, err := normalizeGeo(member)
+			if err != nil {
+				return nil, err
+			}
+			coll.Geometries[i] = normalized
+		}
+		sort.SliceStable(coll.Geometries, func(i, j int) bool {
+			return compareGeo(coll.Geometries[i], coll.Geometries[j]) < 0
+		})
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		normalized, err := normalizeGeo(&g.Feature.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		feature := *g.Feature
+		feature.Geometry = *normalized
+		return &Geo{Type: "Feature", Feature: &feature}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{g.FeatureCollection.CRSReferencable,
+			make([]Feature, len(g.FeatureCollection.Features))}
+		for i, feature := range g.FeatureCollection.Features {
+			normalized, err := normalizeGeo(&feature.Geometry)
+			if err != nil {
+				return nil, err
+			}
+			feature.Geometry = *normalized
+			coll.Features[i] = feature
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+// Normalize returns a copy of the Point. See Geo.Normalize.
+func (g *Point) Normalize() *Point {
+	return g.MapPositions(func(pos []float64) []float64 { return pos })
+}
+
+// Normalize returns a copy of the LineString running from the lesser of its
+// end positions. See Geo.Normalize.
+func (g *LineString) Normalize() *LineString {
+	return &LineString{g.CRSReferencable, normalizeLine(g.Coordinates)}
+}
+
+// Normalize returns a copy of the Polygon in canonical form. See
+// Geo.Normalize.
+func (g *Polygon) Normalize() *Polygon {
+	return &Polygon{g.CRSReferencable, normalizePolygon(g.Coordinates)}
+}
+
+// Normalize returns a copy of the MultiPoint with its positions sorted. See
+// Geo.Normalize.
+func (g *MultiPoint) Normalize() *MultiPoint {
+	coords := copyPath(g.Coordinates)
+	sort.SliceStable(coords, func(i, j int) bool { return comparePositions(coords[i], coords[j]) < 0 })
+	return &MultiPoint{g.CRSReferencable, coords}
+}
+
+// Normalize returns a copy of the MultiLineString in canonical form. See
+// Geo.Normalize.
+func (g *MultiLineString) Normalize() *MultiLineString {
+	coords := make([][][]float64, len(g.Coordinates))
+	for i, line := range g.Coordinates {
+		coords[i] = normalizeLine(line)
+	}
+	sort.SliceStable(coords, func(i, j int) bool { return comparePaths(coords[i], coords[j]) < 0 })
+	return &MultiLineString{g.CRSReferencable, coords}
+}
+
+// Normalize returns a copy of the MultiPolygon in canonical form. See
+// Geo.Normalize.
+func (g *MultiPolygon) Normalize() *MultiPolygon {
+	coords := make([][][][]float64, len(g.Coordinates))
+	for i, poly := range g.Coordinates {
+		coords[i] = normalizePolygon(poly)
+	}
+	sort.SliceStable(coords, func(i, j int) bool { return comparePolygons(coords[i], coords[j]) < 0 })
+	return &MultiPolygon{g.CRSReferencable, coords}
+}
+
+// Normalize returns a copy of the GeometryCollection with its members in
+// canonical form and order. See Geo.Normalize.
+func (coll *GeometryCollection) Normalize() (*GeometryCollection, error) {
+	normalized, err := normalizeGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+	if err != nil {
+		return nil, err
+	}
+	return normalized.GeometryCollection, nil
+}
+
+// Normalize returns a copy of the Feature with its geometry in canonical
+// form. See Geo.Normalize.
+func (f *Feature) Normalize() (*Feature, error) {
+	normalized, err := normalizeGeo(&Geo{Type: "Feature", Feature: f})
+	if err != nil {
+		return nil, err
+	}
+	return normalized.Feature, nil
+}
+
+// Normalize returns a copy of the FeatureCollection with the geometry of
+// every Feature in canonical form. See Geo.Normalize.
+func (coll *FeatureCollection) Normalize() (*FeatureCollection, error) {
+	normalized, err := normalizeGeo(&Geo{Type: "FeatureCollection", FeatureCollection: coll})
+	if err != nil {
+		return nil, err
+	}
+	return normalized.FeatureCollection, nil
+}
+
+// Normalize returns a copy of any geometry, Feature or FeatureCollection in
+// canonical form, so that geometries that differ only in the start and
+// direction of rings and lines or in the order of parts become identical,
+// and compare equal under EqualsExact. Lines run from
+// the lesser of their end positions, comparing x, then y, then further
+// coordinates. Polygon rings are closed and start from their least
+// position, shells run counter-clockwise and holes clockwise, and holes are
+// sorted. The positions of MultiPoints, the parts of other multi-part
+// geometries and the members of GeometryCollections are sorted. Features
+// keep their order within a FeatureCollection, and properties, identifiers
+// and CRS members are kept.
+func (g *Geo) Normalize() (*Geo, error) {
+	return normalizeGeo(g)
+}
@@ -0,0 +1,182 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEqualsExact(t *testing.T) {
+	a := polygonGeo([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}})
+	b := polygonGeo([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}})
+	rotated := polygonGeo([][]float64{{1, 0}, {1, 1}, {0, 1}, {0, 0}, {1, 0}})
+	if eq, err := Equals(a, b, EqualsOptions{}); !eq || err != nil {
+		fmt.Println("recieved    ", eq, err)
+		t.Fail()
+	}
+	if eq, _ := Equals(a, rotated, EqualsOptions{}); eq {
+		t.Fail()
+	}
+	// a Polygon is never exactly equal to a MultiPolygon
+	mp := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{a.Polygon.Coordinates}}}
+	if eq, _ := Equals(a, mp, EqualsOptions{}); eq {
+		t.Fail()
+	}
+	if _, err := Equals(&Geo{Type: "Curve"}, &Geo{Type: "Curve"}, EqualsOptions{}); err == nil {
+		t.Fail()
+	}
+	if _, err := Equals(a, b, EqualsOptions{Mode: EqualsMode(9)}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestEqualsTolerance(t *testing.T) {
+	opts := EqualsOptions{Mode: EqualsTolerance, Epsilon: 1e-9}
+	shell := [][]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	hole1 := [][]float64{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}}
+	hole2 := [][]float64{{3, 3}, {3, 3.5}, {3.5, 3.5}, {3, 3}}
+	a := polygonGeo(shell, hole1, hole2)
+	// the same polygon, starting elsewhere, running clockwise, with holes
+	// reordered, a repeated position and noise in the coordinates
+	b := polygonGeo(
+		[][]float64{{4, 4}, {4, 4}, {4, 1e-10}, {0, 0}, {0, 4}, {4, 4}},
+		[][]float64{{3.5, 3.5}, {3, 3.5}, {3, 3}, {3.5, 3.5}},
+		[][]float64{{2, 1}, {2, 2}, {1, 2}, {1, 1}, {2, 1}},
+	)
+	if eq, err := Equals(a, b, opts); !eq || err != nil {
+		fmt.Println("recieved    ", eq, err)
+		t.Fail()
+	}
+	if eq, _ := Equals(a, b, EqualsOptions{Mode: EqualsTolerance}); eq {
+		t.Fail()
+	}
+	c := polygonGeo([][]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4.1}, {0, 0}}, hole1, hole2)
+	if eq, _ := Equals(a, c, opts); eq {
+		t.Fail()
+	}
+
+	mpa := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{
+		{shell}, {{{10, 10}, {11, 10}, {11, 11}, {10, 10}}}}}}
+	mpb := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{
+		{{{11, 11}, {11, 10}, {10, 10}, {11, 11}}}, {shell}}}}
+	if eq, err := Equals(mpa, mpb, opts); !eq || err != nil {
+		fmt.Println("recieved    ", eq, err)
+		t.Fail()
+	}
+
+	line := lineGeo([]float64{0, 0}, []float64{1, 1}, []float64{2, 0})
+	reversed := lineGeo([]float64{2, 0}, []float64{1, 1}, []float64{0, 0})
+	if eq, _ := Equals(line, reversed, opts); !eq {
+		t.Fail()
+	}
+
+	fa := &Geo{Type: "Feature", Feature: &Feature{ID: "a", Geometry: *a, Properties: map[string]interface{}{"n": 1.0}}}
+	fb := &Geo{Type: "Feature", Feature: &Feature{ID: "a", Geometry: *b, Properties: map[string]interface{}{"n": 1.0}}}
+	if eq, _ := Equals(fa, fb, opts); !eq {
+		t.Fail()
+	}
+	fb.Feature.Properties["n"] = 2.0
+	if eq, _ := Equals(fa, fb, opts); eq {
+		t.Fail()
+	}
+	if _, err := Equals(a, b, EqualsOptions{Mode: EqualsTolerance, Epsilon: -1}); err == nil {
+		t.Fail()
+	}
+	// the first part of one is within tolerance of both parts of the
+	// other, and must give way to the second
+	pa := &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{Coordinates: [][]float64{{0.5, 0}, {0, 0}}}}
+	pb := &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{Coordinates: [][]float64{{0, 0}, {1, 0}}}}
+	half := EqualsOptions{Mode: EqualsTolerance, Epsilon: 0.5}
+	for _, pair := range [][2]*Geo{{pa, pb}, {pb, pa}} {
+		if eq, err := Equals(pair[0], pair[1], half); !eq || err != nil {
+			fmt.Println("recieved    ", eq, err)
+			fmt.Println("but expected", true)
+			t.Fail()
+		}
+	}
+	ga := &Geo{Type: "GeometryCollection", GeometryCollection: &GeometryCollection{Geometries: []*Geo{
+		pointGeo(0.5, 0), pointGeo(0, 0)}}}
+	gb := &Geo{Type: "GeometryCollection", GeometryCollection: &GeometryCollection{Geometries: []*Geo{
+		pointGeo(0, 0), pointGeo(1, 0)}}}
+	if eq, err := Equals(ga, gb, half); !eq || err != nil {
+		fmt.Println("recieved    ", eq, err)
+		t.Fail()
+	}
+	pb.MultiPoint.Coordinates[1] = []float64{1.5, 0}
+	if eq, _ := Equals(pa, pb, half); eq {
+		t.Fail()
+	}
+}
+
+func TestEqualsTopological(t *testing.T) {
+	opts := EqualsOptions{Mode: EqualsTopological}
+	line := lineGeo([]float64{0, 0}, []float64{2, 0})
+	parts := &Geo{Type: "MultiLineString", MultiLineString: &MultiLineString{Coordinates: [][][]float64{
+		{{1, 0}, {2, 0}}, {{1, 0}, {0, 0}}}}}
+	if eq, err := Equals(line, parts, opts); !eq || err != nil {
+		fmt.Println("recieved    ", eq, err)
+		t.Fail()
+	}
+	square := polygonGeo([][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}})
+	extraVertex := polygonGeo([][]float64{{2, 2}, {1, 2}, {0, 2}, {0, 0}, {2, 0}, {2, 2}})
+	if eq, _ := Equals(square, extraVertex, opts); !eq {
+		t.Fail()
+	}
+	smaller := polygonGeo([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}})
+	if eq, _ := Equals(square, smaller, opts); eq {
+		t.Fail()
+	}
+	if eq, _ := Equals(square, line, opts); eq {
+		t.Fail()
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	g := polygonGeo(
+		[][]float64{{2, 2}, {2, 0}, {0, 0}, {0, 2}, {2, 2}},
+		[][]float64{{1.5, 1.5}, {1.5, 1}, {1, 1}, {1.5, 1.5}},
+		[][]float64{{0.5, 0.5}, {1, 0.5}, {0.5, 1}, {0.5, 0.5}},
+	)
+	norm, err := g.Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][][]float64{
+		{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+		{{0.5, 0.5}, {0.5, 1}, {1, 0.5}, {0.5, 0.5}},
+		{{1, 1}, {1.5, 1.5}, {1.5, 1}, {1, 1}},
+	}
+	if fmt.Sprint(norm.Polygon.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", norm.Polygon.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	// the input is unchanged
+	if g.Polygon.Coordinates[0][0][0] != 2 {
+		t.Fail()
+	}
+
+	coll := &Geo{Type: "GeometryCollection", GeometryCollection: &GeometryCollection{Geometries: []*Geo{
+		{Type: "MultiPoint", MultiPoint: &MultiPoint{Coordinates: [][]float64{{3, 1}, {1, 2}, {1, 1}}}},
+		{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{5, 5}, {0, 0}}}},
+		{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{-1, 5}, {0, 0}}}},
+	}}}
+	norm, err = coll.Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := norm.GeometryCollection.Geometries
+	got := fmt.Sprint(members[0].LineString.Coordinates, members[1].LineString.Coordinates,
+		members[2].MultiPoint.Coordinates)
+	if got != "[[-1 5] [0 0]] [[0 0] [5 5]] [[1 1] [1 2] [3 1]]" {
+		fmt.Println("recieved    ", got)
+		t.Fail()
+	}
+
+	// geometries equal under EqualsTolerance become exactly equal
+	a, _ := polygonGeo([][]float64{{0, 0}, {1, 0}, {0, 1}, {0, 0}}).Normalize()
+	b, _ := polygonGeo([][]float64{{0, 1}, {1, 0}, {0, 0}, {0, 1}}).Normalize()
+	if eq, _ := Equals(a, b, EqualsOptions{}); !eq {
+		fmt.Println("recieved    ", a.Polygon.Coordinates, b.Polygon.Coordinates)
+		t.Fail()
+	}
+}
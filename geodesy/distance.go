@@ -0,0 +1,143 @@
+package geodesy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/njwilson23/geojson.go"
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// segmentDistance returns the distance on the WGS84 ellipsoid from pos to
+// the geodesic from a to b
+func segmentDistance(pos, a, b []float64) float64 {
+	_, d := geod.WGS84.Nearest(a[1], a[0], b[1], b[0], pos[1], pos[0])
+	return d
+}
+
+func pathDistance(pos []float64, path [][]float64) float64 {
+	d := math.Inf(1)
+	for i, p := range path {
+		if i == 0 {
+			d = math.Min(d, Distance(pos, p))
+			continue
+		}
+		d = math.Min(d, segmentDistance(pos, path[i-1], p))
+	}
+	return d
+}
+
+// insideRing returns true if pos lies inside a ring whose edges are
+// geodesics, which are taken to be great circles for the test. Inside
+// positions see an odd number of edges cross the meridian running north
+// from them, unless the ring runs around the north pole. Following the
+// right-hand rule, a shell that runs eastward around the globe and a hole
+// that runs westward contain the north pole.
+func insideRing(pos []float64, ring [][]float64, shell bool) bool {
+	tanLat := func(lat float64) float64 {
+		return math.Tan(math.Max(-90+1e-9, math.Min(90-1e-9, lat)) * degree)
+	}
+	inside := false
+	net := 0.0
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		xi := math.Remainder(ring[i][0]-pos[0], 360)
+		xj := math.Remainder(ring[j][0]-pos[0], 360)
+		net += math.Remainder(xi-xj, 360)
+		if (xi > 0) == (xj > 0) || math.Abs(xi-xj) >= 180 {
+			continue
+		}
+		// the latitude at which the great circle through the edge meets the
+		// meridian of pos, compared by its tangent
+		si, sj := math.Sin(xi*degree), math.Sin(xj*degree)
+		t := (tanLat(ring[i][1])*sj - tanLat(ring[j][1])*si) / math.Sin((xj-xi)*degree)
+		if t > tanLat(pos[1]) {
+			inside = !inside
+		}
+	}
+	if math.Abs(net) > 180 && (net > 0) == shell {
+		inside = !inside
+	}
+	return inside
+}
+
+func polygonDistance(pos []float64, rings [][][]float64) float64 {
+	if len(rings) == 0 {
+		return math.Inf(1)
+	}
+	inside := insideRing(pos, rings[0], true)
+	for _, hole := range rings[1:] {
+		if insideRing(pos, hole, false) {
+			inside = false
+		}
+	}
+	if inside {
+		return 0
+	}
+	d := math.Inf(1)
+	for _, ring := range rings {
+		d = math.Min(d, pathDistance(pos, ring))
+	}
+	return d
+}
+
+func geoDistance(pos []float64, g *geojson.Geo) (float64, error) {
+	d := math.Inf(1)
+	switch g.Type {
+	case "Point":
+		d = Distance(pos, g.Point.Coordinates)
+	case "MultiPoint":
+		for _, p := range g.MultiPoint.Coordinates {
+			d = math.Min(d, Distance(pos, p))
+		}
+	case "LineString":
+		d = pathDistance(pos, g.LineString.Coordinates)
+	case "MultiLineString":
+		for _, line := range g.MultiLineString.Coordinates {
+			d = math.Min(d, pathDistance(pos, line))
+		}
+	case "Polygon":
+		d = polygonDistance(pos, g.Polygon.Coordinates)
+	case "MultiPolygon":
+		for _, poly := range g.MultiPolygon.Coordinates {
+			d = math.Min(d, polygonDistance(pos, poly))
+		}
+	case "GeometryCollection":
+		for _, member := range g.GeometryCollection.Geometries {
+			dm, err := geoDistance(pos, member)
+			if err != nil {
+				return 0, err
+			}
+			d = math.Min(d, dm)
+		}
+	case "Feature":
+		return geoDistance(pos, &g.Feature.Geometry)
+	case "FeatureCollection":
+		for i := range g.FeatureCollection.Features {
+			dm, err := geoDistance(pos, &g.FeatureCollection.Features[i].Geometry)
+			if err != nil {
+				return 0, err
+			}
+			d = math.Min(d, dm)
+		}
+	default:
+		return 0, fmt.Errorf("unhandled type: '%s'", g.Type)
+	}
+	return d, nil
+}
+
+// DistanceToGeometry returns the shortest distance on the WGS84 ellipsoid
+// from a position to any geometry, Feature or FeatureCollection, whose
+// edges are taken to be geodesics. The distance is zero for positions
+// inside a Polygon. It returns an error for empty geometries.
+func DistanceToGeometry(pos []float64, g *geojson.Geo) (float64, error) {
+	d, err := geoDistance(pos, g)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsInf(d, 1) {
+		return 0, errors.New("distance to empty geometry")
+	}
+	return d, nil
+}
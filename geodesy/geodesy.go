@@ -0,0 +1,139 @@
+// Package geodesy computes distances, bearings and destinations between
+// geographic positions. Positions are GeoJSON longitude, latitude pairs in
+// degrees, such as the Coordinates of a Point, distances are in metres and
+// bearings are in degrees clockwise from north in [0, 360).
+//
+// Distance, bearing, destination and midpoint follow geodesics on the WGS84
+// ellipsoid using the algorithms of Karney (2013). Haversine and the Rhumb
+// functions work on a sphere of radius EarthRadius.
+package geodesy
+
+import (
+	"errors"
+	"math"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// EarthRadius is the mean radius of the WGS84 ellipsoid in metres, used for
+// spherical calculations
+const EarthRadius = 6371008.8
+
+const degree = math.Pi / 180
+
+// normalizeBearing returns a bearing in [0, 360)
+func normalizeBearing(b float64) float64 {
+	b = math.Mod(b, 360)
+	if b < 0 {
+		b += 360
+	}
+	return b + 0
+}
+
+// normalizeLongitude returns a longitude in [-180, 180]
+func normalizeLongitude(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+	return math.Remainder(lon, 360)
+}
+
+// Haversine returns the great circle distance between two positions on a
+// sphere of radius EarthRadius
+func Haversine(a, b []float64) float64 {
+	phi1, phi2 := a[1]*degree, b[1]*degree
+	dphi := phi2 - phi1
+	dlambda := (b[0] - a[0]) * degree
+	h := math.Pow(math.Sin(dphi/2), 2) + math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(dlambda/2), 2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// Vincenty returns the distance between two positions on the WGS84
+// ellipsoid using the iterative method of Vincenty (1975). It returns an
+// error for nearly antipodal positions, where the iteration fails to
+// converge; Distance has no such limitation.
+func Vincenty(a, b []float64) (float64, error) {
+	major := geod.WGS84.EquatorialRadius()
+	f := geod.WGS84.Flattening()
+	minor := major * (1 - f)
+
+	L := math.Remainder(b[0]-a[0], 360) * degree
+	U1 := math.Atan((1 - f) * math.Tan(a[1]*degree))
+	U2 := math.Atan((1 - f) * math.Tan(b[1]*degree))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	for iter := 0; iter != 200; iter++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, nil
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = L + (1-C)*f*sinAlpha*
+			(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda) > math.Pi {
+			break
+		}
+		if math.Abs(lambda-prev) < 1e-12 {
+			u2 := cos2Alpha * (major*major - minor*minor) / (minor * minor)
+			A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+			B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+			dSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return minor * A * (sigma - dSigma), nil
+		}
+	}
+	return math.NaN(), errors.New("Vincenty formula failed to converge")
+}
+
+// Inverse returns the distance between two positions along the geodesic on
+// the WGS84 ellipsoid, with the initial bearing at a and the final bearing
+// on arriving at b
+func Inverse(a, b []float64) (distance, initialBearing, finalBearing float64) {
+	s12, azi1, azi2 := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+	return s12, normalizeBearing(azi1), normalizeBearing(azi2)
+}
+
+// Distance returns the length of the geodesic between two positions on the
+// WGS84 ellipsoid, accurate to a few nanometres
+func Distance(a, b []float64) float64 {
+	s12, _, _ := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+	return s12
+}
+
+// InitialBearing returns the bearing at a of the geodesic from a to b
+func InitialBearing(a, b []float64) float64 {
+	_, azi1, _ := Inverse(a, b)
+	return azi1
+}
+
+// FinalBearing returns the bearing of the geodesic from a to b on arriving
+// at b
+func FinalBearing(a, b []float64) float64 {
+	_, _, azi2 := Inverse(a, b)
+	return azi2
+}
+
+// Destination returns the position reached by travelling a distance along
+// the geodesic leaving pos at the given bearing
+func Destination(pos []float64, distance, bearing float64) []float64 {
+	lat, lon, _ := geod.WGS84.Direct(pos[1], pos[0], bearing, distance)
+	return []float64{normalizeLongitude(lon), lat}
+}
+
+// Midpoint returns the position halfway along the geodesic from a to b
+func Midpoint(a, b []float64) []float64 {
+	s12, azi1, _ := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+	return Destination(a, s12/2, azi1)
+}
@@ -0,0 +1,212 @@
+package geodesy
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// Vincenty's test case between Flinders Peak and Buninyong
+var flinders = []float64{144.42486788889, -37.95103341667}
+var buninyong = []float64{143.92649552778, -37.65282113889}
+
+func expectClose(t *testing.T, name string, received, expected, tol float64) {
+	if math.Abs(received-expected) > tol {
+		fmt.Println(name)
+		fmt.Println("recieved    ", received)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	expectClose(t, "meridian", Haversine([]float64{0, 0}, []float64{0, 1}), EarthRadius*math.Pi/180, 1e-6)
+	expectClose(t, "antimeridian", Haversine([]float64{179.5, 0}, []float64{-179.5, 0}), EarthRadius*math.Pi/180, 1e-6)
+}
+
+func TestInverse(t *testing.T) {
+	d, azi1, azi2 := Inverse(flinders, buninyong)
+	expectClose(t, "distance", d, 54972.271, 1e-3)
+	expectClose(t, "initial bearing", azi1, 306+52.0/60+5.37/3600, 1e-5)
+	expectClose(t, "final bearing", azi2, 307+10.0/60+25.07/3600, 1e-5)
+	expectClose(t, "Distance", Distance(flinders, buninyong), d, 0)
+	expectClose(t, "InitialBearing", InitialBearing(flinders, buninyong), azi1, 0)
+	expectClose(t, "FinalBearing", FinalBearing(flinders, buninyong), azi2, 0)
+}
+
+func TestVincenty(t *testing.T) {
+	d, err := Vincenty(flinders, buninyong)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectClose(t, "Vincenty", d, Distance(flinders, buninyong), 1e-3)
+
+	d, err = Vincenty(flinders, flinders)
+	if err != nil || d != 0 {
+		t.Fail()
+	}
+
+	// nearly antipodal positions do not converge
+	if _, err = Vincenty([]float64{0, 0}, []float64{179.7, 0.5}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDestination(t *testing.T) {
+	pos := Destination(flinders, 54972.271, 306+52.0/60+5.37/3600)
+	expectClose(t, "longitude", pos[0], buninyong[0], 1e-8)
+	expectClose(t, "latitude", pos[1], buninyong[1], 1e-8)
+
+	// longitudes wrap across the antimeridian
+	pos = Destination([]float64{179.5, 0}, Distance([]float64{0, 0}, []float64{1, 0}), 90)
+	expectClose(t, "wrapped longitude", pos[0], -179.5, 1e-9)
+}
+
+func TestMidpoint(t *testing.T) {
+	pos := Midpoint([]float64{0, 0}, []float64{10, 0})
+	expectClose(t, "longitude", pos[0], 5, 1e-9)
+	expectClose(t, "latitude", pos[1], 0, 1e-9)
+
+	pos = Midpoint(flinders, buninyong)
+	expectClose(t, "halfway", Distance(flinders, pos), Distance(pos, buninyong), 1e-6)
+}
+
+func TestRhumb(t *testing.T) {
+	expectClose(t, "meridian", RhumbDistance([]float64{0, 0}, []float64{0, 10}), 10*EarthRadius*math.Pi/180, 1e-6)
+	expectClose(t, "north", RhumbBearing([]float64{0, 0}, []float64{0, 10}), 0, 1e-12)
+	expectClose(t, "east", RhumbBearing([]float64{179, 0}, []float64{-179, 0}), 90, 1e-12)
+
+	// the parallel at 60 degrees is half the length of the equator
+	expectClose(t, "parallel", RhumbDistance([]float64{0, 60}, []float64{10, 60}),
+		5*EarthRadius*math.Pi/180, 1e-6)
+
+	a, b := []float64{-5, 50}, []float64{-70, 40}
+	pos := RhumbDestination(a, RhumbDistance(a, b), RhumbBearing(a, b))
+	expectClose(t, "longitude", pos[0], b[0], 1e-9)
+	expectClose(t, "latitude", pos[1], b[1], 1e-9)
+
+	mid := RhumbMidpoint(a, b)
+	expectClose(t, "midpoint latitude", mid[1], 45, 1e-12)
+	expectClose(t, "halfway", RhumbDistance(a, mid), RhumbDistance(a, b)/2, 1e-6)
+	expectClose(t, "bearing", RhumbBearing(a, mid), RhumbBearing(a, b), 1e-9)
+}
+
+func TestRhumbDestinationPoles(t *testing.T) {
+	arc := EarthRadius * math.Pi / 180
+
+	// over the north pole and down the opposite meridian
+	pos := RhumbDestination([]float64{10, 80}, 20*arc, 0)
+	expectClose(t, "north longitude", pos[0], -170, 1e-9)
+	expectClose(t, "north latitude", pos[1], 80, 1e-9)
+	pos = RhumbDestination([]float64{10, -80}, 15*arc, 180)
+	expectClose(t, "south longitude", pos[0], -170, 1e-9)
+	expectClose(t, "south latitude", pos[1], -85, 1e-9)
+
+	// ending exactly at a pole, along a meridian or spiralling in
+	for _, bearing := range []float64{0, 45, 300} {
+		pos = RhumbDestination([]float64{10, 80}, 10*arc/math.Cos(bearing*math.Pi/180), bearing)
+		if math.IsNaN(pos[0]) || math.IsInf(pos[0], 0) {
+			fmt.Println("recieved    ", pos)
+			t.Fail()
+		}
+		expectClose(t, "pole latitude", pos[1], 90, 1e-9)
+	}
+}
+
+func TestDistanceToGeometry(t *testing.T) {
+	line := &geojson.Geo{Type: "LineString", LineString: &geojson.LineString{
+		Coordinates: [][]float64{{-1, 0}, {1, 0}},
+	}}
+	d, err := DistanceToGeometry([]float64{0, 1}, line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectClose(t, "line", d, Distance([]float64{0, 0}, []float64{0, 1}), 1e-6)
+
+	poly := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{
+		{{179, -1}, {-179, -1}, {-179, 1}, {179, 1}, {179, -1}},
+	}}}
+	d, err = DistanceToGeometry([]float64{180, 0}, poly)
+	if err != nil || d != 0 {
+		fmt.Println("recieved    ", d, err)
+		t.Fail()
+	}
+	d, _ = DistanceToGeometry([]float64{-178, 0}, poly)
+	expectClose(t, "polygon", d, Distance([]float64{-178, 0}, []float64{-179, 0}), 1e-6)
+
+	fc := &geojson.Geo{Type: "FeatureCollection", FeatureCollection: &geojson.FeatureCollection{
+		Features: []geojson.Feature{{Geometry: *line}, {Geometry: geojson.Geo{
+			Type: "Point", Point: &geojson.Point{Coordinates: []float64{0, 0.5}}}}},
+	}}
+	d, _ = DistanceToGeometry([]float64{0, 1}, fc)
+	expectClose(t, "collection", d, Distance([]float64{0, 0.5}, []float64{0, 1}), 1e-6)
+
+	empty := &geojson.Geo{Type: "MultiPoint", MultiPoint: &geojson.MultiPoint{}}
+	if _, err = DistanceToGeometry([]float64{0, 0}, empty); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDistanceInsidePolygon(t *testing.T) {
+	// the geodesic edges between 60W and 60E bow north, to about 67N and 74N
+	// on the central meridian
+	poly := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{
+		{{-60, 50}, {60, 50}, {60, 60}, {-60, 60}, {-60, 50}},
+	}}}
+	d, _ := DistanceToGeometry([]float64{0, 70}, poly)
+	if d != 0 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+	d, _ = DistanceToGeometry([]float64{0, 55}, poly)
+	if d < 100000 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+
+	// an eastward shell around the north pole contains it, and a westward
+	// hole around it removes it again
+	polar := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{
+		{{0, 70}, {90, 70}, {180, 70}, {-90, 70}, {0, 70}},
+	}}}
+	d, _ = DistanceToGeometry([]float64{45, 85}, polar)
+	if d != 0 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+	polar.Polygon.Coordinates = append(polar.Polygon.Coordinates,
+		[][]float64{{0, 80}, {-90, 80}, {180, 80}, {90, 80}, {0, 80}})
+	d, _ = DistanceToGeometry([]float64{45, 85}, polar)
+	if d == 0 {
+		t.Fail()
+	}
+	d, _ = DistanceToGeometry([]float64{45, 78}, polar)
+	if d != 0 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+}
+
+func TestDistanceToLongSegment(t *testing.T) {
+	// the closest point of a long oblique geodesic, found by searching along
+	// it on the ellipsoid rather than on a sphere
+	a, b, pos := []float64{-60, 50}, []float64{60, 20}, []float64{10, 75}
+	line := &geojson.Geo{Type: "LineString", LineString: &geojson.LineString{Coordinates: [][]float64{a, b}}}
+	d, err := DistanceToGeometry(pos, line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s12, azi1, _ := Inverse(a, b)
+	lo, hi := 0.0, s12
+	for i := 0; i != 200; i++ {
+		m1, m2 := lo+(hi-lo)/3, hi-(hi-lo)/3
+		if Distance(pos, Destination(a, m1, azi1)) < Distance(pos, Destination(a, m2, azi1)) {
+			hi = m2
+		} else {
+			lo = m1
+		}
+	}
+	expectClose(t, "long segment", d, Distance(pos, Destination(a, lo, azi1)), 1e-6)
+}
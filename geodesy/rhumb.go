@@ -0,0 +1,82 @@
+package geodesy
+
+import "math"
+
+// rhumbStretch returns the ratio of the change in latitude to the change in
+// isometric latitude between two latitudes in radians, which scales a
+// change in longitude into distance along a rhumb line
+func rhumbStretch(phi1, phi2 float64) float64 {
+	dpsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	if math.Abs(dpsi) > 1e-12 {
+		return (phi2 - phi1) / dpsi
+	}
+	return math.Cos(phi1)
+}
+
+// rhumb returns the change in isometric latitude and the change in longitude
+// in radians, taking the shorter way around, along the rhumb line from a
+// to b
+func rhumb(a, b []float64) (float64, float64) {
+	phi1, phi2 := a[1]*degree, b[1]*degree
+	dpsi := math.Log(math.Tan(math.Pi/4+phi2/2) / math.Tan(math.Pi/4+phi1/2))
+	return dpsi, math.Remainder(b[0]-a[0], 360) * degree
+}
+
+// RhumbDistance returns the distance along the rhumb line, or loxodrome,
+// from a to b on a sphere of radius EarthRadius. A rhumb line crosses every
+// meridian at the same bearing.
+func RhumbDistance(a, b []float64) float64 {
+	phi1, phi2 := a[1]*degree, b[1]*degree
+	_, dlambda := rhumb(a, b)
+	q := rhumbStretch(phi1, phi2)
+	return EarthRadius * math.Hypot(phi2-phi1, q*dlambda)
+}
+
+// RhumbBearing returns the constant bearing of the rhumb line from a to b
+func RhumbBearing(a, b []float64) float64 {
+	dpsi, dlambda := rhumb(a, b)
+	return normalizeBearing(math.Atan2(dlambda, dpsi) / degree)
+}
+
+// RhumbDestination returns the position reached by travelling a distance
+// along the rhumb line leaving pos at the given bearing, on a sphere of
+// radius EarthRadius. A rhumb line that is not a meridian spirals into the
+// pole, so a path reaching a pole ends there with the longitude of pos, and
+// one that would pass over it is reflected down the opposite meridian.
+func RhumbDestination(pos []float64, distance, bearing float64) []float64 {
+	delta := distance / EarthRadius
+	theta := bearing * degree
+	phi1 := pos[1] * degree
+	phi2 := phi1 + delta*math.Cos(theta)
+	if over := math.Abs(phi2) - math.Pi/2; over > -1e-12 {
+		if over <= 1e-12 {
+			return []float64{normalizeLongitude(pos[0]), math.Copysign(90, phi2)}
+		}
+		phi2 = math.Copysign(math.Pi, phi2) - phi2
+		return []float64{normalizeLongitude(pos[0] + 180), phi2 / degree}
+	}
+	dlambda := 0.0
+	if q := rhumbStretch(phi1, phi2); q != 0 {
+		dlambda = delta * math.Sin(theta) / q
+	}
+	return []float64{normalizeLongitude(pos[0] + dlambda/degree), phi2 / degree}
+}
+
+// RhumbMidpoint returns the position halfway along the rhumb line from a
+// to b
+func RhumbMidpoint(a, b []float64) []float64 {
+	phi1, phi2 := a[1]*degree, b[1]*degree
+	lambda1 := a[0] * degree
+	_, dlambda := rhumb(a, b)
+	lambda2 := lambda1 + dlambda
+	phim := (phi1 + phi2) / 2
+	f1 := math.Tan(math.Pi/4 + phi1/2)
+	f2 := math.Tan(math.Pi/4 + phi2/2)
+	fm := math.Tan(math.Pi/4 + phim/2)
+	lambdam := ((lambda2-lambda1)*math.Log(fm) + lambda1*math.Log(f2) - lambda2*math.Log(f1)) /
+		math.Log(f2/f1)
+	if math.IsNaN(lambdam) || math.IsInf(lambdam, 0) || phi1 == phi2 {
+		lambdam = (lambda1 + lambda2) / 2
+	}
+	return []float64{normalizeLongitude(lambdam / degree), phim / degree}
+}
@@ -0,0 +1,308 @@
+/* functions for encoding positions as geohashes and covering geometries with geohash cells */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// maxGeohashPrecision keeps the interleaved cell indices within 64 bits
+const maxGeohashPrecision = 12
+
+// geohashCell identifies a geohash by its integer column and row at a given
+// precision
+type geohashCell struct {
+	ix, iy           uint64
+	lonBits, latBits uint
+	precision        int
+}
+
+func newGeohashCell(precision int) (geohashCell, error) {
+	if precision < 1 || precision > maxGeohashPrecision {
+		return geohashCell{}, fmt.Errorf("geohash precision must be between 1 and %d", maxGeohashPrecision)
+	}
+	nbits := uint(5 * precision)
+	return geohashCell{lonBits: (nbits + 1) / 2, latBits: nbits / 2, precision: precision}, nil
+}
+
+// size returns the width and height of a cell in degrees
+func (c geohashCell) size() (float64, float64) {
+	return 360.0 / float64(uint64(1)<<c.lonBits), 180.0 / float64(uint64(1)<<c.latBits)
+}
+
+// locate sets the cell indices to the cell containing lon, lat
+func (c *geohashCell) locate(lon, lat float64) {
+	w, h := c.size()
+	c.ix = clampIndex(math.Floor((lon+180.0)/w), c.lonBits)
+	c.iy = clampIndex(math.Floor((lat+90.0)/h), c.latBits)
+}
+
+func clampIndex(v float64, bits uint) uint64 {
+	if v < 0 {
+		return 0
+	}
+	max := float64(uint64(1)<<bits - 1)
+	if v > max {
+		return uint64(max)
+	}
+	return uint64(v)
+}
+
+// bbox returns the cell extent as [xmin, ymin, xmax, ymax]
+func (c geohashCell) bbox() [4]float64 {
+	w, h := c.size()
+	x0 := -180.0 + float64(c.ix)*w
+	y0 := -90.0 + float64(c.iy)*h
+	return [4]float64{x0, y0, x0 + w, y0 + h}
+}
+
+func (c geohashCell) String() string {
+	var sb strings.Builder
+	var bit, ch uint
+	lonBit := c.lonBits
+	latBit := c.latBits
+	for i := uint(0); i != 5*uint(c.precision); i++ {
+		if i%2 == 0 {
+			lonBit--
+			bit = uint((c.ix >> lonBit) & 1)
+		} else {
+			latBit--
+			bit = uint((c.iy >> latBit) & 1)
+		}
+		ch = ch<<1 | bit
+		if i%5 == 4 {
+			sb.WriteByte(geohashAlphabet[ch])
+			ch = 0
+		}
+	}
+	return sb.String()
+}
+
+func parseGeohash(hash string) (geohashCell, error) {
+	c, err := newGeohashCell(len(hash))
+	if err != nil {
+		return c, err
+	}
+	var i uint
+	for _, r := range strings.ToLower(hash) {
+		v := strings.IndexRune(geohashAlphabet, r)
+		if v < 0 {
+			return c, fmt.Errorf("invalid geohash character: '%c'", r)
+		}
+		for b := 4; b >= 0; b-- {
+			bit := uint64(v>>uint(b)) & 1
+			if i%2 == 0 {
+				c.ix = c.ix<<1 | bit
+			} else {
+				c.iy = c.iy<<1 | bit
+			}
+			i++
+		}
+	}
+	return c, nil
+}
+
+// Geohash encodes the Point as a geohash string with precision characters
+func (p *Point) Geohash(precision int) (string, error) {
+	if len(p.Coordinates) < 2 {
+		return "", errors.New("point has fewer than two coordinates")
+	}
+	c, err := newGeohashCell(precision)
+	if err != nil {
+		return "", err
+	}
+	c.locate(p.Coordinates[0], p.Coordinates[1])
+	return c.String(), nil
+}
+
+// DecodeGeohash returns the cell referred to by a geohash as a rectangular
+// Polygon
+func DecodeGeohash(hash string) (*Polygon, error) {
+	c, err := parseGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return bboxPolygon(c.bbox()), nil
+}
+
+// GeohashNeighbours returns the geohashes adjacent to hash, in the order N, NE,
+// E, SE, S, SW, W, NW. Neighbours wrap across the antimeridian. Cells touching
+// a pole have no neighbours beyond it, so fewer than eight may be returned.
+func GeohashNeighbours(hash string) ([]string, error) {
+	c, err := parseGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+	offsets := [8][2]int64{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+	ncols := int64(1) << c.lonBits
+	nrows := int64(1) << c.latBits
+	neighbours := []string{}
+	for _, off := range offsets {
+		row := int64(c.iy) + off[1]
+		if row < 0 || row >= nrows {
+			continue
+		}
+		col := ((int64(c.ix)+off[0])%ncols + ncols) % ncols
+		n := c
+		n.ix = uint64(col)
+		n.iy = uint64(row)
+		neighbours = append(neighbours, n.String())
+	}
+	return neighbours, nil
+}
+
+// geohashCover returns the sorted geohashes within the extents of a
+// geometry's parts for which intersects returns true for that part. Parts
+// are expected to have been cut at the antimeridian, so that their extents
+// do not wrap.
+func geohashCover(parts [][][]float64, precision int, intersects func(part int, cell [4]float64) bool) ([]string, error) {
+	seen := make(map[string]bool)
+	hashes := []string{}
+	for i, part := range parts {
+		lo, err := newGeohashCell(precision)
+		if err != nil {
+			return nil, err
+		}
+		bb, err := (&LineString{Coordinates: part}).Bbox()
+		if err != nil {
+			return nil, err
+		}
+		hi := lo
+		lo.locate(bb.xmin, bb.ymin)
+		hi.locate(bb.xmax, bb.ymax)
+		c := lo
+		for c.iy = lo.iy; c.iy <= hi.iy; c.iy++ {
+			for c.ix = lo.ix; c.ix <= hi.ix; c.ix++ {
+				if hash := c.String(); !seen[hash] && intersects(i, c.bbox()) {
+					seen[hash] = true
+					hashes = append(hashes, hash)
+				}
+			}
+		}
+	}
+	sort.Strings(hashes)
+	return hashes, nil
+}
+
+// segmentInCell returns true if the segment p0-p1 meets a geohash cell.
+// Like the cells of locate, a cell includes its west and south edges but not
+// its east and north edges, except where those lie at 180 degrees longitude
+// or 90 degrees latitude.
+func segmentInCell(p0, p1 []float64, cell [4]float64) bool {
+	t0, t1, ok := liangBarsky(p0, p1, cell)
+	if !ok {
+		return false
+	}
+	at := func(t float64, k int) float64 {
+		if t == 1 {
+			return p1[k]
+		}
+		return p0[k] + t*(p1[k]-p0[k])
+	}
+	// the part of the segment within the closed cell lies only along an
+	// excluded edge
+	onEdge := func(k int, limit float64) bool {
+		edge := cell[k+2]
+		return edge != limit && at(t0, k) >= edge && at(t1, k) >= edge
+	}
+	return !onEdge(0, 180) && !onEdge(1, 90)
+}
+
+// polygonInCell returns true if the interior of a polygon meets the interior
+// of a cell, so that polygons bounded by cell edges do not take in their
+// neighbours
+func polygonInCell(rings [][][]float64, cell [4]float64) bool {
+	centre := []float64{(cell[0] + cell[2]) / 2, (cell[1] + cell[3]) / 2}
+	if pointInPolygon(centre, rings) == 1 {
+		return true
+	}
+	for _, ring := range rings {
+		for i := 1; i < len(ring); i++ {
+			p0, p1 := ring[i-1], ring[i]
+			t0, t1, ok := liangBarsky(p0, p1, cell)
+			if !ok {
+				continue
+			}
+			t := (t0 + t1) / 2
+			x, y := p0[0]+t*(p1[0]-p0[0]), p0[1]+t*(p1[1]-p0[1])
+			if x > cell[0] && x < cell[2] && y > cell[1] && y < cell[3] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GeohashCover returns the sorted set of geohashes at the given precision
+// that intersect the LineString. Edges take the shorter way around, so that
+// lines crossing the antimeridian are split there.
+func (g *LineString) GeohashCover(precision int) ([]string, error) {
+	if len(g.Coordinates) == 0 {
+		return nil, errors.New("empty LineString")
+	}
+	cut, err := g.CutAntimeridian()
+	if err != nil {
+		return nil, err
+	}
+	var lines [][][]float64
+	if cut.Type == "LineString" {
+		lines = [][][]float64{cut.LineString.Coordinates}
+	} else {
+		lines = cut.MultiLineString.Coordinates
+	}
+	if len(lines) == 0 {
+		// every position is the same
+		lines = [][][]float64{{wrapPosition(g.Coordinates[0])}}
+	}
+	return geohashCover(lines, precision, func(part int, cell [4]float64) bool {
+		line := lines[part]
+		if len(line) == 1 {
+			return segmentInCell(line[0], line[0], cell)
+		}
+		for i := 1; i != len(line); i++ {
+			if segmentInCell(line[i-1], line[i], cell) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// GeohashCover returns the sorted set of geohashes at the given precision
+// whose interiors meet the interior of the Polygon. Polygons crossing the
+// antimeridian or around a pole are cut as by CutAntimeridian.
+func (g *Polygon) GeohashCover(precision int) ([]string, error) {
+	if len(g.Coordinates) == 0 || len(g.Coordinates[0]) == 0 {
+		return nil, errors.New("empty Polygon")
+	}
+	cut, err := g.CutAntimeridian()
+	if err != nil {
+		return nil, err
+	}
+	var polys [][][][]float64
+	if cut.Type == "Polygon" {
+		polys = [][][][]float64{cut.Polygon.Coordinates}
+	} else {
+		polys = cut.MultiPolygon.Coordinates
+	}
+	shells := make([][][]float64, len(polys))
+	for i, poly := range polys {
+		shells[i] = poly[0]
+	}
+	return geohashCover(shells, precision, func(part int, cell [4]float64) bool {
+		return polygonInCell(polys[part], cell)
+	})
+}
+
+// bboxPolygon returns a counter-clockwise rectangular Polygon for an extent
+// [xmin, ymin, xmax, ymax]
+func bboxPolygon(bb [4]float64) *Polygon {
+	return &Polygon{Coordinates: [][][]float64{{
+		{bb[0], bb[1]}, {bb[2], bb[1]}, {bb[2], bb[3]}, {bb[0], bb[3]}, {bb[0], bb[1]},
+	}}}
+}
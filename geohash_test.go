@@ -0,0 +1,245 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestGeohashEncode(t *testing.T) {
+	pt := &Point{Coordinates: []float64{-5.6, 42.6}}
+	hash, err := pt.Geohash(5)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hash != "ezs42" {
+		fmt.Println("recieved    ", hash)
+		fmt.Println("but expected", "ezs42")
+		t.Fail()
+	}
+
+	pt = &Point{Coordinates: []float64{10.40744, 57.64911}}
+	hash, err = pt.Geohash(11)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hash != "u4pruydqqvj" {
+		fmt.Println("recieved    ", hash)
+		fmt.Println("but expected", "u4pruydqqvj")
+		t.Fail()
+	}
+}
+
+func TestGeohashInvalidPrecision(t *testing.T) {
+	pt := &Point{Coordinates: []float64{0, 0}}
+	if _, err := pt.Geohash(0); err == nil {
+		t.Fail()
+	}
+	if _, err := pt.Geohash(13); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDecodeGeohash(t *testing.T) {
+	poly, err := DecodeGeohash("ezs42")
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	ring := poly.Coordinates[0]
+	if len(ring) != 5 {
+		t.Fail()
+	}
+	expected := []float64{-5.625, 42.5830078125, -5.5810546875, 42.626953125}
+	got := []float64{ring[0][0], ring[0][1], ring[2][0], ring[2][1]}
+	for i := range expected {
+		if math.Abs(got[i]-expected[i]) > 1e-12 {
+			fmt.Println("recieved    ", got)
+			fmt.Println("but expected", expected)
+			t.Fail()
+			break
+		}
+	}
+	if !isCounterClockwise(ring) {
+		t.Fail()
+	}
+
+	if _, err = DecodeGeohash("ezs4a"); err == nil {
+		t.Fail()
+	}
+}
+
+func bboxCenter(poly *Polygon) (float64, float64, float64, float64) {
+	ring := poly.Coordinates[0]
+	return 0.5 * (ring[0][0] + ring[2][0]), 0.5 * (ring[0][1] + ring[2][1]),
+		ring[2][0] - ring[0][0], ring[2][1] - ring[0][1]
+}
+
+func TestGeohashNeighbours(t *testing.T) {
+	neighbours, err := GeohashNeighbours("ezs42")
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(neighbours) != 8 {
+		t.Fatal("expected eight neighbours")
+	}
+	if neighbours[0] != "ezs48" || neighbours[2] != "ezs43" || neighbours[4] != "ezs40" || neighbours[6] != "ezefr" {
+		fmt.Println("recieved", neighbours)
+		t.Fail()
+	}
+
+	cell, _ := DecodeGeohash("ezs42")
+	x, y, w, h := bboxCenter(cell)
+	offsets := [][2]float64{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+	for i, hash := range neighbours {
+		poly, _ := DecodeGeohash(hash)
+		nx, ny, _, _ := bboxCenter(poly)
+		if math.Abs(nx-x-offsets[i][0]*w) > 1e-9 || math.Abs(ny-y-offsets[i][1]*h) > 1e-9 {
+			fmt.Println("neighbour", i, hash, "is misplaced")
+			t.Fail()
+		}
+	}
+}
+
+func TestGeohashNeighboursWrap(t *testing.T) {
+	pt := &Point{Coordinates: []float64{179.9, 89.9}}
+	hash, _ := pt.Geohash(3)
+	neighbours, err := GeohashNeighbours(hash)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	// no neighbours north of the pole
+	if len(neighbours) != 5 {
+		fmt.Println("recieved", neighbours)
+		t.Fail()
+	}
+	// eastern neighbour wraps around to the western hemisphere
+	poly, _ := DecodeGeohash(neighbours[0])
+	if poly.Coordinates[0][0][0] != -180 {
+		t.Fail()
+	}
+}
+
+func TestGeohashCoverLineString(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{-170, 10}, {-100, 10}}}
+	hashes, err := ls.GeohashCover(1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	// precision 1 cells are 45 degrees wide; the line spans two of them
+	expected := []string{"8", "9"}
+	if fmt.Sprint(hashes) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestGeohashCoverPolygon(t *testing.T) {
+	// a triangle that excludes the upper-right cell of its bounding box
+	poly := &Polygon{Coordinates: [][][]float64{{
+		{0.1, 0.1}, {89.9, 0.1}, {0.1, 44.9}, {0.1, 0.1},
+	}}}
+	hashes, err := poly.GeohashCover(1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := []string{"s", "t"}
+	if fmt.Sprint(hashes) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	// a hole covering an entire cell removes it from the cover
+	poly = &Polygon{Coordinates: [][][]float64{
+		{{0.1, -44.9}, {134.9, -44.9}, {134.9, 89.9}, {0.1, 89.9}, {0.1, -44.9}},
+		{{44.9, -0.1}, {90.1, -0.1}, {90.1, 45.1}, {44.9, 45.1}, {44.9, -0.1}},
+	}}
+	hashes, err = poly.GeohashCover(1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected = []string{"k", "m", "q", "s", "u", "v", "w", "y"}
+	if fmt.Sprint(hashes) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestGeohashCoverAntimeridian(t *testing.T) {
+	// the short way across the antimeridian touches the first and last
+	// columns rather than every column between them
+	ls := &LineString{Coordinates: [][]float64{{179.9, 10}, {-179.9, 10}}}
+	hashes, err := ls.GeohashCover(2)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := []string{"81", "xc"}
+	if fmt.Sprint(hashes) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	poly := &Polygon{Coordinates: [][][]float64{{{179.9, 10}, {-179.9, 10}, {-179.9, 11}, {179.9, 11}, {179.9, 10}}}}
+	hashes, err = poly.GeohashCover(2)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if fmt.Sprint(hashes) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestGeohashCoverCellEdges(t *testing.T) {
+	// a cell covers only itself, not the neighbours sharing its edges
+	cell, _ := DecodeGeohash("u4pru")
+	hashes, err := cell.GeohashCover(5)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if fmt.Sprint(hashes) != "[u4pru]" {
+		fmt.Println("recieved    ", hashes)
+		t.Fail()
+	}
+
+	// a line along the top edge of a cell belongs to the cell above it,
+	// except at the north pole
+	bb := cell.Coordinates[0]
+	ls := &LineString{Coordinates: [][]float64{{bb[0][0] + 0.001, bb[2][1]}, {bb[2][0] - 0.001, bb[2][1]}}}
+	hashes, err = ls.GeohashCover(5)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	neighbours, _ := GeohashNeighbours("u4pru")
+	if fmt.Sprint(hashes) != fmt.Sprint(neighbours[:1]) {
+		fmt.Println("recieved    ", hashes)
+		fmt.Println("but expected", neighbours[:1])
+		t.Fail()
+	}
+	ls = &LineString{Coordinates: [][]float64{{170, 90}, {180, 90}}}
+	hashes, err = ls.GeohashCover(1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if fmt.Sprint(hashes) != "[z]" {
+		fmt.Println("recieved    ", hashes)
+		t.Fail()
+	}
+}
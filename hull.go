@@ -0,0 +1,330 @@
+/* functions for computing convex and concave hulls */
+package geojson
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/njwilson23/geojson.go/internal/delaunay"
+)
+
+// positions returns every position in the components
+func (c *components) positions() [][]float64 {
+	positions := append([][]float64{}, c.points...)
+	for _, line := range c.lines {
+		positions = append(positions, line...)
+	}
+	for _, poly := range c.polygons {
+		for _, ring := range poly {
+			positions = append(positions, ring...)
+		}
+	}
+	return positions
+}
+
+// convexHull returns the distinct vertices of the convex hull of positions in
+// counter-clockwise order, without repeating the first vertex. Collinear
+// vertices are dropped. Uses Andrew's monotone chain algorithm.
+func convexHull(positions [][]float64) [][]float64 {
+	pts := make([][]float64, 0, len(positions))
+	for _, pos := range positions {
+		if len(pos) >= 2 {
+			pts = append(pts, pos)
+		}
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] == pts[j][0] {
+			return pts[i][1] < pts[j][1]
+		}
+		return pts[i][0] < pts[j][0]
+	})
+	unique := pts[:0]
+	for i, pos := range pts {
+		if i == 0 || pos[0] != pts[i-1][0] || pos[1] != pts[i-1][1] {
+			unique = append(unique, pos)
+		}
+	}
+	pts = unique
+	if len(pts) < 3 {
+		return pts
+	}
+
+	hull := make([][]float64, 0, 2*len(pts))
+	for _, pos := range pts {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], pos) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pos)
+	}
+	lower := len(hull) + 1
+	for i := len(pts) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], pts[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, pts[i])
+	}
+	return hull[:len(hull)-1]
+}
+
+// hullGeo wraps the vertices of a hull as a Point, LineString or Polygon
+// depending on how many there are
+func hullGeo(hull [][]float64) *Geo {
+	coords := make([][]float64, len(hull))
+	for i, pos := range hull {
+		coords[i] = []float64{pos[0], pos[1]}
+	}
+	switch len(coords) {
+	case 1:
+		return &Geo{Type: "Point", Point: &Point{Coordinates: coords[0]}}
+	case 2:
+		return &Geo{Type: "LineString", LineString: &LineString{Coordinates: coords}}
+	}
+	coords = append(coords, []float64{coords[0][0], coords[0][1]})
+	return &Geo{Type: "Polygon", Polygon: &Polygon{Coordinates: [][][]float64{coords}}}
+}
+
+func convexHullGeo(g *Geo) (*Geo, error) {
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return nil, err
+	}
+	hull := convexHull(c.positions())
+	if len(hull) == 0 {
+		return nil, errors.New("convex hull of empty geometry")
+	}
+	return hullGeo(hull), nil
+}
+
+// ConvexHull returns the Point itself
+func (g *Point) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "Point", Point: g})
+}
+
+// ConvexHull returns the smallest convex Polygon containing the LineString,
+// or a LineString if its vertices are collinear
+func (g *LineString) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "LineString", LineString: g})
+}
+
+// ConvexHull returns the smallest convex Polygon containing the Polygon
+func (g *Polygon) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// ConvexHull returns the smallest convex Polygon containing the MultiPoint,
+// or a Point or LineString if its positions are coincident or collinear
+func (g *MultiPoint) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "MultiPoint", MultiPoint: g})
+}
+
+// ConvexHull returns the smallest convex Polygon containing the
+// MultiLineString, or a LineString if its vertices are collinear
+func (g *MultiLineString) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "MultiLineString", MultiLineString: g})
+}
+
+// ConvexHull returns the smallest convex Polygon containing the MultiPolygon
+func (g *MultiPolygon) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// ConvexHull returns the convex hull of every member of the
+// GeometryCollection
+func (coll *GeometryCollection) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll})
+}
+
+// ConvexHull returns the convex hull of the Feature geometry
+func (f *Feature) ConvexHull() (*Geo, error) {
+	return convexHullGeo(&f.Geometry)
+}
+
+// ConvexHull returns the smallest convex geometry containing any geometry,
+// Feature or FeatureCollection. The result is a counter-clockwise Polygon,
+// or a Point or LineString when the input positions are all coincident or
+// collinear.
+func (g *Geo) ConvexHull() (*Geo, error) {
+	return convexHullGeo(g)
+}
+
+// borderEdge is a triangle queued for erosion by the length of its border
+type borderEdge struct {
+	tri    int
+	length float64
+}
+
+type borderQueue []borderEdge
+
+func (q borderQueue) Len() int            { return len(q) }
+func (q borderQueue) Less(i, j int) bool  { return q[i].length > q[j].length }
+func (q borderQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *borderQueue) Push(x interface{}) { *q = append(*q, x.(borderEdge)) }
+func (q *borderQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+func edgeLength(t *delaunay.Triangulation, e int) float64 {
+	a := t.Points[t.Triangles[e]]
+	b := t.Points[t.Triangles[delaunay.Next(e)]]
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// isBorder returns true if half-edge e of a kept triangle has no kept
+// triangle on its other side
+func isBorder(t *delaunay.Triangulation, keep []bool, e int) bool {
+	opp := t.Halfedges[e]
+	return opp == -1 || !keep[opp/3]
+}
+
+// erodeTriangulation removes triangles from the outside of a triangulation
+// in order of decreasing border edge length, until every border edge is no
+// longer than maxEdgeLength. A triangle is only removed when doing so keeps
+// every vertex on the region and the boundary a single simple ring.
+func erodeTriangulation(t *delaunay.Triangulation, maxEdgeLength float64) []bool {
+	ntri := len(t.Triangles) / 3
+	keep := make([]bool, ntri)
+	for i := range keep {
+		keep[i] = true
+	}
+	onBoundary := make([]bool, len(t.Points))
+	queue := &borderQueue{}
+	for e, opp := range t.Halfedges {
+		if opp == -1 {
+			onBoundary[t.Triangles[e]] = true
+			heap.Push(queue, borderEdge{e / 3, edgeLength(t, e)})
+		}
+	}
+
+	for queue.Len() != 0 {
+		item := heap.Pop(queue).(borderEdge)
+		if item.length <= maxEdgeLength {
+			break
+		}
+		if !keep[item.tri] {
+			continue
+		}
+		border, nborder := -1, 0
+		for e := 3 * item.tri; e != 3*item.tri+3; e++ {
+			if isBorder(t, keep, e) {
+				border = e
+				nborder++
+			}
+		}
+		// triangles with more than one border edge would strand a vertex
+		if nborder != 1 || edgeLength(t, border) != item.length {
+			continue
+		}
+		apex := t.Triangles[delaunay.Prev(border)]
+		if onBoundary[apex] {
+			continue
+		}
+		keep[item.tri] = false
+		onBoundary[apex] = true
+		for _, e := range []int{delaunay.Next(border), delaunay.Prev(border)} {
+			if opp := t.Halfedges[e]; opp != -1 && keep[opp/3] {
+				heap.Push(queue, borderEdge{opp / 3, edgeLength(t, opp)})
+			}
+		}
+	}
+	return keep
+}
+
+// triangleRegions returns the polygons formed by the union of the kept
+// triangles. Shells are counter-clockwise and holes clockwise.
+func triangleRegions(t *delaunay.Triangulation, keep []bool) [][][][]float64 {
+	var borders [][2][]float64
+	for e := range t.Triangles {
+		if keep[e/3] && isBorder(t, keep, e) {
+			borders = append(borders, [2][]float64{
+				t.Points[t.Triangles[e]], t.Points[t.Triangles[delaunay.Next(e)]],
+			})
+		}
+	}
+	return assembleRings(borders, false)
+}
+
+func concaveHull(g *Geo, maxEdgeLength float64, allowDisjoint bool) (*Geo, error) {
+	if maxEdgeLength < 0 || math.IsNaN(maxEdgeLength) {
+		return nil, errors.New("maximum edge length must not be negative")
+	}
+	c := new(components)
+	if err := c.add(g); err != nil {
+		return nil, err
+	}
+	hull := convexHull(c.positions())
+	if len(hull) < 3 {
+		if len(hull) == 0 {
+			return nil, errors.New("concave hull of empty geometry")
+		}
+		return hullGeo(hull), nil
+	}
+
+	// triangulate the distinct positions
+	var points [][]float64
+	seen := make(map[[2]float64]bool)
+	for _, pos := range c.positions() {
+		if len(pos) < 2 || seen[[2]float64{pos[0], pos[1]}] {
+			continue
+		}
+		seen[[2]float64{pos[0], pos[1]}] = true
+		points = append(points, pos)
+	}
+	t := delaunay.Triangulate(points)
+
+	var keep []bool
+	if allowDisjoint {
+		keep = make([]bool, len(t.Triangles)/3)
+		for i := range keep {
+			keep[i] = edgeLength(t, 3*i) <= maxEdgeLength &&
+				edgeLength(t, 3*i+1) <= maxEdgeLength &&
+				edgeLength(t, 3*i+2) <= maxEdgeLength
+		}
+	} else {
+		keep = erodeTriangulation(t, maxEdgeLength)
+	}
+
+	polygons := triangleRegions(t, keep)
+	switch len(polygons) {
+	case 0:
+		return nil, errors.New("no triangles have edges shorter than the maximum edge length")
+	case 1:
+		return &Geo{Type: "Polygon", Polygon: &Polygon{Coordinates: polygons[0]}}, nil
+	}
+	return &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: polygons}}, nil
+}
+
+// ConcaveHull returns a Polygon enclosing every position of the MultiPoint
+// that follows its outline more closely than the convex hull. See
+// Geo.ConcaveHull.
+func (g *MultiPoint) ConcaveHull(maxEdgeLength float64, allowDisjoint bool) (*Geo, error) {
+	return concaveHull(&Geo{Type: "MultiPoint", MultiPoint: g}, maxEdgeLength, allowDisjoint)
+}
+
+// ConcaveHull returns a hull of the positions of any geometry, Feature or
+// FeatureCollection that follows their outline more closely than the convex
+// hull. It is built from the Delaunay triangulation of the positions.
+// maxEdgeLength, in coordinate units, sets the concavity: smaller values
+// give tighter hulls, and values at least as long as the longest triangle
+// edge give the convex hull.
+//
+// By default triangles are eroded from the outside of the triangulation
+// while their outer edge is longer than maxEdgeLength, which always gives a
+// single Polygon without holes that contains every position. With
+// allowDisjoint, every triangle with an edge longer than maxEdgeLength is
+// discarded instead, so that widely separated clusters produce a
+// MultiPolygon and large empty areas become holes; positions far from all
+// others are then left outside the hull.
+//
+// Every position on the outline of the hull is one of its vertices, even
+// where it lies on the straight line between its neighbours.
+//
+// Coincident or collinear positions give a Point or LineString as for
+// ConvexHull.
+func (g *Geo) ConcaveHull(maxEdgeLength float64, allowDisjoint bool) (*Geo, error) {
+	return concaveHull(g, maxEdgeLength, allowDisjoint)
+}
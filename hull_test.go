@@ -0,0 +1,215 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestConvexHullMultiPoint(t *testing.T) {
+	mp := &MultiPoint{Coordinates: [][]float64{{0, 0}, {4, 0}, {2, 1}, {4, 4}, {1, 3}, {0, 4}, {2, 0}}}
+	hull, err := mp.ConvexHull()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", hull.Type)
+	}
+	ring := hull.Polygon.Coordinates[0]
+	// the collinear point {2, 0} is not a vertex
+	if len(ring) != 5 {
+		fmt.Println("recieved    ", ring)
+		t.Fail()
+	}
+	if !isCounterClockwise(ring) || ringArea(ring) != 16 {
+		t.Fail()
+	}
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	mp := &MultiPoint{Coordinates: [][]float64{{1, 1}, {1, 1}}}
+	hull, err := mp.ConvexHull()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Point" {
+		fmt.Println("recieved    ", hull.Type)
+		t.Fail()
+	}
+
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {2, 2}, {1, 1}, {3, 3}}}
+	hull, err = ls.ConvexHull()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "LineString" {
+		t.Fatal("expected a LineString, got", hull.Type)
+	}
+	coords := hull.LineString.Coordinates
+	if len(coords) != 2 || coords[0][0] != 0 || coords[1][0] != 3 {
+		fmt.Println("recieved    ", coords)
+		t.Fail()
+	}
+
+	if _, err = (&MultiPoint{}).ConvexHull(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestConvexHullCollection(t *testing.T) {
+	coll := &GeometryCollection{Geometries: []*Geo{
+		polygonGeo(square(0, 0, 1)),
+		lineGeo([]float64{3, 0}, []float64{3, 1}),
+		pointGeo(5, 5),
+	}}
+	hull, err := coll.ConvexHull()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	// the line's lower end and the square's far corners are inside the hull
+	if hull.Type != "Polygon" || len(hull.Polygon.Coordinates[0]) != 5 {
+		fmt.Println("recieved    ", hull.Polygon.Coordinates)
+		t.Fail()
+	}
+}
+
+// uShape returns a grid of points with a notch cut from the top
+func uShape() *Geo {
+	var coords [][]float64
+	for x := 0.0; x <= 6; x++ {
+		for y := 0.0; y <= 6; y++ {
+			if x >= 2 && x <= 4 && y >= 2 {
+				continue
+			}
+			coords = append(coords, []float64{x, y})
+		}
+	}
+	return &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{Coordinates: coords}}
+}
+
+func TestConcaveHull(t *testing.T) {
+	g := uShape()
+	hull, err := g.ConcaveHull(100, false)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Polygon" || hull.Polygon.Area() != 36 {
+		fmt.Println("recieved    ", hull.Type, hull.Polygon.Area())
+		t.Fail()
+	}
+
+	hull, err = g.ConcaveHull(1.5, false)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", hull.Type)
+	}
+	if len(hull.Polygon.Coordinates) != 1 || !isCounterClockwise(hull.Polygon.Coordinates[0]) {
+		t.Fail()
+	}
+	// the two arms and base of the U, plus a half cell in each inner corner
+	if math.Abs(hull.Polygon.Area()-17) > 1e-12 {
+		fmt.Println("recieved    ", hull.Polygon.Area())
+		fmt.Println("but expected", 17)
+		t.Fail()
+	}
+	for _, pos := range g.MultiPoint.Coordinates {
+		if pointInPolygon(pos, hull.Polygon.Coordinates) < 0 {
+			fmt.Println(pos, "is outside the hull")
+			t.Fail()
+		}
+	}
+}
+
+func TestConcaveHullCollinear(t *testing.T) {
+	// the midpoints of the sides of a 3 by 3 grid are kept as vertices
+	var coords [][]float64
+	for x := 0.0; x <= 2; x++ {
+		for y := 0.0; y <= 2; y++ {
+			coords = append(coords, []float64{x, y})
+		}
+	}
+	for _, allowDisjoint := range []bool{false, true} {
+		hull, err := (&MultiPoint{Coordinates: coords}).ConcaveHull(1.5, allowDisjoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hull.Type != "Polygon" || len(hull.Polygon.Coordinates[0]) != 9 || hull.Polygon.Area() != 4 {
+			fmt.Println("recieved    ", hull.Type, hull.Polygon.Coordinates)
+			fmt.Println("but expected a ring of 8 vertices with area 4")
+			t.Fail()
+		}
+	}
+}
+
+func TestConcaveHullDisjoint(t *testing.T) {
+	var coords [][]float64
+	for _, x0 := range []float64{0, 10} {
+		for x := 0.0; x <= 2; x++ {
+			for y := 0.0; y <= 2; y++ {
+				coords = append(coords, []float64{x0 + x, y})
+			}
+		}
+	}
+	mp := &MultiPoint{Coordinates: coords}
+
+	hull, err := mp.ConcaveHull(1.5, false)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Polygon" {
+		fmt.Println("recieved    ", hull.Type)
+		t.Fail()
+	}
+
+	hull, err = mp.ConcaveHull(1.5, true)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "MultiPolygon" {
+		t.Fatal("expected a MultiPolygon, got", hull.Type)
+	}
+	if len(hull.MultiPolygon.Coordinates) != 2 || hull.MultiPolygon.Area() != 8 {
+		fmt.Println("recieved    ", hull.MultiPolygon.Coordinates)
+		t.Fail()
+	}
+
+	if _, err = mp.ConcaveHull(0.5, true); err == nil {
+		t.Fail()
+	}
+}
+
+func TestConcaveHullHole(t *testing.T) {
+	// a frame of points two cells wide around an empty square
+	var coords [][]float64
+	for x := 0.0; x <= 6; x++ {
+		for y := 0.0; y <= 6; y++ {
+			if x > 1 && x < 5 && y > 1 && y < 5 {
+				continue
+			}
+			coords = append(coords, []float64{x, y})
+		}
+	}
+	hull, err := (&MultiPoint{Coordinates: coords}).ConcaveHull(1.5, true)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if hull.Type != "Polygon" || len(hull.Polygon.Coordinates) != 2 {
+		t.Fatal("expected a Polygon with a hole")
+	}
+	// twenty whole cells, plus a half cell in each inner corner
+	if isCounterClockwise(hull.Polygon.Coordinates[1]) || hull.Polygon.Area() != 22 {
+		fmt.Println("recieved    ", hull.Polygon.Area())
+		t.Fail()
+	}
+}
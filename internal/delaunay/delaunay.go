@@ -0,0 +1,441 @@
+// Package delaunay computes Delaunay triangulations of planar point sets
+// using the sweep-hull algorithm of Delaunator (Agafonkin, 2017). Triangles
+// are described by half-edges: edge e of the triangulation runs from
+// Triangles[e] to Triangles[Next(e)], and Halfedges[e] is the opposite
+// half-edge in the adjacent triangle, or -1 on the convex hull.
+package delaunay
+
+import (
+	"math"
+	"sort"
+)
+
+const epsilon = 0x1p-52
+
+// Triangulation is a Delaunay triangulation. Triangles holds three point
+// indices per counter-clockwise triangle, and Hull holds the indices of the
+// convex hull in counter-clockwise order. If every point is collinear there
+// are no triangles and Hull holds the distinct points in order along the
+// line.
+type Triangulation struct {
+	Points    [][]float64
+	Triangles []int
+	Halfedges []int
+	Hull      []int
+}
+
+// Next returns the half-edge following e around its triangle
+func Next(e int) int {
+	if e%3 == 2 {
+		return e - 2
+	}
+	return e + 1
+}
+
+// Prev returns the half-edge preceding e around its triangle
+func Prev(e int) int {
+	if e%3 == 0 {
+		return e + 2
+	}
+	return e - 1
+}
+
+type builder struct {
+	points    [][]float64
+	triangles []int
+	halfedges []int
+
+	hullPrev, hullNext, hullTri, hullHash []int
+	hullStart                             int
+	hashSize                              int
+	cx, cy                                float64
+	edgeStack                             []int
+}
+
+// Triangulate returns the Delaunay triangulation of points. Points with
+// fewer than two coordinates are not allowed. Duplicate points are ignored.
+func Triangulate(points [][]float64) *Triangulation {
+	n := len(points)
+	t := &Triangulation{Points: points}
+	if n == 0 {
+		return t
+	}
+	b := &builder{points: points}
+	b.hashSize = int(math.Ceil(math.Sqrt(float64(n))))
+	b.hullPrev = make([]int, n)
+	b.hullNext = make([]int, n)
+	b.hullTri = make([]int, n)
+	b.hullHash = make([]int, b.hashSize)
+	maxTriangles := 2*n - 5
+	if maxTriangles < 0 {
+		maxTriangles = 0
+	}
+	b.triangles = make([]int, 0, maxTriangles*3)
+	b.halfedges = make([]int, 0, maxTriangles*3)
+
+	ids := make([]int, n)
+	dists := make([]float64, n)
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i, p := range points {
+		minX = math.Min(minX, p[0])
+		minY = math.Min(minY, p[1])
+		maxX = math.Max(maxX, p[0])
+		maxY = math.Max(maxY, p[1])
+		ids[i] = i
+	}
+	cx := (minX + maxX) / 2
+	cy := (minY + maxY) / 2
+
+	// seed triangle: the point nearest the centre, its nearest neighbour,
+	// and the point making the smallest circumcircle with them
+	i0, i1, i2 := -1, -1, -1
+	minDist := math.Inf(1)
+	for i, p := range points {
+		if d := dist(cx, cy, p[0], p[1]); d < minDist {
+			i0, minDist = i, d
+		}
+	}
+	minDist = math.Inf(1)
+	for i, p := range points {
+		if i == i0 {
+			continue
+		}
+		if d := dist(points[i0][0], points[i0][1], p[0], p[1]); d < minDist && d > 0 {
+			i1, minDist = i, d
+		}
+	}
+	minRadius := math.Inf(1)
+	if i1 >= 0 {
+		for i, p := range points {
+			if i == i0 || i == i1 {
+				continue
+			}
+			r := circumradius(points[i0][0], points[i0][1], points[i1][0], points[i1][1], p[0], p[1])
+			if r < minRadius {
+				i2, minRadius = i, r
+			}
+		}
+	}
+
+	if math.IsInf(minRadius, 1) || math.IsNaN(minRadius) {
+		// collinear points: order by distance along the line
+		for i, p := range points {
+			dists[i] = p[0] - points[0][0]
+			if dists[i] == 0 {
+				dists[i] = p[1] - points[0][1]
+			}
+		}
+		sort.SliceStable(ids, func(a, c int) bool { return dists[ids[a]] < dists[ids[c]] })
+		d0 := math.Inf(-1)
+		for _, id := range ids {
+			if dists[id] > d0 {
+				t.Hull = append(t.Hull, id)
+				d0 = dists[id]
+			}
+		}
+		return t
+	}
+
+	if orient(points[i0][0], points[i0][1], points[i1][0], points[i1][1], points[i2][0], points[i2][1]) {
+		i1, i2 = i2, i1
+	}
+	b.cx, b.cy = circumcenter(points[i0][0], points[i0][1], points[i1][0], points[i1][1],
+		points[i2][0], points[i2][1])
+	for i, p := range points {
+		dists[i] = dist(p[0], p[1], b.cx, b.cy)
+	}
+	sort.Slice(ids, func(a, c int) bool { return dists[ids[a]] < dists[ids[c]] })
+
+	b.hullStart = i0
+	hullSize := 3
+	b.hullNext[i0], b.hullPrev[i2] = i1, i1
+	b.hullNext[i1], b.hullPrev[i0] = i2, i2
+	b.hullNext[i2], b.hullPrev[i1] = i0, i0
+	b.hullTri[i0], b.hullTri[i1], b.hullTri[i2] = 0, 1, 2
+	for i := range b.hullHash {
+		b.hullHash[i] = -1
+	}
+	b.hullHash[b.hashKey(points[i0][0], points[i0][1])] = i0
+	b.hullHash[b.hashKey(points[i1][0], points[i1][1])] = i1
+	b.hullHash[b.hashKey(points[i2][0], points[i2][1])] = i2
+
+	b.addTriangle(i0, i1, i2, -1, -1, -1)
+
+	var xp, yp float64
+	for k, i := range ids {
+		x, y := points[i][0], points[i][1]
+
+		// skip near-duplicate points
+		if k > 0 && math.Abs(x-xp) <= epsilon && math.Abs(y-yp) <= epsilon {
+			continue
+		}
+		xp, yp = x, y
+
+		if i == i0 || i == i1 || i == i2 {
+			continue
+		}
+
+		// find a visible edge on the convex hull using the edge hash
+		start := 0
+		key := b.hashKey(x, y)
+		for j := 0; j < b.hashSize; j++ {
+			start = b.hullHash[(key+j)%b.hashSize]
+			if start != -1 && start != b.hullNext[start] {
+				break
+			}
+		}
+
+		start = b.hullPrev[start]
+		e := start
+		for {
+			q := b.hullNext[e]
+			if orient(x, y, points[e][0], points[e][1], points[q][0], points[q][1]) {
+				break
+			}
+			e = q
+			if e == start {
+				e = -1
+				break
+			}
+		}
+		if e == -1 {
+			// likely a near-duplicate point
+			continue
+		}
+
+		// add the first triangle from the point
+		tr := b.addTriangle(e, i, b.hullNext[e], -1, -1, b.hullTri[e])
+		b.hullTri[i] = b.legalize(tr + 2)
+		b.hullTri[e] = tr
+		hullSize++
+
+		// walk forward through the hull, adding more triangles
+		nn := b.hullNext[e]
+		for {
+			q := b.hullNext[nn]
+			if !orient(x, y, points[nn][0], points[nn][1], points[q][0], points[q][1]) {
+				break
+			}
+			tr = b.addTriangle(nn, i, q, b.hullTri[i], -1, b.hullTri[nn])
+			b.hullTri[i] = b.legalize(tr + 2)
+			b.hullNext[nn] = nn
+			hullSize--
+			nn = q
+		}
+
+		// walk backward from the other side
+		if e == start {
+			for {
+				q := b.hullPrev[e]
+				if !orient(x, y, points[q][0], points[q][1], points[e][0], points[e][1]) {
+					break
+				}
+				tr = b.addTriangle(q, i, e, -1, b.hullTri[e], b.hullTri[q])
+				b.legalize(tr + 2)
+				b.hullTri[q] = tr
+				b.hullNext[e] = e
+				hullSize--
+				e = q
+			}
+		}
+
+		b.hullStart = e
+		b.hullPrev[i] = e
+		b.hullNext[e] = i
+		b.hullPrev[nn] = i
+		b.hullNext[i] = nn
+
+		b.hullHash[b.hashKey(x, y)] = i
+		b.hullHash[b.hashKey(points[e][0], points[e][1])] = e
+	}
+
+	// the sweep builds clockwise triangles, so reverse them and the hull
+	t.Hull = make([]int, hullSize)
+	e := b.hullStart
+	for i := hullSize - 1; i >= 0; i-- {
+		t.Hull[i] = e
+		e = b.hullNext[e]
+	}
+	t.Triangles = make([]int, len(b.triangles))
+	t.Halfedges = make([]int, len(b.halfedges))
+	for e := range b.triangles {
+		t.Triangles[e] = b.triangles[e-e%3+(3-e%3)%3]
+		opp := b.halfedges[e-e%3+2-e%3]
+		if opp != -1 {
+			opp = opp - opp%3 + 2 - opp%3
+		}
+		t.Halfedges[e] = opp
+	}
+	return t
+}
+
+func (b *builder) hashKey(x, y float64) int {
+	return int(math.Floor(pseudoAngle(x-b.cx, y-b.cy)*float64(b.hashSize))) % b.hashSize
+}
+
+// legalize flips edges until the triangles around half-edge a satisfy the
+// Delaunay condition, returning the half-edge that ends up in a's place
+func (b *builder) legalize(a int) int {
+	var ar int
+	b.edgeStack = b.edgeStack[:0]
+	for {
+		bb := b.halfedges[a]
+		a0 := a - a%3
+		ar = a0 + (a+2)%3
+
+		if bb == -1 {
+			if len(b.edgeStack) == 0 {
+				break
+			}
+			a = b.edgeStack[len(b.edgeStack)-1]
+			b.edgeStack = b.edgeStack[:len(b.edgeStack)-1]
+			continue
+		}
+
+		b0 := bb - bb%3
+		al := a0 + (a+1)%3
+		bl := b0 + (bb+2)%3
+
+		p0 := b.triangles[ar]
+		pr := b.triangles[a]
+		pl := b.triangles[al]
+		p1 := b.triangles[bl]
+
+		pts := b.points
+		illegal := inCircle(pts[p0][0], pts[p0][1], pts[pr][0], pts[pr][1],
+			pts[pl][0], pts[pl][1], pts[p1][0], pts[p1][1])
+
+		if illegal {
+			b.triangles[a] = p1
+			b.triangles[bb] = p0
+
+			hbl := b.halfedges[bl]
+			if hbl == -1 {
+				// the edge was swapped on the other side of the hull
+				e := b.hullStart
+				for {
+					if b.hullTri[e] == bl {
+						b.hullTri[e] = a
+						break
+					}
+					e = b.hullPrev[e]
+					if e == b.hullStart {
+						break
+					}
+				}
+			}
+			b.link(a, hbl)
+			b.link(bb, b.halfedges[ar])
+			b.link(ar, bl)
+
+			br := b0 + (bb+1)%3
+			b.edgeStack = append(b.edgeStack, br)
+		} else {
+			if len(b.edgeStack) == 0 {
+				break
+			}
+			a = b.edgeStack[len(b.edgeStack)-1]
+			b.edgeStack = b.edgeStack[:len(b.edgeStack)-1]
+		}
+	}
+	return ar
+}
+
+func (b *builder) link(a, c int) {
+	b.halfedges[a] = c
+	if c != -1 {
+		b.halfedges[c] = a
+	}
+}
+
+func (b *builder) addTriangle(i0, i1, i2, a, bb, c int) int {
+	t := len(b.triangles)
+	b.triangles = append(b.triangles, i0, i1, i2)
+	b.halfedges = append(b.halfedges, -1, -1, -1)
+	b.link(t, a)
+	b.link(t+1, bb)
+	b.link(t+2, c)
+	return t
+}
+
+// pseudoAngle increases monotonically with the angle of (dx, dy), in [0, 1]
+func pseudoAngle(dx, dy float64) float64 {
+	p := dx / (math.Abs(dx) + math.Abs(dy))
+	if dy > 0 {
+		return (3 - p) / 4
+	}
+	return (1 + p) / 4
+}
+
+func dist(ax, ay, bx, by float64) float64 {
+	dx := ax - bx
+	dy := ay - by
+	return dx*dx + dy*dy
+}
+
+// orientIfSure returns the orientation determinant of p, r, q when its sign
+// can be trusted, and zero otherwise
+func orientIfSure(px, py, rx, ry, qx, qy float64) float64 {
+	l := (ry - py) * (qx - px)
+	r := (rx - px) * (qy - py)
+	if math.Abs(l-r) >= 3.3306690738754716e-16*math.Abs(l+r) {
+		return l - r
+	}
+	return 0
+}
+
+// orient returns true if p, q and r turn counter-clockwise, trying each
+// rotation of the points so that the result is stable for a given triangle
+func orient(px, py, qx, qy, rx, ry float64) bool {
+	sign := orientIfSure(rx, ry, px, py, qx, qy)
+	if sign == 0 {
+		sign = orientIfSure(px, py, qx, qy, rx, ry)
+	}
+	if sign == 0 {
+		sign = orientIfSure(qx, qy, rx, ry, px, py)
+	}
+	return sign < 0
+}
+
+func inCircle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	dx := ax - px
+	dy := ay - py
+	ex := bx - px
+	ey := by - py
+	fx := cx - px
+	fy := cy - py
+	ap := dx*dx + dy*dy
+	bp := ex*ex + ey*ey
+	cp := fx*fx + fy*fy
+	return dx*(ey*cp-bp*fy)-dy*(ex*cp-bp*fx)+ap*(ex*fy-ey*fx) < 0
+}
+
+func circumradius(ax, ay, bx, by, cx, cy float64) float64 {
+	dx := bx - ax
+	dy := by - ay
+	ex := cx - ax
+	ey := cy - ay
+	bl := dx*dx + dy*dy
+	cl := ex*ex + ey*ey
+	d := 0.5 / (dx*ey - dy*ex)
+	x := (ey*bl - dy*cl) * d
+	y := (dx*cl - ex*bl) * d
+	return x*x + y*y
+}
+
+// Circumcenter returns the centre of the circle through three points
+func Circumcenter(a, b, c []float64) (float64, float64) {
+	return circumcenter(a[0], a[1], b[0], b[1], c[0], c[1])
+}
+
+func circumcenter(ax, ay, bx, by, cx, cy float64) (float64, float64) {
+	dx := bx - ax
+	dy := by - ay
+	ex := cx - ax
+	ey := cy - ay
+	bl := dx*dx + dy*dy
+	cl := ex*ex + ey*ey
+	d := 0.5 / (dx*ey - dy*ex)
+	return ax + (ey*bl-dy*cl)*d, ay + (dx*cl-ex*bl)*d
+}
@@ -0,0 +1,84 @@
+package delaunay
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func signedArea(a, b, c []float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (c[0]-a[0])*(b[1]-a[1])
+}
+
+func TestTriangulateSquare(t *testing.T) {
+	tri := Triangulate([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0.5, 0.5}})
+	if len(tri.Triangles) != 12 {
+		t.Fatal("expected four triangles, got", len(tri.Triangles)/3)
+	}
+	if len(tri.Hull) != 4 {
+		t.Fail()
+	}
+}
+
+func TestTriangulateRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([][]float64, 500)
+	for i := range points {
+		points[i] = []float64{rng.Float64() * 100, rng.Float64() * 100}
+	}
+	tri := Triangulate(points)
+
+	// Euler: a triangulation of n points with h on the hull has 2n - h - 2
+	// triangles
+	if len(tri.Triangles)/3 != 2*len(points)-len(tri.Hull)-2 {
+		t.Error("unexpected number of triangles", len(tri.Triangles)/3)
+	}
+	for e, opp := range tri.Halfedges {
+		if opp != -1 && tri.Halfedges[opp] != e {
+			t.Fatal("asymmetric half-edges at", e)
+		}
+	}
+	for i := 0; i < len(tri.Triangles); i += 3 {
+		a := points[tri.Triangles[i]]
+		b := points[tri.Triangles[i+1]]
+		c := points[tri.Triangles[i+2]]
+		if signedArea(a, b, c) <= 0 {
+			t.Fatal("triangle", i/3, "is not counter-clockwise")
+		}
+		// no point lies inside a circumcircle
+		cx, cy := Circumcenter(a, b, c)
+		r := math.Hypot(a[0]-cx, a[1]-cy)
+		for j, p := range points {
+			if j == tri.Triangles[i] || j == tri.Triangles[i+1] || j == tri.Triangles[i+2] {
+				continue
+			}
+			if math.Hypot(p[0]-cx, p[1]-cy) < r*(1-1e-9) {
+				t.Fatal("point", j, "inside circumcircle of triangle", i/3)
+			}
+		}
+	}
+	for i := range tri.Hull {
+		a := points[tri.Hull[i]]
+		b := points[tri.Hull[(i+1)%len(tri.Hull)]]
+		c := points[tri.Hull[(i+2)%len(tri.Hull)]]
+		if signedArea(a, b, c) < 0 {
+			t.Fatal("hull is not convex and counter-clockwise")
+		}
+	}
+}
+
+func TestTriangulateCollinear(t *testing.T) {
+	tri := Triangulate([][]float64{{2, 2}, {0, 0}, {1, 1}, {1, 1}, {3, 3}})
+	if len(tri.Triangles) != 0 {
+		t.Fail()
+	}
+	expected := []int{1, 2, 0, 4}
+	if len(tri.Hull) != len(expected) {
+		t.Fatal("unexpected hull", tri.Hull)
+	}
+	for i, v := range expected {
+		if tri.Hull[i] != v {
+			t.Fatal("unexpected hull", tri.Hull)
+		}
+	}
+}
@@ -0,0 +1,667 @@
+// Package geod solves geodesic problems on an oblate ellipsoid of revolution.
+// It is a port of the algorithms in Karney (2013) "Algorithms for geodesics",
+// J. Geodesy 87, 43-55, as implemented in GeographicLib. Angles are in
+// degrees with latitude first, and lengths are in the units of the ellipsoid
+// axis.
+package geod
+
+import "math"
+
+// Geodesic describes an ellipsoid of revolution
+type Geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	a3x [nA3x]float64
+	c3x [nC3x]float64
+	c4x [nC4x]float64
+}
+
+// WGS84 is the ellipsoid used by GPS and assumed by RFC 7946
+var WGS84 = New(6378137, 1/298.257223563)
+
+// New returns a Geodesic for an ellipsoid with equatorial radius a and
+// flattening f, which must be non-negative
+func New(a, f float64) *Geodesic {
+	g := &Geodesic{a: a, f: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / sq(g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+	if g.e2 == 0 {
+		g.c2 = sq(a)
+	} else {
+		g.c2 = (sq(a) + sq(g.b)*math.Atanh(math.Sqrt(g.e2))/math.Sqrt(g.e2)) / 2
+	}
+	g.etol2 = 0.1 * tol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+// EquatorialRadius returns the semi-major axis of the ellipsoid
+func (g *Geodesic) EquatorialRadius() float64 {
+	return g.a
+}
+
+// Flattening returns the flattening of the ellipsoid
+func (g *Geodesic) Flattening() float64 {
+	return g.f
+}
+
+// TotalArea returns the surface area of the ellipsoid
+func (g *Geodesic) TotalArea() float64 {
+	return 4 * math.Pi * g.c2
+}
+
+// lengths computes the distance s12b/b and reduced length m12b/b along a
+// geodesic, along with the geodesic scales M12 and M21
+func (g *Geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+	cbet1, cbet2 float64, wantS, wantM, wantScale bool, ca []float64) (s12b, m12b, bigM12, bigM21 float64) {
+
+	var m0, j12, a1, a2 float64
+	var cb [nC]float64
+	redlp := wantM || wantScale
+	if wantS || redlp {
+		a1 = a1m1f(eps)
+		c1f(eps, ca)
+		if redlp {
+			a2 = a2m1f(eps)
+			c2f(eps, cb[:])
+			m0 = a1 - a2
+			a2 = 1 + a2
+		}
+		a1 = 1 + a1
+	}
+	if wantS {
+		b1 := sinCosSeries(true, ssig2, csig2, ca, nC1) -
+			sinCosSeries(true, ssig1, csig1, ca, nC1)
+		s12b = a1 * (sig12 + b1)
+		if redlp {
+			b2 := sinCosSeries(true, ssig2, csig2, cb[:], nC2) -
+				sinCosSeries(true, ssig1, csig1, cb[:], nC2)
+			j12 = m0*sig12 + (a1*b1 - a2*b2)
+		}
+	} else if redlp {
+		for l := 1; l <= nC2; l++ {
+			cb[l] = a1*ca[l] - a2*cb[l]
+		}
+		j12 = m0*sig12 + (sinCosSeries(true, ssig2, csig2, cb[:], nC2) -
+			sinCosSeries(true, ssig1, csig1, cb[:], nC2))
+	}
+	if wantM {
+		m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	}
+	if wantScale {
+		csig12 := csig1*csig2 + ssig1*ssig2
+		t := g.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+		bigM12 = csig12 + (t*ssig2-csig2*j12)*ssig1/dn1
+		bigM21 = csig12 - (t*ssig1-csig1*j12)*ssig2/dn2
+	}
+	return
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for the positive
+// root k
+func astroid(x, y float64) float64 {
+	p := sq(x)
+	q := sq(y)
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	s := p * q / 4
+	r2 := sq(r)
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(sq(u) + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+sq(w)) + w)
+}
+
+// inverseStart returns a starting guess for Newton's method in the inverse
+// problem. If the line is short enough that no iteration is needed, sig12 is
+// non-negative and the final azimuth is also returned.
+func (g *Geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	lam12, slam12, clam12 float64, ca []float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := sq(sbet1 + sbet2)
+		sbetm2 /= sbetm2 + sq(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*sq(somg12)/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*sq(somg12)/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) > 0.1 || csig12 >= 0 ||
+		ssig12 >= 6*math.Abs(g.n)*math.Pi*sq(cbet1) {
+		// zeroth order spherical approximation is adequate
+	} else {
+		// scale to coordinates where the antipodal point is at the origin
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sq(sbet1) * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+
+		if y > -tol1 && x > -1-xthresh {
+			// strip near cut
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - sq(salp1))
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sin(omg12a), -math.Cos(omg12a)
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+		}
+	}
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return
+}
+
+// lambda12 returns the longitude difference reached by a geodesic leaving
+// bet1 with azimuth alp1 and arriving at bet2, less the target difference
+func (g *Geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2,
+	salp1, calp1, slam120, clam120 float64, diffp bool, ca []float64) (
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+
+	if sbet1 == 0 && calp1 == 0 {
+		// break degeneracy of equatorial line
+		calp1 = -tiny
+	}
+
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(sq(calp1*cbet1)+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2) + 0
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := sq(calp0) * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, ca)
+	b312 := sinCosSeries(true, ssig2, csig2, ca, nC3-1) -
+		sinCosSeries(true, ssig1, csig1, ca, nC3-1)
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+				cbet1, cbet2, false, true, false, ca)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return
+}
+
+// inverse solves the inverse problem, returning the distance, the sines and
+// cosines of the azimuths at each end, and the area S12 between the geodesic
+// and the equator
+func (g *Geodesic) inverse(lat1, lon1, lat2, lon2 float64, wantArea bool) (
+	s12, salp1, calp1, salp2, calp2, area float64) {
+
+	var ca [nC]float64
+	var m12x, s12x, sig12, omg12 float64
+	somg12, comg12 := 2.0, 0.0
+
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if math.Signbit(lon12) {
+		lonsign = -1
+	}
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * degree
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.0
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(tiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sq(sbet1))
+	dn2 := math.Sqrt(1 + g.ep2*sq(sbet2))
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// endpoints are on a single full meridian
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2)+0, csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, _ = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+			cbet1, cbet2, true, true, false, ca[:])
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*tiny || (sig12 < tol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+		} else {
+			// m12 < 0, i.e., prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// geodesic runs along the equator
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+	} else if !meridian {
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1,
+			sbet2, cbet2, dn2, lam12, slam12, clam12, ca[:])
+
+		if sig12 >= 0 {
+			// short lines
+			s12x = sig12 * g.b * dnm
+			m12x = sq(dnm) * g.b * math.Sin(sig12/dnm)
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// Newton's method, falling back to bisection
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+			salp1a, calp1a := tiny, 1.0
+			salp1b, calp1b := tiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				var v, dv float64
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv =
+					g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1,
+						slam12, clam12, numit < maxit1, ca[:])
+				tol := tol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb || !(math.Abs(v) >= tol) || numit == maxit2 {
+					break
+				}
+				if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < maxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sin(dalp1), math.Cos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							tripn = math.Abs(v) <= 16*tol0
+							continue
+						}
+					}
+				}
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+			}
+			s12x, m12x, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2,
+				cbet1, cbet2, true, true, false, ca[:])
+			m12x *= g.b
+			s12x *= g.b
+			if wantArea {
+				sdomg12, cdomg12 := math.Sin(domg12), math.Cos(domg12)
+				somg12 = slam12*cdomg12 - clam12*sdomg12
+				comg12 = clam12*cdomg12 + slam12*sdomg12
+			}
+		}
+	}
+
+	s12 = 0 + s12x
+
+	if wantArea {
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1)
+		if calp0 != 0 && salp0 != 0 {
+			ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+			ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+			k2 := sq(calp0) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			a4 := sq(g.a) * calp0 * salp0 * g.e2
+			g.c4f(eps, ca[:])
+			b41 := sinCosSeries(false, ssig1, csig1, ca[:], nC4)
+			b42 := sinCosSeries(false, ssig2, csig2, ca[:], nC4)
+			area = a4 * (b42 - b41)
+		}
+
+		if !meridian && somg12 > 1 {
+			somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+		}
+
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			domg12 := 1 + comg12
+			dbet1 := 1 + cbet1
+			dbet2 := 1 + cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1),
+				domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = tiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		area += g.c2 * alp12
+		area *= swapp * lonsign * latsign
+		area += 0
+	}
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+	return
+}
+
+// Inverse returns the length of the shortest geodesic between two points and
+// the azimuths of the geodesic at each end, measured clockwise from north
+func (g *Geodesic) Inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	var salp1, calp1, salp2, calp2 float64
+	s12, salp1, calp1, salp2, calp2, _ = g.inverse(lat1, lon1, lat2, lon2, false)
+	return s12, atan2d(salp1, calp1), atan2d(salp2, calp2)
+}
+
+// transit returns 1 or -1 if an edge from lon1 to lon2 crosses the prime
+// meridian heading east or west, and 0 otherwise
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	if lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)) {
+		return 1
+	}
+	if lon12 < 0 && lon1 >= 0 && lon2 < 0 {
+		return -1
+	}
+	return 0
+}
+
+// PolygonArea returns the area and perimeter of the polygon with vertices at
+// lats, lons, with edges that are geodesics. The polygon is closed
+// automatically and must not repeat its first vertex. The area is positive
+// when the vertices are traversed counter-clockwise.
+func (g *Geodesic) PolygonArea(lats, lons []float64) (area, perimeter float64) {
+	n := len(lats)
+	if n < 2 {
+		return 0, 0
+	}
+	crossings := 0
+	for i := 0; i != n; i++ {
+		j := (i + 1) % n
+		s12, _, _, _, _, s := g.inverse(lats[i], lons[i], lats[j], lons[j], true)
+		perimeter += s12
+		area += s
+		crossings += transit(lons[i], lons[j])
+	}
+	if n < 3 {
+		return 0, perimeter
+	}
+	area0 := g.TotalArea()
+	area = math.Remainder(area, area0)
+	if crossings&1 != 0 {
+		if area < 0 {
+			area += area0 / 2
+		} else {
+			area -= area0 / 2
+		}
+	}
+	// accumulated area is positive for clockwise traversal
+	area = -area
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+	return area + 0, perimeter
+}
+
+// Direct returns the position and forward azimuth reached by travelling a
+// distance s12 along the geodesic leaving lat1, lon1 at azimuth azi1
+func (g *Geodesic) Direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	var c1a, c1pa, c3a [nC]float64
+
+	azi1 = angNormalize(azi1)
+	salp1, calp1 := sincosd(angRound(azi1))
+	sbet1, cbet1 := sincosd(angRound(latFix(lat1)))
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	// the geodesic crosses the equator at azimuth alp0
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+	ssig1, somg1 := sbet1, salp0*sbet1
+	csig1 := 1.0
+	if sbet1 != 0 || calp1 != 0 {
+		csig1 = cbet1 * calp1
+	}
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	k2 := sq(calp0) * g.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	a1m1 := a1m1f(eps)
+	c1f(eps, c1a[:])
+	b11 := sinCosSeries(true, ssig1, csig1, c1a[:], nC1)
+	s, c := math.Sin(b11), math.Cos(b11)
+	stau1 := ssig1*c + csig1*s
+	ctau1 := csig1*c - ssig1*s
+	c1pf(eps, c1pa[:])
+	a3c := -g.f * salp0 * g.a3f(eps)
+	g.c3f(eps, c3a[:])
+	b31 := sinCosSeries(true, ssig1, csig1, c3a[:], nC3-1)
+
+	tau12 := s12 / (g.b * (1 + a1m1))
+	s, c = math.Sin(tau12), math.Cos(tau12)
+	b12 := -sinCosSeries(true, stau1*c+ctau1*s, ctau1*c-stau1*s, c1pa[:], nC1p)
+	sig12 := tau12 - (b12 - b11)
+	ssig12, csig12 := math.Sin(sig12), math.Cos(sig12)
+	if math.Abs(g.f) > 0.01 {
+		// the reverted series is only accurate for small flattening, so take
+		// one Newton step
+		ssig2 := ssig1*csig12 + csig1*ssig12
+		csig2 := csig1*csig12 - ssig1*ssig12
+		b12 = sinCosSeries(true, ssig2, csig2, c1a[:], nC1)
+		serr := (1+a1m1)*(sig12+(b12-b11)) - s12/g.b
+		sig12 -= serr / math.Sqrt(1+k2*sq(ssig2))
+		ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+	}
+
+	ssig2 := ssig1*csig12 + csig1*ssig12
+	csig2 := csig1*csig12 - ssig1*ssig12
+	sbet2 := calp0 * ssig2
+	cbet2 := math.Hypot(salp0, calp0*csig2)
+	if cbet2 == 0 {
+		cbet2, csig2 = tiny, tiny
+	}
+	salp2, calp2 := salp0, calp0*csig2
+
+	somg2, comg2 := salp0*ssig2, csig2
+	omg12 := math.Atan2(somg2*comg1-comg2*somg1, comg2*comg1+somg2*somg1)
+	lam12 := omg12 + a3c*(sig12+(sinCosSeries(true, ssig2, csig2, c3a[:], nC3-1)-b31))
+	lon2 = angNormalize(angNormalize(lon1) + angNormalize(lam12/degree))
+	lat2 = atan2d(sbet2, g.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+	return
+}
+
+// Nearest returns the distance along the geodesic from lat1, lon1 to lat2,
+// lon2 of the point closest to lat3, lon3, and the distance from that point
+// to lat3, lon3. The foot of the perpendicular geodesic is found by
+// iterating from the start, treating each correction as on a sphere of the
+// equatorial radius, and the ends are taken if they are closer.
+func (g *Geodesic) Nearest(lat1, lon1, lat2, lon2, lat3, lon3 float64) (s, d float64) {
+	s12, azi1, _ := g.Inverse(lat1, lon1, lat2, lon2)
+	d, _, _ = g.Inverse(lat1, lon1, lat3, lon3)
+	if d2, _, _ := g.Inverse(lat2, lon2, lat3, lon3); d2 < d {
+		s, d = s12, d2
+	}
+	if s12 == 0 {
+		return s, d
+	}
+
+	x := 0.0
+	for iter := 0; iter != 30; iter++ {
+		lat, lon, azi := g.Direct(lat1, lon1, azi1, x)
+		sq3, aziq3, _ := g.Inverse(lat, lon, lat3, lon3)
+		sig := sq3 / g.a
+		dx := g.a * math.Atan2(math.Sin(sig)*math.Cos((aziq3-azi)*degree), math.Cos(sig))
+		next := math.Max(0, math.Min(s12, x+dx))
+		converged := math.Abs(next-x) < 1e-9*math.Max(1, s12)
+		x = next
+		if converged {
+			break
+		}
+	}
+	lat, lon, _ := g.Direct(lat1, lon1, azi1, x)
+	if dx, _, _ := g.Inverse(lat, lon, lat3, lon3); dx < d {
+		s, d = x, dx
+	}
+	return s, d
+}
@@ -0,0 +1,158 @@
+package geod
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestInverse(t *testing.T) {
+	// reference values from Vincenty's formulae, which agree with Karney's
+	// to well under a millimetre, though Vincenty loses a little accuracy
+	// in azimuth for nearly antipodal points
+	cases := [][7]float64{
+		{40.6, -73.8, 51.6, -0.5, 5551759.400334, 51.1988828455, 107.8217767357},
+		{-30, 10, 45, 120, 13809989.376932, 53.6247074543, 80.1556894960},
+		{0, 0, 0.5, 179, 19902751.032656, 48.0024583502, 131.9951345490},
+	}
+	for _, c := range cases {
+		s12, azi1, azi2 := WGS84.Inverse(c[0], c[1], c[2], c[3])
+		if math.Abs(s12-c[4]) > 1e-3 || math.Abs(azi1-c[5]) > 1e-7 || math.Abs(azi2-c[6]) > 1e-7 {
+			fmt.Println("recieved    ", s12, azi1, azi2)
+			fmt.Println("but expected", c[4], c[5], c[6])
+			t.Fail()
+		}
+	}
+}
+
+func TestDirect(t *testing.T) {
+	// the inverse cases solved forwards from their starting azimuths
+	cases := [][7]float64{
+		{40.6, -73.8, 51.6, -0.5, 5551759.400334, 51.1988828455, 107.8217767357},
+		{-30, 10, 45, 120, 13809989.376932, 53.6247074543, 80.1556894960},
+		{0, 0, 0.5, 179, 19902751.032656, 48.0024583502, 131.9951345490},
+	}
+	for _, c := range cases {
+		lat2, lon2, azi2 := WGS84.Direct(c[0], c[1], c[5], c[4])
+		if math.Abs(lat2-c[2]) > 1e-7 || math.Abs(lon2-c[3]) > 1e-7 || math.Abs(azi2-c[6]) > 1e-6 {
+			fmt.Println("recieved    ", lat2, lon2, azi2)
+			fmt.Println("but expected", c[2], c[3], c[6])
+			t.Fail()
+		}
+	}
+
+	// round trip across the antimeridian
+	lat2, lon2, _ := WGS84.Direct(10, 179.5, 80, 250e3)
+	s12, azi1, _ := WGS84.Inverse(10, 179.5, lat2, lon2)
+	if lon2 > 0 || math.Abs(s12-250e3) > 1e-6 || math.Abs(azi1-80) > 1e-9 {
+		fmt.Println("recieved    ", lat2, lon2, s12, azi1)
+		t.Fail()
+	}
+}
+
+func TestInverseSpecialCases(t *testing.T) {
+	// quarter meridian
+	s12, azi1, _ := WGS84.Inverse(0, 0, 90, 0)
+	if math.Abs(s12-10001965.729313) > 1e-5 || azi1 != 0 {
+		fmt.Println("recieved", s12, azi1)
+		t.Fail()
+	}
+	// along the equator
+	s12, azi1, azi2 := WGS84.Inverse(0, 0, 0, 1)
+	if math.Abs(s12-6378137*math.Pi/180) > 1e-8 || azi1 != 90 || azi2 != 90 {
+		fmt.Println("recieved", s12, azi1, azi2)
+		t.Fail()
+	}
+	// coincident points
+	s12, _, _ = WGS84.Inverse(12, 34, 12, 34)
+	if s12 != 0 {
+		t.Fail()
+	}
+	// antipodal points
+	s12, _, _ = WGS84.Inverse(0, 0, 0, 180)
+	if math.Abs(s12-20003931.458625) > 1e-5 {
+		fmt.Println("recieved", s12)
+		t.Fail()
+	}
+}
+
+// authalicBand returns the area of the ellipsoid between two parallels and
+// spanning dlon degrees of longitude
+func authalicBand(lat1, lat2, dlon float64) float64 {
+	a := 6378137.0
+	f := 1 / 298.257223563
+	e := math.Sqrt(f * (2 - f))
+	b := a * (1 - f)
+	q := func(lat float64) float64 {
+		s := math.Sin(lat * degree)
+		return s/(1-e*e*s*s) + math.Log((1+e*s)/(1-e*s))/(2*e)
+	}
+	return b * b * dlon * degree / 2 * (q(lat2) - q(lat1))
+}
+
+func TestPolygonArea(t *testing.T) {
+	// reference value from GeographicLib's Planimeter
+	area, perimeter := WGS84.PolygonArea([]float64{0, 0, 1, 1}, []float64{0, 1, 1, 0})
+	ref := 12308778361.469
+	if math.Abs(area-ref) > 1e-2 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", ref)
+		t.Fail()
+	}
+	// the northern edge bulges poleward of the parallel, adding area
+	if area < authalicBand(0, 1, 1) {
+		t.Fail()
+	}
+	if math.Abs(perimeter-443770.9) > 10 {
+		fmt.Println("recieved perimeter", perimeter)
+		t.Fail()
+	}
+
+	// clockwise traversal gives a negative area
+	cw, _ := WGS84.PolygonArea([]float64{0, 1, 1, 0}, []float64{0, 0, 1, 1})
+	if math.Abs(cw+area) > 1e-3 {
+		t.Fail()
+	}
+
+	// a polygon encircling the north pole
+	area, _ = WGS84.PolygonArea([]float64{80, 80, 80, 80}, []float64{0, 90, 180, -90})
+	if area <= 0 || area > authalicBand(80, 90, 360) {
+		fmt.Println("recieved", area, authalicBand(80, 90, 360))
+		t.Fail()
+	}
+
+	if math.Abs(WGS84.TotalArea()-5.10065621724e14) > 1e3 {
+		t.Fail()
+	}
+}
+
+func TestNearest(t *testing.T) {
+	// by symmetry the point closest to a position north of the equator is
+	// directly south of it
+	s, d := WGS84.Nearest(0, 0, 0, 10, 1, 3)
+	s03, _, _ := WGS84.Inverse(0, 0, 0, 3)
+	d3, _, _ := WGS84.Inverse(0, 3, 1, 3)
+	if math.Abs(s-s03) > 1e-6 || math.Abs(d-d3) > 1e-6 {
+		fmt.Println("recieved    ", s, d)
+		fmt.Println("but expected", s03, d3)
+		t.Fail()
+	}
+
+	// the perpendicular from a position off a long oblique geodesic
+	s12, azi1, _ := WGS84.Inverse(-30, 10, 45, 120)
+	lat, lon, azi := WGS84.Direct(-30, 10, azi1, 0.4*s12)
+	lat3, lon3, _ := WGS84.Direct(lat, lon, azi+90, 500e3)
+	s, d = WGS84.Nearest(-30, 10, 45, 120, lat3, lon3)
+	if math.Abs(s-0.4*s12) > 1e-6 || math.Abs(d-500e3) > 1e-6 {
+		fmt.Println("recieved    ", s, d)
+		fmt.Println("but expected", 0.4*s12, 500e3)
+		t.Fail()
+	}
+
+	// beyond the end
+	s, _ = WGS84.Nearest(0, 0, 0, 10, 1, 12)
+	if s12, _, _ = WGS84.Inverse(0, 0, 0, 10); s != s12 {
+		fmt.Println("recieved    ", s)
+		t.Fail()
+	}
+}
@@ -0,0 +1,175 @@
+package geod
+
+import "math"
+
+const degree = math.Pi / 180
+
+var (
+	tiny    = math.Sqrt(0x1p-1022)
+	tol0    = math.Nextafter(1, 2) - 1
+	tol1    = 200 * tol0
+	tol2    = math.Sqrt(tol0)
+	tolb    = tol0
+	xthresh = 1000 * tol2
+)
+
+const (
+	maxit1 = 20
+	maxit2 = maxit1 + 53 + 10
+)
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+// polyval evaluates the polynomial of order n with coefficients p (highest
+// order first) at x
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// sumx returns the error-free sum of u and v, together with the rounding
+// error t
+func sumx(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	return s, -(up + vpp)
+}
+
+// angNormalize reduces an angle to (-180, 180]
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+// angDiff returns y - x reduced to [-180, 180], together with the error in
+// the difference
+func angDiff(x, y float64) (float64, float64) {
+	d, t := sumx(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	return sumx(d, t)
+}
+
+// angRound coarsens tiny angles so that they are exactly representable
+// relative to 1/16
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	if x == 0 {
+		return 0
+	}
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	if x < 0 {
+		return -y
+	}
+	return y
+}
+
+// latFix returns NaN for latitudes outside [-90, 90]
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// sincosd returns the sine and cosine of an angle in degrees, exactly for
+// multiples of 90
+func sincosd(x float64) (float64, float64) {
+	r := math.Remainder(x, 90)
+	q := int(math.Round((x - r) / 90))
+	r *= degree
+	s, c := math.Sin(r), math.Cos(r)
+	var sinx, cosx float64
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	if x != 0 {
+		sinx += 0
+		cosx += 0
+	}
+	return sinx, cosx
+}
+
+// atan2d returns atan2(y, x) in degrees, exactly for multiples of 45
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if x < 0 {
+		x = -x
+		q++
+	}
+	ang := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
+
+// sinCosSeries evaluates sum(c[i] * sin(2*i*x), i, 1, n) when sinp is true,
+// and sum(c[i] * cos((2*i+1)*x), i, 0, n-1) otherwise, using Clenshaw
+// summation
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	k := n
+	if sinp {
+		k++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
@@ -0,0 +1,440 @@
+/* linear referencing: positions along lines by distance from their start */
+package geojson
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// lineMeasure measures distance along lines, either in the plane in
+// coordinate units or along geodesics on the WGS84 ellipsoid in metres
+type lineMeasure struct {
+	geodesic bool
+}
+
+// length returns the length of the segment from a to b
+func (m lineMeasure) length(a, b []float64) float64 {
+	if m.geodesic {
+		s12, _, _ := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+		return s12
+	}
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// along returns the position a distance s from a on the segment to b, which
+// has length s12. Coordinates beyond the second are interpolated linearly.
+func (m lineMeasure) along(a, b []float64, s, s12 float64) []float64 {
+	if s <= 0 || s12 == 0 {
+		return append([]float64{}, a...)
+	}
+	if s >= s12 {
+		return append([]float64{}, b...)
+	}
+	pos := interpolate(a, b, s/s12)
+	if m.geodesic {
+		_, azi1, _ := geod.WGS84.Inverse(a[1], a[0], b[1], b[0])
+		pos[1], pos[0], _ = geod.WGS84.Direct(a[1], a[0], azi1, s)
+	}
+	return pos
+}
+
+// nearest returns the distance along the segment from a to b of the point
+// closest to pos, and the distance from that point to pos
+func (m lineMeasure) nearest(pos, a, b []float64) (float64, float64) {
+	if m.geodesic {
+		return geod.WGS84.Nearest(a[1], a[0], b[1], b[0], pos[1], pos[0])
+	}
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	d2 := dx*dx + dy*dy
+	t := 0.0
+	if d2 != 0 {
+		t = math.Max(0, math.Min(1, ((pos[0]-a[0])*dx+(pos[1]-a[1])*dy)/d2))
+	}
+	return t * math.Sqrt(d2), math.Hypot(a[0]+t*dx-pos[0], a[1]+t*dy-pos[1])
+}
+
+// measuredLine is a line with the distance to each of its positions
+type measuredLine struct {
+	positions [][]float64
+	cum       []float64
+}
+
+// measureLines returns the parts of a line with distances accumulated
+// across every part, ignoring the gaps between parts
+func (m lineMeasure) measureLines(parts [][][]float64) ([]measuredLine, float64, error) {
+	lines := make([]measuredLine, 0, len(parts))
+	var total float64
+	for _, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		cum := make([]float64, len(part))
+		cum[0] = total
+		for i := 1; i < len(part); i++ {
+			total += m.length(part[i-1], part[i])
+			cum[i] = total
+		}
+		lines = append(lines, measuredLine{part, cum})
+	}
+	if len(lines) == 0 {
+		return nil, 0, errors.New("linear referencing requires a non-empty line")
+	}
+	return lines, total, nil
+}
+
+// positionAt returns the position at distance s along a part, which must be
+// within the part
+func (m lineMeasure) positionAt(line measuredLine, s float64) []float64 {
+	j := sort.SearchFloat64s(line.cum, s)
+	if j == len(line.cum) {
+		j--
+	}
+	if j == 0 || line.cum[j] == s {
+		return append([]float64{}, line.positions[j]...)
+	}
+	return m.along(line.positions[j-1], line.positions[j], s-line.cum[j-1], line.cum[j]-line.cum[j-1])
+}
+
+// clampMeasure counts negative distances back from the end and limits
+// distances to the length of the line
+func clampMeasure(s, total float64) (float64, error) {
+	if math.IsNaN(s) {
+		return 0, errors.New("distance along line is NaN")
+	}
+	if s < 0 {
+		s += total
+	}
+	return math.Max(0, math.Min(total, s)), nil
+}
+
+func (m lineMeasure) interpolate(parts [][][]float64, s float64) ([]float64, error) {
+	lines, total, err := m.measureLines(parts)
+	if err != nil {
+		return nil, err
+	}
+	if s, err = clampMeasure(s, total); err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if s <= line.cum[len(line.cum)-1] {
+			return m.positionAt(line, s), nil
+		}
+	}
+	last := lines[len(lines)-1]
+	return append([]float64{}, last.positions[len(last.positions)-1]...), nil
+}
+
+func (m lineMeasure) interpolateFraction(parts [][][]float64, fraction float64) ([]float64, error) {
+	_, total, err := m.measureLines(parts)
+	if err != nil {
+		return nil, err
+	}
+	if fraction < 0 || fraction > 1 || math.IsNaN(fraction) {
+		return nil, errors.New("fraction must be between 0 and 1")
+	}
+	return m.interpolate(parts, fraction*total)
+}
+
+func (m lineMeasure) locate(parts [][][]float64, pos []float64) (float64, error) {
+	lines, _, err := m.measureLines(parts)
+	if err != nil {
+		return 0, err
+	}
+	if len(pos) < 2 {
+		return 0, errors.New("cannot locate a position with fewer than two coordinates")
+	}
+	best, dbest := 0.0, math.Inf(1)
+	for _, line := range lines {
+		if len(line.positions) == 1 {
+			if d := m.length(line.positions[0], pos); d < dbest {
+				best, dbest = line.cum[0], d
+			}
+		}
+		for i := 1; i < len(line.positions); i++ {
+			s, d := m.nearest(pos, line.positions[i-1], line.positions[i])
+			if d < dbest {
+				best, dbest = line.cum[i-1]+s, d
+			}
+		}
+	}
+	return best, nil
+}
+
+// substring returns the pieces of the line between two distances along it,
+// in the direction of the line. Parts that the range only touches at one
+// end are left out unless the range is empty, in which case a piece with
+// the single position repeated is returned.
+func (m lineMeasure) substring(lines []measuredLine, start, end float64) [][][]float64 {
+	var pieces [][][]float64
+	for _, line := range lines {
+		lo := math.Max(start, line.cum[0])
+		hi := math.Min(end, line.cum[len(line.cum)-1])
+		if lo > hi || (lo == hi && start != end) {
+			continue
+		}
+		piece := [][]float64{m.positionAt(line, lo)}
+		for j, s := range line.cum {
+			if s > lo && s < hi {
+				piece = append(piece, line.positions[j])
+			}
+		}
+		piece = append(piece, m.positionAt(line, hi))
+		pieces = append(pieces, piece)
+		if start == end {
+			break
+		}
+	}
+	return pieces
+}
+
+func (m lineMeasure) substringLines(parts [][][]float64, start, end float64) ([][][]float64, error) {
+	lines, total, err := m.measureLines(parts)
+	if err != nil {
+		return nil, err
+	}
+	if start, err = clampMeasure(start, total); err != nil {
+		return nil, err
+	}
+	if end, err = clampMeasure(end, total); err != nil {
+		return nil, err
+	}
+	if start <= end {
+		return m.substring(lines, start, end), nil
+	}
+	pieces := m.substring(lines, end, start)
+	reversed := make([][][]float64, len(pieces))
+	for i, piece := range pieces {
+		r := make([][]float64, len(piece))
+		for j, pos := range piece {
+			r[len(piece)-1-j] = pos
+		}
+		reversed[len(pieces)-1-i] = r
+	}
+	return reversed, nil
+}
+
+func (m lineMeasure) split(parts [][][]float64, points []*Point) ([][][]float64, error) {
+	lines, _, err := m.measureLines(parts)
+	if err != nil {
+		return nil, err
+	}
+	cuts := make([]float64, len(points))
+	for i, pt := range points {
+		if cuts[i], err = m.locate(parts, pt.Coordinates); err != nil {
+			return nil, err
+		}
+	}
+	sort.Float64s(cuts)
+
+	var pieces [][][]float64
+	for _, line := range lines {
+		if len(line.positions) < 2 {
+			continue
+		}
+		start, end := line.cum[0], line.cum[len(line.cum)-1]
+		for _, s := range cuts {
+			if s > start && s < end {
+				pieces = append(pieces, m.substring([]measuredLine{line}, start, s)...)
+				start = s
+			}
+		}
+		pieces = append(pieces, m.substring([]measuredLine{line}, start, end)...)
+	}
+	return pieces, nil
+}
+
+var planarMeasure = lineMeasure{false}
+var geodesicMeasure = lineMeasure{true}
+
+// Interpolate returns the Point a distance along the LineString from its
+// start, in coordinate units. Negative distances are measured back from the
+// end, and distances beyond either end give the end position.
+func (g *LineString) Interpolate(distance float64) (*Point, error) {
+	pos, err := planarMeasure.interpolate([][][]float64{g.Coordinates}, distance)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// InterpolateFraction returns the Point a fraction between 0 and 1 of the
+// way along the LineString
+func (g *LineString) InterpolateFraction(fraction float64) (*Point, error) {
+	pos, err := planarMeasure.interpolateFraction([][][]float64{g.Coordinates}, fraction)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// Locate returns the distance along the LineString of the position on it
+// closest to pt. Where several positions are equally close, the first is
+// used.
+func (g *LineString) Locate(pt *Point) (float64, error) {
+	return planarMeasure.locate([][][]float64{g.Coordinates}, pt.Coordinates)
+}
+
+// Substring returns the part of the LineString between two distances along
+// it, which are treated as for Interpolate. If start is greater than end
+// the result runs in the opposite direction to the LineString, and if they
+// are equal it repeats a single position.
+func (g *LineString) Substring(start, end float64) (*LineString, error) {
+	pieces, err := planarMeasure.substringLines([][][]float64{g.Coordinates}, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &LineString{g.CRSReferencable, pieces[0]}, nil
+}
+
+// Split returns the LineString divided at the positions on it closest to
+// each of the points. Points closest to either end do not divide it.
+func (g *LineString) Split(points ...*Point) (*MultiLineString, error) {
+	pieces, err := planarMeasure.split([][][]float64{g.Coordinates}, points)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
+
+// GeodesicInterpolate returns the Point a distance in metres along the
+// LineString, following geodesics on the WGS84 ellipsoid. See
+// LineString.Interpolate.
+func (g *LineString) GeodesicInterpolate(distance float64) (*Point, error) {
+	pos, err := geodesicMeasure.interpolate([][][]float64{g.Coordinates}, distance)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// GeodesicInterpolateFraction returns the Point a fraction between 0 and 1
+// of the way along the geodesic length of the LineString
+func (g *LineString) GeodesicInterpolateFraction(fraction float64) (*Point, error) {
+	pos, err := geodesicMeasure.interpolateFraction([][][]float64{g.Coordinates}, fraction)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// GeodesicLocate returns the distance in metres along the LineString of the
+// position on it closest to pt on the WGS84 ellipsoid
+func (g *LineString) GeodesicLocate(pt *Point) (float64, error) {
+	return geodesicMeasure.locate([][][]float64{g.Coordinates}, pt.Coordinates)
+}
+
+// GeodesicSubstring returns the part of the LineString between two
+// distances in metres along it. See LineString.Substring.
+func (g *LineString) GeodesicSubstring(start, end float64) (*LineString, error) {
+	pieces, err := geodesicMeasure.substringLines([][][]float64{g.Coordinates}, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &LineString{g.CRSReferencable, pieces[0]}, nil
+}
+
+// GeodesicSplit returns the LineString divided at the positions on it
+// closest to each of the points on the WGS84 ellipsoid
+func (g *LineString) GeodesicSplit(points ...*Point) (*MultiLineString, error) {
+	pieces, err := geodesicMeasure.split([][][]float64{g.Coordinates}, points)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
+
+// Interpolate returns the Point a distance along the MultiLineString, which
+// is measured through each line in turn without counting the gaps between
+// them. See LineString.Interpolate.
+func (g *MultiLineString) Interpolate(distance float64) (*Point, error) {
+	pos, err := planarMeasure.interpolate(g.Coordinates, distance)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// InterpolateFraction returns the Point a fraction between 0 and 1 of the
+// way along the MultiLineString
+func (g *MultiLineString) InterpolateFraction(fraction float64) (*Point, error) {
+	pos, err := planarMeasure.interpolateFraction(g.Coordinates, fraction)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// Locate returns the distance along the MultiLineString of the position on
+// it closest to pt
+func (g *MultiLineString) Locate(pt *Point) (float64, error) {
+	return planarMeasure.locate(g.Coordinates, pt.Coordinates)
+}
+
+// Substring returns the parts of the lines between two distances along the
+// MultiLineString. See LineString.Substring.
+func (g *MultiLineString) Substring(start, end float64) (*MultiLineString, error) {
+	pieces, err := planarMeasure.substringLines(g.Coordinates, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
+
+// Split returns the lines of the MultiLineString divided at the positions
+// on them closest to each of the points
+func (g *MultiLineString) Split(points ...*Point) (*MultiLineString, error) {
+	pieces, err := planarMeasure.split(g.Coordinates, points)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
+
+// GeodesicInterpolate returns the Point a distance in metres along the
+// MultiLineString on the WGS84 ellipsoid. See MultiLineString.Interpolate.
+func (g *MultiLineString) GeodesicInterpolate(distance float64) (*Point, error) {
+	pos, err := geodesicMeasure.interpolate(g.Coordinates, distance)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// GeodesicInterpolateFraction returns the Point a fraction between 0 and 1
+// of the way along the geodesic length of the MultiLineString
+func (g *MultiLineString) GeodesicInterpolateFraction(fraction float64) (*Point, error) {
+	pos, err := geodesicMeasure.interpolateFraction(g.Coordinates, fraction)
+	if err != nil {
+		return nil, err
+	}
+	return &Point{g.CRSReferencable, pos}, nil
+}
+
+// GeodesicLocate returns the distance in metres along the MultiLineString
+// of the position on it closest to pt on the WGS84 ellipsoid
+func (g *MultiLineString) GeodesicLocate(pt *Point) (float64, error) {
+	return geodesicMeasure.locate(g.Coordinates, pt.Coordinates)
+}
+
+// GeodesicSubstring returns the parts of the lines between two distances in
+// metres along the MultiLineString
+func (g *MultiLineString) GeodesicSubstring(start, end float64) (*MultiLineString, error) {
+	pieces, err := geodesicMeasure.substringLines(g.Coordinates, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
+
+// GeodesicSplit returns the lines of the MultiLineString divided at the
+// positions on them closest to each of the points on the WGS84 ellipsoid
+func (g *MultiLineString) GeodesicSplit(points ...*Point) (*MultiLineString, error) {
+	pieces, err := geodesicMeasure.split(g.Coordinates, points)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{g.CRSReferencable, pieces}, nil
+}
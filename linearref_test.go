@@ -0,0 +1,185 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+func TestLineStringInterpolate(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0, 10}, {3, 0, 20}, {3, 4, 30}}}
+	cases := []struct {
+		distance float64
+		expected []float64
+	}{
+		{0, []float64{0, 0, 10}},
+		{1.5, []float64{1.5, 0, 15}},
+		{5, []float64{3, 2, 25}},
+		{-1, []float64{3, 3, 27.5}},
+		{9, []float64{3, 4, 30}},
+	}
+	for _, c := range cases {
+		pt, err := ls.Interpolate(c.distance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(pt.Coordinates) != fmt.Sprint(c.expected) {
+			fmt.Println("recieved    ", pt.Coordinates)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+	}
+
+	pt, _ := ls.InterpolateFraction(0.5)
+	if fmt.Sprint(pt.Coordinates) != fmt.Sprint([]float64{3, 0.5, 21.25}) {
+		fmt.Println("recieved    ", pt.Coordinates)
+		t.Fail()
+	}
+	if _, err := ls.InterpolateFraction(1.5); err == nil {
+		t.Fail()
+	}
+	if _, err := (&LineString{}).Interpolate(1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLineStringLocate(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {3, 0}, {3, 4}}}
+	cases := [][3]float64{
+		{1, 1, 1},
+		{5, 2, 5},
+		{-2, -2, 0},
+		{4, 6, 7},
+	}
+	for _, c := range cases {
+		s, err := ls.Locate(&Point{Coordinates: []float64{c[0], c[1]}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != c[2] {
+			fmt.Println("recieved    ", s)
+			fmt.Println("but expected", c[2])
+			t.Fail()
+		}
+	}
+}
+
+func TestLineStringSubstring(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {3, 0}, {3, 4}}}
+	sub, err := ls.Substring(1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]float64{{1, 0}, {3, 0}, {3, 2}}
+	if fmt.Sprint(sub.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", sub.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	// reversed, and counted back from the end
+	sub, _ = ls.Substring(-2, 1)
+	expected = [][]float64{{3, 2}, {3, 0}, {1, 0}}
+	if fmt.Sprint(sub.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", sub.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	sub, _ = ls.Substring(3, 3)
+	if fmt.Sprint(sub.Coordinates) != fmt.Sprint([][]float64{{3, 0}, {3, 0}}) {
+		fmt.Println("recieved    ", sub.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestLineStringSplit(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {3, 0}, {3, 4}}}
+	parts, err := ls.Split(&Point{Coordinates: []float64{4, 2}}, &Point{Coordinates: []float64{1, -1}},
+		&Point{Coordinates: []float64{-1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][][]float64{{{0, 0}, {1, 0}}, {{1, 0}, {3, 0}, {3, 2}}, {{3, 2}, {3, 4}}}
+	if fmt.Sprint(parts.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", parts.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestMultiLineStringLinearReferencing(t *testing.T) {
+	mls := &MultiLineString{Coordinates: [][][]float64{{{0, 0}, {2, 0}}, {{10, 0}, {10, 3}}}}
+	pt, _ := mls.Interpolate(3)
+	if fmt.Sprint(pt.Coordinates) != fmt.Sprint([]float64{10, 1}) {
+		fmt.Println("recieved    ", pt.Coordinates)
+		t.Fail()
+	}
+	s, _ := mls.Locate(&Point{Coordinates: []float64{9, 2}})
+	if s != 4 {
+		fmt.Println("recieved    ", s)
+		t.Fail()
+	}
+	sub, _ := mls.Substring(1, 4)
+	expected := [][][]float64{{{1, 0}, {2, 0}}, {{10, 0}, {10, 2}}}
+	if fmt.Sprint(sub.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", sub.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	// the end of the first line only touches the range
+	sub, _ = mls.Substring(2, 3)
+	if len(sub.Coordinates) != 1 {
+		fmt.Println("recieved    ", sub.Coordinates)
+		t.Fail()
+	}
+	parts, _ := mls.Split(&Point{Coordinates: []float64{1, 1}})
+	if len(parts.Coordinates) != 3 {
+		fmt.Println("recieved    ", parts.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestGeodesicLinearReferencing(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {10, 0}, {10, 10}}}
+	first, _, _ := geod.WGS84.Inverse(0, 0, 0, 10)
+	second, _, _ := geod.WGS84.Inverse(0, 10, 10, 10)
+
+	pt, err := ls.GeodesicInterpolate(first + second/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _, _ := geod.WGS84.Inverse(0, 10, pt.Coordinates[1], pt.Coordinates[0])
+	if math.Abs(pt.Coordinates[0]-10) > 1e-9 || math.Abs(s-second/2) > 1e-6 {
+		fmt.Println("recieved    ", pt.Coordinates)
+		t.Fail()
+	}
+
+	// locating the interpolated point recovers its distance
+	loc, _ := ls.GeodesicLocate(pt)
+	if math.Abs(loc-(first+second/2)) > 1e-6 {
+		fmt.Println("recieved    ", loc)
+		fmt.Println("but expected", first+second/2)
+		t.Fail()
+	}
+
+	pt, _ = ls.GeodesicInterpolateFraction(1)
+	if fmt.Sprint(pt.Coordinates) != fmt.Sprint([]float64{10, 10}) {
+		fmt.Println("recieved    ", pt.Coordinates)
+		t.Fail()
+	}
+
+	sub, _ := ls.GeodesicSubstring(first/2, first)
+	if len(sub.Coordinates) != 2 || math.Abs(sub.Coordinates[0][0]-5) > 1e-9 || sub.Coordinates[0][1] != 0 {
+		fmt.Println("recieved    ", sub.Coordinates)
+		t.Fail()
+	}
+
+	parts, _ := ls.GeodesicSplit(&Point{Coordinates: []float64{5, 1}})
+	if len(parts.Coordinates) != 2 || math.Abs(parts.Coordinates[0][1][0]-5) > 1e-9 {
+		fmt.Println("recieved    ", parts.Coordinates)
+		t.Fail()
+	}
+}
@@ -0,0 +1,108 @@
+/* functions for repairing invalid polygons */
+package geojson
+
+import (
+	"fmt"
+	"math"
+)
+
+// cleanRing returns the positions of a ring that have finite x and y
+// coordinates
+func cleanRing(ring [][]float64) [][]float64 {
+	clean := make([][]float64, 0, len(ring))
+	for _, pos := range ring {
+		if len(pos) < 2 || math.IsNaN(pos[0]) || math.IsNaN(pos[1]) ||
+			math.IsInf(pos[0], 0) || math.IsInf(pos[1], 0) {
+			continue
+		}
+		clean = append(clean, pos)
+	}
+	return clean
+}
+
+// ringRegions returns the area enclosed by a ring as valid polygons, using
+// the even-odd rule so that the lobes of a self-intersecting ring are
+// split into separate polygons whatever their orientation. Repeated
+// positions and spikes that enclose no area are removed, and the ring need
+// not be closed.
+func ringRegions(ring [][]float64) [][][][]float64 {
+	edges := appendRawRingEdges(nil, cleanRing(ring), 0)
+	return overlayAreas(edges, func(w [2]int) bool { return w[0]%2 != 0 })
+}
+
+// makeValidPolygon returns the area of the shell of a polygon less the area
+// of its holes, along with any parts of the holes outside the shell
+func makeValidPolygon(rings [][][]float64) (valid, orphans [][][][]float64) {
+	var edges []overlayEdge
+	for i, ring := range rings {
+		op := 0
+		if i != 0 {
+			op = 1
+		}
+		for _, region := range ringRegions(ring) {
+			edges = appendPolygonEdges(edges, region, op)
+		}
+	}
+	valid = overlayAreas(edges, func(w [2]int) bool { return w[0] > 0 && w[1] == 0 })
+	orphans = overlayAreas(edges, func(w [2]int) bool { return w[0] == 0 && w[1] > 0 })
+	return valid, orphans
+}
+
+func makeValid(g *Geo) (*Geo, error) {
+	var polygons [][][][]float64
+	var crs CRSReferencable
+	switch g.Type {
+	case "Polygon":
+		crs = g.Polygon.CRSReferencable
+		polygons = [][][][]float64{g.Polygon.Coordinates}
+	case "MultiPolygon":
+		crs = g.MultiPolygon.CRSReferencable
+		polygons = g.MultiPolygon.Coordinates
+	default:
+		return nil, fmt.Errorf("cannot make a %s valid", g.Type)
+	}
+
+	// holes outside their own shell are cut from the other polygons
+	var edges []overlayEdge
+	for _, rings := range polygons {
+		valid, orphans := makeValidPolygon(rings)
+		for _, poly := range valid {
+			edges = appendPolygonEdges(edges, poly, 0)
+		}
+		for _, poly := range orphans {
+			edges = appendPolygonEdges(edges, poly, 1)
+		}
+	}
+	result := areasGeo(overlayAreas(edges, func(w [2]int) bool { return w[0] > 0 && w[1] == 0 }))
+	if result.Type == "Polygon" {
+		result.Polygon.CRSReferencable = crs
+	} else {
+		result.MultiPolygon.CRSReferencable = crs
+	}
+	return result, nil
+}
+
+// MakeValid returns a valid version of the Polygon. See Geo.MakeValid.
+func (g *Polygon) MakeValid() (*Geo, error) {
+	return makeValid(&Geo{Type: "Polygon", Polygon: g})
+}
+
+// MakeValid returns a valid version of the MultiPolygon. See Geo.MakeValid.
+func (g *MultiPolygon) MakeValid() (*Geo, error) {
+	return makeValid(&Geo{Type: "MultiPolygon", MultiPolygon: g})
+}
+
+// MakeValid repairs a Polygon or MultiPolygon, returning a Polygon, or a
+// MultiPolygon when the repaired area has several parts or is empty.
+//
+// Repeated and non-finite positions are removed and rings are closed. Each
+// ring encloses the area inside an odd number of its loops, so a bow-tie
+// becomes two parts and spikes that enclose no area are dropped. The area
+// of each polygon is that of its first ring less that of its holes.
+// Polygons of a MultiPolygon that overlap are merged, and holes that lie
+// outside their own shell are cut from the other polygons, so that a hole
+// listed with the wrong shell is moved to the right one. The result has
+// counter-clockwise shells, each with the clockwise holes it contains.
+func (g *Geo) MakeValid() (*Geo, error) {
+	return makeValid(g)
+}
@@ -0,0 +1,109 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestMakeValidBowtie(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}}}
+	valid, err := poly.MakeValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid.Type != "MultiPolygon" || len(valid.MultiPolygon.Coordinates) != 2 {
+		t.Fatal("expected a MultiPolygon with two parts, got", valid)
+	}
+	for _, part := range valid.MultiPolygon.Coordinates {
+		if len(part[0]) != 4 || ringArea(part[0]) != 1 {
+			fmt.Println("recieved    ", part)
+			t.Fail()
+		}
+	}
+}
+
+func TestMakeValidRepeatedAndUnclosed(t *testing.T) {
+	// clockwise, unclosed, with a repeated position, a spike and a NaN
+	poly := &Polygon{Coordinates: [][][]float64{{
+		{0, 0}, {0, 3}, {0, 3}, {3, 3}, {5, 3}, {3, 3}, {math.NaN(), 1}, {3, 0},
+	}}}
+	valid, err := poly.MakeValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid.Type != "Polygon" {
+		t.Fatal("expected a Polygon, got", valid.Type)
+	}
+	expected := [][][]float64{{{0, 0}, {3, 0}, {3, 3}, {0, 3}, {0, 0}}}
+	if fmt.Sprint(valid.Polygon.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", valid.Polygon.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestMakeValidHoles(t *testing.T) {
+	// the hole crosses the shell and the second polygon's hole lies in the first
+	mp := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}, {{3, 1}, {5, 1}, {5, 2}, {3, 2}, {3, 1}}},
+		{{{10, 0}, {14, 0}, {14, 4}, {10, 4}, {10, 0}}, {{1, 3}, {2, 3}, {2, 2}, {1, 2}, {1, 3}}},
+	}}
+	valid, err := mp.MakeValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid.Type != "MultiPolygon" || len(valid.MultiPolygon.Coordinates) != 2 {
+		t.Fatal("expected a MultiPolygon with two parts, got", valid)
+	}
+	var area float64
+	var holes int
+	for _, part := range valid.MultiPolygon.Coordinates {
+		holes += len(part) - 1
+		for _, ring := range part {
+			area += ringArea(ring)
+		}
+	}
+	if holes != 1 || area != 16-1-1+16 {
+		fmt.Println("recieved    ", valid.MultiPolygon.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestMakeValidOverlapping(t *testing.T) {
+	mp := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+	}}
+	valid, err := mp.MakeValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid.Type != "Polygon" || ringArea(valid.Polygon.Coordinates[0]) != 7 {
+		fmt.Println("recieved    ", valid)
+		t.Fail()
+	}
+}
+
+func TestMakeValidIsland(t *testing.T) {
+	// an island inside the hole of another polygon is already valid
+	mp := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}}, {{1, 1}, {1, 5}, {5, 5}, {5, 1}, {1, 1}}},
+		{{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}},
+	}}
+	valid, err := mp.MakeValid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid.Type != "MultiPolygon" || len(valid.MultiPolygon.Coordinates) != 2 || valid.Area() != 24 {
+		fmt.Println("recieved    ", valid)
+		t.Fail()
+	}
+}
+
+func TestMakeValidType(t *testing.T) {
+	g := &Geo{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{0, 0}, {1, 1}}}}
+	if _, err := g.MakeValid(); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,358 @@
+/* functions for measuring the area and length of geometries */
+package geojson
+
+import (
+	"math"
+
+	"github.com/njwilson23/geojson.go/internal/geod"
+)
+
+// ringArea returns the signed planar area of a ring, positive when the ring
+// is counter-clockwise
+func ringArea(ring [][]float64) float64 {
+	var sum float64
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		sum += (ring[j][0] - ring[i][0]) * (ring[j][1] + ring[i][1])
+	}
+	return 0.5 * sum
+}
+
+// pathLength returns the planar length of a sequence of positions
+func pathLength(positions [][]float64) float64 {
+	var sum float64
+	for i := 1; i < len(positions); i++ {
+		sum += math.Hypot(positions[i][0]-positions[i-1][0], positions[i][1]-positions[i-1][1])
+	}
+	return sum
+}
+
+// polygonArea returns the planar area of a polygon with holes
+func polygonArea(rings [][][]float64) float64 {
+	var area float64
+	for i, ring := range rings {
+		if i == 0 {
+			area += math.Abs(ringArea(ring))
+		} else {
+			area -= math.Abs(ringArea(ring))
+		}
+	}
+	return area
+}
+
+// openRing returns the ring without a duplicated closing position
+func openRing(ring [][]float64) [][]float64 {
+	n := len(ring)
+	if n > 1 && ring[0][0] == ring[n-1][0] && ring[0][1] == ring[n-1][1] {
+		return ring[:n-1]
+	}
+	return ring
+}
+
+// geodesicRingArea returns the unsigned area in square metres and the
+// perimeter in metres of a ring on the WGS84 ellipsoid
+func geodesicRingArea(ring [][]float64) (float64, float64) {
+	ring = openRing(ring)
+	lats := make([]float64, len(ring))
+	lons := make([]float64, len(ring))
+	for i, pos := range ring {
+		lons[i] = pos[0]
+		lats[i] = pos[1]
+	}
+	area, perimeter := geod.WGS84.PolygonArea(lats, lons)
+	return math.Abs(area), perimeter
+}
+
+// geodesicPathLength returns the length in metres of a sequence of
+// positions joined by geodesics on the WGS84 ellipsoid
+func geodesicPathLength(positions [][]float64) float64 {
+	var sum float64
+	for i := 1; i < len(positions); i++ {
+		s12, _, _ := geod.WGS84.Inverse(positions[i-1][1], positions[i-1][0],
+			positions[i][1], positions[i][0])
+		sum += s12
+	}
+	return sum
+}
+
+func geodesicPolygonArea(rings [][][]float64) float64 {
+	var area float64
+	for i, ring := range rings {
+		a, _ := geodesicRingArea(ring)
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	return area
+}
+
+func geodesicPolygonPerimeter(rings [][][]float64) float64 {
+	var perimeter float64
+	for _, ring := range rings {
+		_, p := geodesicRingArea(ring)
+		perimeter += p
+	}
+	return perimeter
+}
+
+func polygonPerimeter(rings [][][]float64) float64 {
+	var perimeter float64
+	for _, ring := range rings {
+		perimeter += pathLength(ring)
+		if n := len(ring); n > 1 {
+			perimeter += math.Hypot(ring[0][0]-ring[n-1][0], ring[0][1]-ring[n-1][1])
+		}
+	}
+	return perimeter
+}
+
+/* Planar measurements, in the units of the coordinates */
+
+// Area returns zero, since a LineString has no area
+func (g *LineString) Area() float64 {
+	return 0
+}
+
+// Length returns the planar length of the LineString
+func (g *LineString) Length() float64 {
+	return pathLength(g.Coordinates)
+}
+
+// Area returns the planar area of the Polygon, excluding holes
+func (g *Polygon) Area() float64 {
+	return polygonArea(g.Coordinates)
+}
+
+// Length returns the planar perimeter of the Polygon, including holes
+func (g *Polygon) Length() float64 {
+	return polygonPerimeter(g.Coordinates)
+}
+
+// Area returns zero, since a MultiLineString has no area
+func (g *MultiLineString) Area() float64 {
+	return 0
+}
+
+// Length returns the total planar length of the MultiLineString
+func (g *MultiLineString) Length() float64 {
+	var length float64
+	for _, line := range g.Coordinates {
+		length += pathLength(line)
+	}
+	return length
+}
+
+// Area returns the total planar area of the MultiPolygon
+func (g *MultiPolygon) Area() float64 {
+	var area float64
+	for _, poly := range g.Coordinates {
+		area += polygonArea(poly)
+	}
+	return area
+}
+
+// Length returns the total planar perimeter of the MultiPolygon
+func (g *MultiPolygon) Length() float64 {
+	var length float64
+	for _, poly := range g.Coordinates {
+		length += polygonPerimeter(poly)
+	}
+	return length
+}
+
+// Area returns the sum of the planar areas of the member geometries
+func (coll *GeometryCollection) Area() float64 {
+	var area float64
+	for _, g := range coll.Geometries {
+		area += g.Area()
+	}
+	return area
+}
+
+// Length returns the sum of the planar lengths of the member geometries
+func (coll *GeometryCollection) Length() float64 {
+	var length float64
+	for _, g := range coll.Geometries {
+		length += g.Length()
+	}
+	return length
+}
+
+// Area returns the planar area of the Feature geometry
+func (f *Feature) Area() float64 {
+	return f.Geometry.Area()
+}
+
+// Length returns the planar length of the Feature geometry
+func (f *Feature) Length() float64 {
+	return f.Geometry.Length()
+}
+
+// Area returns the planar area of any geometry or Feature. Points and
+// unrecognized types have zero area.
+func (g *Geo) Area() float64 {
+	switch g.Type {
+	case "LineString":
+		return g.LineString.Area()
+	case "Polygon":
+		return g.Polygon.Area()
+	case "MultiLineString":
+		return g.MultiLineString.Area()
+	case "MultiPolygon":
+		return g.MultiPolygon.Area()
+	case "GeometryCollection":
+		return g.GeometryCollection.Area()
+	case "Feature":
+		return g.Feature.Area()
+	}
+	return 0
+}
+
+// Length returns the planar length of any geometry or Feature. Points and
+// unrecognized types have zero length.
+func (g *Geo) Length() float64 {
+	switch g.Type {
+	case "LineString":
+		return g.LineString.Length()
+	case "Polygon":
+		return g.Polygon.Length()
+	case "MultiLineString":
+		return g.MultiLineString.Length()
+	case "MultiPolygon":
+		return g.MultiPolygon.Length()
+	case "GeometryCollection":
+		return g.GeometryCollection.Length()
+	case "Feature":
+		return g.Feature.Length()
+	}
+	return 0
+}
+
+/* Geodesic measurements on the WGS84 ellipsoid, treating coordinates as
+ * longitude and latitude in degrees */
+
+// GeodesicArea returns zero, since a LineString has no area
+func (g *LineString) GeodesicArea() float64 {
+	return 0
+}
+
+// GeodesicLength returns the length of the LineString in metres
+func (g *LineString) GeodesicLength() float64 {
+	return geodesicPathLength(g.Coordinates)
+}
+
+// GeodesicArea returns the area of the Polygon in square metres, excluding
+// holes
+func (g *Polygon) GeodesicArea() float64 {
+	return geodesicPolygonArea(g.Coordinates)
+}
+
+// GeodesicLength returns the perimeter of the Polygon in metres, including
+// holes
+func (g *Polygon) GeodesicLength() float64 {
+	return geodesicPolygonPerimeter(g.Coordinates)
+}
+
+// GeodesicArea returns zero, since a MultiLineString has no area
+func (g *MultiLineString) GeodesicArea() float64 {
+	return 0
+}
+
+// GeodesicLength returns the total length of the MultiLineString in metres
+func (g *MultiLineString) GeodesicLength() float64 {
+	var length float64
+	for _, line := range g.Coordinates {
+		length += geodesicPathLength(line)
+	}
+	return length
+}
+
+// GeodesicArea returns the total area of the MultiPolygon in square metres
+func (g *MultiPolygon) GeodesicArea() float64 {
+	var area float64
+	for _, poly := range g.Coordinates {
+		area += geodesicPolygonArea(poly)
+	}
+	return area
+}
+
+// GeodesicLength returns the total perimeter of the MultiPolygon in metres
+func (g *MultiPolygon) GeodesicLength() float64 {
+	var length float64
+	for _, poly := range g.Coordinates {
+		length += geodesicPolygonPerimeter(poly)
+	}
+	return length
+}
+
+// GeodesicArea returns the sum of the areas of the member geometries in
+// square metres
+func (coll *GeometryCollection) GeodesicArea() float64 {
+	var area float64
+	for _, g := range coll.Geometries {
+		area += g.GeodesicArea()
+	}
+	return area
+}
+
+// GeodesicLength returns the sum of the lengths of the member geometries in
+// metres
+func (coll *GeometryCollection) GeodesicLength() float64 {
+	var length float64
+	for _, g := range coll.Geometries {
+		length += g.GeodesicLength()
+	}
+	return length
+}
+
+// GeodesicArea returns the area of the Feature geometry in square metres
+func (f *Feature) GeodesicArea() float64 {
+	return f.Geometry.GeodesicArea()
+}
+
+// GeodesicLength returns the length of the Feature geometry in metres
+func (f *Feature) GeodesicLength() float64 {
+	return f.Geometry.GeodesicLength()
+}
+
+// GeodesicArea returns the area of any geometry or Feature in square metres.
+// Points and unrecognized types have zero area.
+func (g *Geo) GeodesicArea() float64 {
+	switch g.Type {
+	case "LineString":
+		return g.LineString.GeodesicArea()
+	case "Polygon":
+		return g.Polygon.GeodesicArea()
+	case "MultiLineString":
+		return g.MultiLineString.GeodesicArea()
+	case "MultiPolygon":
+		return g.MultiPolygon.GeodesicArea()
+	case "GeometryCollection":
+		return g.GeometryCollection.GeodesicArea()
+	case "Feature":
+		return g.Feature.GeodesicArea()
+	}
+	return 0
+}
+
+// GeodesicLength returns the length of any geometry or Feature in metres.
+// Points and unrecognized types have zero length.
+func (g *Geo) GeodesicLength() float64 {
+	switch g.Type {
+	case "LineString":
+		return g.LineString.GeodesicLength()
+	case "Polygon":
+		return g.Polygon.GeodesicLength()
+	case "MultiLineString":
+		return g.MultiLineString.GeodesicLength()
+	case "MultiPolygon":
+		return g.MultiPolygon.GeodesicLength()
+	case "GeometryCollection":
+		return g.GeometryCollection.GeodesicLength()
+	case "Feature":
+		return g.Feature.GeodesicLength()
+	}
+	return 0
+}
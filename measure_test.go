@@ -0,0 +1,98 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPlanarArea(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}},
+	}}
+	if poly.Area() != 15 {
+		fmt.Println("recieved", poly.Area())
+		t.Fail()
+	}
+	if poly.Length() != 20 {
+		fmt.Println("recieved", poly.Length())
+		t.Fail()
+	}
+
+	// winding order of the input does not change the area
+	poly.Coordinates[0] = [][]float64{{0, 0}, {0, 4}, {4, 4}, {4, 0}, {0, 0}}
+	if poly.Area() != 15 {
+		t.Fail()
+	}
+
+	mpoly := &MultiPolygon{Coordinates: [][][][]float64{
+		poly.Coordinates,
+		{{{10, 10}, {11, 10}, {11, 11}, {10, 10}}},
+	}}
+	if mpoly.Area() != 15.5 {
+		fmt.Println("recieved", mpoly.Area())
+		t.Fail()
+	}
+}
+
+func TestPlanarLength(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {3, 4}, {3, 6}}}
+	if ls.Length() != 7 || ls.Area() != 0 {
+		t.Fail()
+	}
+	mls := &MultiLineString{Coordinates: [][][]float64{ls.Coordinates, {{0, 0}, {1, 0}}}}
+	if mls.Length() != 8 {
+		t.Fail()
+	}
+
+	coll := &GeometryCollection{Geometries: []*Geo{
+		{Type: "Point", Point: &Point{Coordinates: []float64{0, 0}}},
+		{Type: "LineString", LineString: ls},
+		{Type: "Polygon", Polygon: &Polygon{Coordinates: [][][]float64{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}}},
+	}}
+	if coll.Length() != 15 || coll.Area() != 4 {
+		fmt.Println("recieved", coll.Length(), coll.Area())
+		t.Fail()
+	}
+	f := &Feature{Geometry: Geo{Type: "GeometryCollection", GeometryCollection: coll}}
+	if f.Length() != 15 || f.Area() != 4 {
+		t.Fail()
+	}
+}
+
+func TestGeodesicArea(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+	}}
+	if math.Abs(poly.GeodesicArea()-12308778361.469) > 1e-2 {
+		fmt.Println("recieved", poly.GeodesicArea())
+		t.Fail()
+	}
+
+	// a hole removes its area regardless of winding
+	poly.Coordinates = append(poly.Coordinates, [][]float64{
+		{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}, {0.25, 0.25},
+	})
+	hole := &Polygon{Coordinates: poly.Coordinates[1:]}
+	expected := 12308778361.469 - hole.GeodesicArea()
+	if math.Abs(poly.GeodesicArea()-expected) > 1e-2 {
+		t.Fail()
+	}
+	if math.Abs(hole.GeodesicArea()/3077e6-1) > 1e-3 {
+		fmt.Println("recieved", hole.GeodesicArea())
+		t.Fail()
+	}
+}
+
+func TestGeodesicLength(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {0, 90}}}
+	if math.Abs(ls.GeodesicLength()-10001965.729313) > 1e-5 {
+		fmt.Println("recieved", ls.GeodesicLength())
+		t.Fail()
+	}
+	geo := &Geo{Type: "Feature", Feature: &Feature{Geometry: Geo{Type: "LineString", LineString: ls}}}
+	if geo.GeodesicLength() != ls.GeodesicLength() {
+		t.Fail()
+	}
+}
@@ -0,0 +1,473 @@
+/* planar overlay of polygonal linework, shared by buffers and boolean operations */
+package geojson
+
+import (
+	"math"
+	"sort"
+)
+
+// overlayEdge is a directed segment carrying the change in the winding
+// number of each of two operands when it is crossed from right to left
+type overlayEdge struct {
+	a, b  []float64
+	delta [2]int
+}
+
+// appendRingEdges appends the edges of a ring to operand op, so that its
+// interior has winding number +1 if ccw is true and -1 otherwise, whatever
+// the orientation the ring is stored in
+func appendRingEdges(edges []overlayEdge, ring [][]float64, op int, ccw bool) []overlayEdge {
+	area := ringArea(ring)
+	if area == 0 {
+		return edges
+	}
+	d := 1
+	if (area > 0) != ccw {
+		d = -1
+	}
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		var delta [2]int
+		delta[op] = d
+		edges = append(edges, overlayEdge{ring[j], ring[i], delta})
+	}
+	return edges
+}
+
+// appendRawRingEdges appends the edges of a ring to operand op in the
+// direction they are stored, so that the parts of a self-intersecting ring
+// keep winding numbers of opposite sign
+func appendRawRingEdges(edges []overlayEdge, ring [][]float64, op int) []overlayEdge {
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		var delta [2]int
+		delta[op] = 1
+		edges = append(edges, overlayEdge{ring[j], ring[i], delta})
+	}
+	return edges
+}
+
+// appendPolygonEdges appends the rings of a polygon to operand op with the
+// shell counter-clockwise and the holes clockwise
+func appendPolygonEdges(edges []overlayEdge, rings [][][]float64, op int) []overlayEdge {
+	for i, ring := range rings {
+		edges = appendRingEdges(edges, ring, op, i == 0)
+	}
+	return edges
+}
+
+// nodeEdges splits the edges wherever they meet one another by snap
+// rounding. The ends of the edges and the positions where they cross mark
+// cells of a grid of spacing quantum as hot, and every edge is replaced by
+// the path through the centres of the hot cells it passes through. However
+// close the edges come, the pieces then meet only at shared ends or
+// coincide.
+func nodeEdges(edges []overlayEdge, quantum float64) []overlayEdge {
+	type cell [2]float64
+	cellOf := func(pos []float64) cell {
+		return cell{math.Round(pos[0] / quantum), math.Round(pos[1] / quantum)}
+	}
+	// hot holds a position for each hot cell, at its centre and with any
+	// coordinates beyond the second of the first end found there
+	hot := make(map[cell][]float64)
+	mark := func(pos []float64) {
+		c := cellOf(pos)
+		if _, ok := hot[c]; !ok {
+			centre := append([]float64{}, pos...)
+			centre[0], centre[1] = c[0]*quantum, c[1]*quantum
+			hot[c] = centre
+		}
+	}
+	for _, e := range edges {
+		mark(e.a)
+		mark(e.b)
+	}
+
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return minf(edges[order[i]].a[0], edges[order[i]].b[0]) < minf(edges[order[j]].a[0], edges[order[j]].b[0])
+	})
+	for ii, i := range order {
+		ei := edges[i]
+		xmax := maxf(ei.a[0], ei.b[0])
+		ymin, ymax := minf(ei.a[1], ei.b[1]), maxf(ei.a[1], ei.b[1])
+		for _, j := range order[ii+1:] {
+			ej := edges[j]
+			if minf(ej.a[0], ej.b[0]) > xmax {
+				break
+			}
+			if minf(ej.a[1], ej.b[1]) > ymax || maxf(ej.a[1], ej.b[1]) < ymin {
+				continue
+			}
+			for _, pos := range segmentIntersection(ei.a, ei.b, ej.a, ej.b) {
+				mark(pos)
+			}
+		}
+	}
+
+	cells := make([]cell, 0, len(hot))
+	for c := range hot {
+		cells = append(cells, c)
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i][0] < cells[j][0] })
+
+	type stop struct {
+		t   float64
+		pos []float64
+	}
+	half := quantum / 2
+	noded := make([]overlayEdge, 0, len(edges))
+	for _, e := range edges {
+		ca, cb := cellOf(e.a), cellOf(e.b)
+		if ca == cb {
+			continue
+		}
+		// the cells are visited in the order the edge enters them, from the
+		// cell of its start to the cell of its end
+		stops := []stop{{-1, hot[ca]}, {2, hot[cb]}}
+		xmin, xmax := minf(e.a[0], e.b[0])-quantum, maxf(e.a[0], e.b[0])+quantum
+		ymin, ymax := minf(e.a[1], e.b[1])-quantum, maxf(e.a[1], e.b[1])+quantum
+		first := sort.Search(len(cells), func(k int) bool { return cells[k][0]*quantum >= xmin })
+		for _, c := range cells[first:] {
+			x, y := c[0]*quantum, c[1]*quantum
+			if x > xmax {
+				break
+			}
+			if y < ymin || y > ymax || c == ca || c == cb {
+				continue
+			}
+			// cells touched only at an end of the edge are not passed through
+			t0, t1, ok := liangBarsky(e.a, e.b, [4]float64{x - half, y - half, x + half, y + half})
+			if ok && !(t0 == t1 && (t0 == 0 || t0 == 1)) {
+				stops = append(stops, stop{t0, hot[c]})
+			}
+		}
+		sort.Slice(stops, func(i, j int) bool { return stops[i].t < stops[j].t })
+		for k := 1; k < len(stops); k++ {
+			if !samePosition(stops[k-1].pos, stops[k].pos) {
+				noded = append(noded, overlayEdge{stops[k-1].pos, stops[k].pos, e.delta})
+			}
+		}
+	}
+	return noded
+}
+
+// overlayGraph is the planar graph formed by noded overlay edges, with
+// coincident edges merged. Half-edges 2k and 2k+1 run in opposite
+// directions along edge k.
+type overlayGraph struct {
+	vertices [][]float64
+	from     []int
+	delta    [][2]int
+	angle    []float64
+	outgoing [][]int
+}
+
+func (og *overlayGraph) to(h int) int {
+	return og.from[h^1]
+}
+
+// newOverlayGraph nodes and merges the edges. Positions are snapped to a
+// grid whose spacing is a small multiple of the coordinate magnitude, to
+// absorb round-off in computed intersections. The spacing is a power of
+// two, so that coordinates with few significant bits, such as integers,
+// are unchanged.
+func newOverlayGraph(edges []overlayEdge) *overlayGraph {
+	var scale float64
+	for _, e := range edges {
+		scale = math.Max(scale, math.Max(math.Abs(e.a[0]), math.Abs(e.a[1])))
+		scale = math.Max(scale, math.Max(math.Abs(e.b[0]), math.Abs(e.b[1])))
+	}
+	quantum := math.Exp2(math.Ceil(math.Log2(math.Max(scale, 1) * 1e-12)))
+	edges = nodeEdges(edges, quantum)
+
+	og := new(overlayGraph)
+	ids := make(map[[2]float64]int)
+	vertex := func(pos []float64) int {
+		key := [2]float64{math.Round(pos[0] / quantum), math.Round(pos[1] / quantum)}
+		id, ok := ids[key]
+		if !ok {
+			id = len(og.vertices)
+			ids[key] = id
+			og.vertices = append(og.vertices, pos)
+		}
+		return id
+	}
+
+	merged := make(map[[2]int]int)
+	var ends [][2]int
+	var deltas [][2]int
+	for _, e := range edges {
+		a, b := vertex(e.a), vertex(e.b)
+		if a == b {
+			continue
+		}
+		d := e.delta
+		if a > b {
+			a, b = b, a
+			d = [2]int{-d[0], -d[1]}
+		}
+		k, ok := merged[[2]int{a, b}]
+		if !ok {
+			k = len(ends)
+			merged[[2]int{a, b}] = k
+			ends = append(ends, [2]int{a, b})
+			deltas = append(deltas, [2]int{})
+		}
+		deltas[k][0] += d[0]
+		deltas[k][1] += d[1]
+	}
+
+	// edges that change neither winding number separate nothing
+	og.outgoing = make([][]int, len(og.vertices))
+	for k, end := range ends {
+		d := deltas[k]
+		if d == [2]int{} {
+			continue
+		}
+		for _, h := range [2]struct {
+			a, b int
+			d    [2]int
+		}{{end[0], end[1], d}, {end[1], end[0], [2]int{-d[0], -d[1]}}} {
+			id := len(og.from)
+			a, b := og.vertices[h.a], og.vertices[h.b]
+			og.from = append(og.from, h.a)
+			og.delta = append(og.delta, h.d)
+			og.angle = append(og.angle, math.Atan2(b[1]-a[1], b[0]-a[0]))
+			og.outgoing[h.a] = append(og.outgoing[h.a], id)
+		}
+	}
+	for _, out := range og.outgoing {
+		sort.Slice(out, func(i, j int) bool { return og.angle[out[i]] < og.angle[out[j]] })
+	}
+	return og
+}
+
+// next returns the half-edge that continues the boundary of the face to the
+// left of h: the first edge clockwise from the reverse of h
+func (og *overlayGraph) next(h int) int {
+	out := og.outgoing[og.to(h)]
+	for i, cand := range out {
+		if cand == h^1 {
+			return out[(i+len(out)-1)%len(out)]
+		}
+	}
+	return -1
+}
+
+// leftWinding returns the winding numbers of the face to the left of
+// half-edge h, by counting crossings along a ray leaving the middle of h
+// perpendicular to it
+func (og *overlayGraph) leftWinding(h int) [2]int {
+	a, b := og.vertices[og.from[h]], og.vertices[og.to(h)]
+	mx, my := 0.5*(a[0]+b[0]), 0.5*(a[1]+b[1])
+	nx, ny := -(b[1] - a[1]), b[0]-a[0]
+
+	var w [2]int
+	for e := 0; e < len(og.from); e += 2 {
+		if e == h&^1 {
+			continue
+		}
+		p, q := og.vertices[og.from[e]], og.vertices[og.to(e)]
+		pu := (p[0]-mx)*nx + (p[1]-my)*ny
+		pv := nx*(p[1]-my) - ny*(p[0]-mx)
+		qu := (q[0]-mx)*nx + (q[1]-my)*ny
+		qv := nx*(q[1]-my) - ny*(q[0]-mx)
+		var sign int
+		switch {
+		case pv <= 0 && qv > 0:
+			sign = 1
+		case qv <= 0 && pv > 0:
+			sign = -1
+		default:
+			continue
+		}
+		if pu+(0-pv)*(qu-pu)/(qv-pv) > 0 {
+			w[0] += sign * og.delta[e][0]
+			w[1] += sign * og.delta[e][1]
+		}
+	}
+	return w
+}
+
+// overlayAreas returns the polygons covering the parts of the plane where
+// inside returns true for the winding numbers of the operands. Shells are
+// counter-clockwise and holes clockwise.
+func overlayAreas(edges []overlayEdge, inside func(w [2]int) bool) [][][][]float64 {
+	og := newOverlayGraph(edges)
+
+	// trace the face cycles
+	cycle := make([]int, len(og.from))
+	for i := range cycle {
+		cycle[i] = -1
+	}
+	var cycles [][]int
+	for h := range og.from {
+		if cycle[h] != -1 {
+			continue
+		}
+		id := len(cycles)
+		cycles = append(cycles, nil)
+		for e := h; e != -1 && cycle[e] == -1; e = og.next(e) {
+			cycle[e] = id
+			cycles[id] = append(cycles[id], e)
+		}
+	}
+
+	// find the winding numbers of one face in each connected part of the
+	// graph with a ray, then step across edges to the neighbouring faces
+	winding := make([][2]int, len(cycles))
+	known := make([]bool, len(cycles))
+	for id := range cycles {
+		if known[id] {
+			continue
+		}
+		winding[id] = og.leftWinding(cycles[id][0])
+		known[id] = true
+		queue := []int{id}
+		for len(queue) != 0 {
+			c := queue[0]
+			queue = queue[1:]
+			for _, h := range cycles[c] {
+				other := cycle[h^1]
+				if known[other] {
+					continue
+				}
+				d := og.delta[h]
+				winding[other] = [2]int{winding[c][0] - d[0], winding[c][1] - d[1]}
+				known[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+	insideCycle := make([]bool, len(cycles))
+	for id, w := range winding {
+		insideCycle[id] = inside(w)
+	}
+
+	var boundary [][2][]float64
+	for h := range og.from {
+		if insideCycle[cycle[h]] && !insideCycle[cycle[h^1]] {
+			boundary = append(boundary, [2][]float64{og.vertices[og.from[h]], og.vertices[og.to(h)]})
+		}
+	}
+	return assembleRings(boundary, true)
+}
+
+// assembleRings joins directed edges that have the region to their left
+// into closed rings, and groups them into polygons. Where rings touch at a
+// vertex the first edge clockwise from the incoming one is followed, so
+// that rings never cross or touch themselves. With dropCollinear, vertices
+// on the straight line between their neighbours are removed.
+func assembleRings(edges [][2][]float64, dropCollinear bool) [][][][]float64 {
+	outgoing := make(map[[2]float64][]int)
+	for i, e := range edges {
+		key := [2]float64{e[0][0], e[0][1]}
+		outgoing[key] = append(outgoing[key], i)
+	}
+
+	used := make([]bool, len(edges))
+	var shells, holes [][][]float64
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var ring [][]float64
+		e := start
+		for {
+			used[e] = true
+			from, to := edges[e][0], edges[e][1]
+			ring = append(ring, []float64{from[0], from[1]})
+			back := math.Atan2(from[1]-to[1], from[0]-to[0])
+			next, best := -1, math.Inf(1)
+			for _, cand := range outgoing[[2]float64{to[0], to[1]}] {
+				if used[cand] && cand != start {
+					continue
+				}
+				w := edges[cand][1]
+				turn := math.Mod(back-math.Atan2(w[1]-to[1], w[0]-to[0])+4*math.Pi, 2*math.Pi)
+				if turn == 0 {
+					turn = 2 * math.Pi
+				}
+				if turn < best {
+					next, best = cand, turn
+				}
+			}
+			e = next
+			if e == start || e == -1 {
+				break
+			}
+		}
+		if dropCollinear {
+			ring = removeCollinear(ring)
+		}
+		if len(ring) < 3 {
+			continue
+		}
+		ring = append(ring, []float64{ring[0][0], ring[0][1]})
+		if ringArea(ring) > 0 {
+			shells = append(shells, ring)
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	polygons := make([][][][]float64, len(shells))
+	for i, shell := range shells {
+		polygons[i] = [][][]float64{shell}
+	}
+	for _, hole := range holes {
+		owner, ownerArea := -1, math.Inf(1)
+		for i, shell := range shells {
+			area := ringArea(shell)
+			if area < ownerArea && ringInsideRing(hole, shell) {
+				owner, ownerArea = i, area
+			}
+		}
+		if owner != -1 {
+			polygons[owner] = append(polygons[owner], hole)
+		}
+	}
+	return polygons
+}
+
+// removeCollinear drops vertices of an unclosed ring that lie on the
+// straight line between their neighbours
+func removeCollinear(ring [][]float64) [][]float64 {
+	out := make([][]float64, 0, len(ring))
+	for _, pos := range ring {
+		for len(out) >= 2 && cross(out[len(out)-2], out[len(out)-1], pos) == 0 {
+			out = out[:len(out)-1]
+		}
+		out = append(out, pos)
+	}
+	for len(out) >= 3 {
+		n := len(out)
+		if cross(out[n-2], out[n-1], out[0]) == 0 {
+			out = out[:n-1]
+		} else if cross(out[n-1], out[0], out[1]) == 0 {
+			out = out[1:]
+		} else {
+			break
+		}
+	}
+	return out
+}
+
+// ringInsideRing returns true if inner lies within outer, given that their
+// boundaries touch at no more than isolated vertices
+func ringInsideRing(inner, outer [][]float64) bool {
+	for i := 1; i < len(inner); i++ {
+		mid := []float64{0.5 * (inner[i-1][0] + inner[i][0]), 0.5 * (inner[i-1][1] + inner[i][1])}
+		switch pointInRing(mid, outer) {
+		case 1:
+			return true
+		case -1:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+/* planar geometry primitives shared by the higher level operations */
+package geojson
+
+// cross returns the z-component of (p1 - p0) x (q - p0), which is positive
+// when q is left of the directed line p0->p1
+func cross(p0, p1, q []float64) float64 {
+	return (p1[0]-p0[0])*(q[1]-p0[1]) - (q[0]-p0[0])*(p1[1]-p0[1])
+}
+
+// onSegment returns true if q lies on the closed segment p0-p1
+func onSegment(q, p0, p1 []float64) bool {
+	if cross(p0, p1, q) != 0 {
+		return false
+	}
+	return q[0] >= minf(p0[0], p1[0]) && q[0] <= maxf(p0[0], p1[0]) &&
+		q[1] >= minf(p0[1], p1[1]) && q[1] <= maxf(p0[1], p1[1])
+}
+
+// pointInRing returns 1 if pt is inside ring, 0 if it is on the boundary and
+// -1 if it is outside. The ring may or may not be explicitly closed.
+func pointInRing(pt []float64, ring [][]float64) int {
+	n := len(ring)
+	if n == 0 {
+		return -1
+	}
+	inside := false
+	for i, j := 0, n-1; i != n; j, i = i, i+1 {
+		a, b := ring[j], ring[i]
+		if onSegment(pt, a, b) {
+			return 0
+		}
+		if (a[1] > pt[1]) != (b[1] > pt[1]) {
+			x := a[0] + (pt[1]-a[1])*(b[0]-a[0])/(b[1]-a[1])
+			if pt[0] < x {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return 1
+	}
+	return -1
+}
+
+// pointInPolygon returns 1 if pt is in the interior of the polygon described
+// by rings (shell followed by holes), 0 if it is on the boundary and -1 if it
+// is outside
+func pointInPolygon(pt []float64, rings [][][]float64) int {
+	if len(rings) == 0 {
+		return -1
+	}
+	loc := pointInRing(pt, rings[0])
+	if loc != 1 {
+		return loc
+	}
+	for _, hole := range rings[1:] {
+		switch pointInRing(pt, hole) {
+		case 1:
+			return -1
+		case 0:
+			return 0
+		}
+	}
+	return 1
+}
+
+// segmentsIntersect returns true if the closed segments a0-a1 and b0-b1 share
+// at least one point
+func segmentsIntersect(a0, a1, b0, b1 []float64) bool {
+	d1 := cross(b0, b1, a0)
+	d2 := cross(b0, b1, a1)
+	d3 := cross(a0, a1, b0)
+	d4 := cross(a0, a1, b1)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(a0, b0, b1)) ||
+		(d2 == 0 && onSegment(a1, b0, b1)) ||
+		(d3 == 0 && onSegment(b0, a0, a1)) ||
+		(d4 == 0 && onSegment(b1, a0, a1))
+}
+
+// segmentIntersectsRect returns true if the segment p0-p1 touches the
+// rectangle [xmin, ymin, xmax, ymax]
+func segmentIntersectsRect(p0, p1 []float64, rect [4]float64) bool {
+	_, _, ok := liangBarsky(p0, p1, rect)
+	return ok
+}
+
+// liangBarsky returns the range of the parameter t in [0, 1] for which
+// p0 + t*(p1 - p0) lies within the rectangle [xmin, ymin, xmax, ymax], or
+// false if the segment misses it. Uses Liang-Barsky parametric clipping.
+func liangBarsky(p0, p1 []float64, rect [4]float64) (float64, float64, bool) {
+	t0, t1 := 0.0, 1.0
+	dx := p1[0] - p0[0]
+	dy := p1[1] - p0[1]
+	p := [4]float64{-dx, dx, -dy, dy}
+	q := [4]float64{p0[0] - rect[0], rect[2] - p0[0], p0[1] - rect[1], rect[3] - p0[1]}
+	for i := 0; i != 4; i++ {
+		if p[i] == 0 {
+			if q[i] < 0 {
+				return 0, 0, false
+			}
+			continue
+		}
+		r := q[i] / p[i]
+		if p[i] < 0 {
+			if r > t1 {
+				return 0, 0, false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return 0, 0, false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	return t0, t1, true
+}
+
+func minf(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
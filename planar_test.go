@@ -0,0 +1,65 @@
+package geojson
+
+import "testing"
+
+func TestPointInRing(t *testing.T) {
+	ring := [][]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}
+	if pointInRing([]float64{2, 2}, ring) != 1 {
+		t.Fail()
+	}
+	if pointInRing([]float64{4, 2}, ring) != 0 {
+		t.Fail()
+	}
+	if pointInRing([]float64{0, 0}, ring) != 0 {
+		t.Fail()
+	}
+	if pointInRing([]float64{5, 2}, ring) != -1 {
+		t.Fail()
+	}
+}
+
+func TestPointInPolygonHole(t *testing.T) {
+	rings := [][][]float64{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+	}
+	if pointInPolygon([]float64{2, 2}, rings) != -1 {
+		t.Fail()
+	}
+	if pointInPolygon([]float64{1, 2}, rings) != 0 {
+		t.Fail()
+	}
+	if pointInPolygon([]float64{0.5, 2}, rings) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	if !segmentsIntersect([]float64{0, 0}, []float64{2, 2}, []float64{0, 2}, []float64{2, 0}) {
+		t.Fail()
+	}
+	// touching at an endpoint
+	if !segmentsIntersect([]float64{0, 0}, []float64{1, 1}, []float64{1, 1}, []float64{2, 0}) {
+		t.Fail()
+	}
+	// collinear and disjoint
+	if segmentsIntersect([]float64{0, 0}, []float64{1, 0}, []float64{2, 0}, []float64{3, 0}) {
+		t.Fail()
+	}
+}
+
+func TestSegmentIntersectsRect(t *testing.T) {
+	rect := [4]float64{0, 0, 1, 1}
+	if !segmentIntersectsRect([]float64{-1, 0.5}, []float64{2, 0.5}, rect) {
+		t.Fail()
+	}
+	if segmentIntersectsRect([]float64{-1, 1.5}, []float64{2, 1.5}, rect) {
+		t.Fail()
+	}
+	if segmentIntersectsRect([]float64{1.5, -1}, []float64{3, 0.5}, rect) {
+		t.Fail()
+	}
+	if !segmentIntersectsRect([]float64{0.2, 0.2}, []float64{0.3, 0.3}, rect) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,152 @@
+/* pole of inaccessibility search for placing labels inside polygons */
+package geojson
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+)
+
+// labelCell is a square search cell used by the pole of inaccessibility
+// search
+type labelCell struct {
+	x, y, h float64 // centre and half-size
+	d       float64 // distance from the centre to the polygon outline
+	max     float64 // upper bound of the distance within the cell
+}
+
+func newLabelCell(x, y, h float64, polygons [][][][]float64) *labelCell {
+	d := signedOutlineDistance([]float64{x, y}, polygons)
+	return &labelCell{x, y, h, d, d + h*math.Sqrt2}
+}
+
+type labelQueue []*labelCell
+
+func (q labelQueue) Len() int            { return len(q) }
+func (q labelQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q labelQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *labelQueue) Push(x interface{}) { *q = append(*q, x.(*labelCell)) }
+func (q *labelQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// segmentDistance returns the distance from pos to the segment a-b
+func segmentDistance(pos, a, b []float64) float64 {
+	x, y := a[0], a[1]
+	dx, dy := b[0]-x, b[1]-y
+	if dx != 0 || dy != 0 {
+		t := ((pos[0]-x)*dx + (pos[1]-y)*dy) / (dx*dx + dy*dy)
+		if t > 1 {
+			x, y = b[0], b[1]
+		} else if t > 0 {
+			x += dx * t
+			y += dy * t
+		}
+	}
+	return math.Hypot(pos[0]-x, pos[1]-y)
+}
+
+// signedOutlineDistance returns the distance from pos to the nearest ring of
+// any of the polygons, positive inside and negative outside
+func signedOutlineDistance(pos []float64, polygons [][][][]float64) float64 {
+	inside := false
+	dmin := math.Inf(1)
+	for _, rings := range polygons {
+		if pointInPolygon(pos, rings) > 0 {
+			inside = true
+		}
+		for _, ring := range rings {
+			n := len(ring)
+			for i, j := 0, n-1; i < n; j, i = i, i+1 {
+				dmin = math.Min(dmin, segmentDistance(pos, ring[j], ring[i]))
+			}
+		}
+	}
+	if inside {
+		return dmin
+	}
+	return -dmin
+}
+
+// poleOfInaccessibility finds the interior point furthest from the outline of
+// a set of polygons, to within precision, using the quadtree search of
+// Agafonkin (2016) "polylabel"
+func poleOfInaccessibility(polygons [][][][]float64, precision float64) (*Point, error) {
+	if len(polygons) == 0 || len(polygons[0]) == 0 || len(polygons[0][0]) == 0 {
+		return nil, errors.New("empty polygon")
+	}
+	if precision <= 0 {
+		return nil, errors.New("precision must be positive")
+	}
+	xmin, ymin := math.Inf(1), math.Inf(1)
+	xmax, ymax := math.Inf(-1), math.Inf(-1)
+	for _, rings := range polygons {
+		for _, pos := range rings[0] {
+			xmin = math.Min(xmin, pos[0])
+			ymin = math.Min(ymin, pos[1])
+			xmax = math.Max(xmax, pos[0])
+			ymax = math.Max(ymax, pos[1])
+		}
+	}
+	size := math.Min(xmax-xmin, ymax-ymin)
+	if size == 0 {
+		return &Point{Coordinates: []float64{xmin, ymin}}, nil
+	}
+
+	// seed with the centroid and a grid covering the extent
+	var best *labelCell
+	ctr := new(centroidSum)
+	for _, rings := range polygons {
+		ctr.addPolygon(rings)
+	}
+	if p, err := ctr.point(); err == nil {
+		best = newLabelCell(p.Coordinates[0], p.Coordinates[1], 0, polygons)
+	}
+	// the point on surface is always inside, so the result is too
+	if p, _ := interiorPointPolygon(polygons[0]); p != nil {
+		c := newLabelCell(p[0], p[1], 0, polygons)
+		if best == nil || c.d > best.d {
+			best = c
+		}
+	}
+
+	q := &labelQueue{}
+	h := size / 2
+	for x := xmin; x < xmax; x += size {
+		for y := ymin; y < ymax; y += size {
+			heap.Push(q, newLabelCell(x+h, y+h, h, polygons))
+		}
+	}
+
+	for q.Len() != 0 {
+		c := heap.Pop(q).(*labelCell)
+		if best == nil || c.d > best.d {
+			best = c
+		}
+		if c.max-best.d <= precision {
+			continue
+		}
+		h = c.h / 2
+		heap.Push(q, newLabelCell(c.x-h, c.y-h, h, polygons))
+		heap.Push(q, newLabelCell(c.x+h, c.y-h, h, polygons))
+		heap.Push(q, newLabelCell(c.x-h, c.y+h, h, polygons))
+		heap.Push(q, newLabelCell(c.x+h, c.y+h, h, polygons))
+	}
+	return &Point{Coordinates: []float64{best.x, best.y}}, nil
+}
+
+// PoleOfInaccessibility returns the interior point of the Polygon furthest
+// from its outline, found to within precision. It suits label placement
+// better than the centroid, which can fall outside concave shapes.
+func (g *Polygon) PoleOfInaccessibility(precision float64) (*Point, error) {
+	return poleOfInaccessibility([][][][]float64{g.Coordinates}, precision)
+}
+
+// PoleOfInaccessibility returns the interior point of the MultiPolygon
+// furthest from the outline of any of its polygons, found to within precision
+func (g *MultiPolygon) PoleOfInaccessibility(precision float64) (*Point, error) {
+	return poleOfInaccessibility(g.Coordinates, precision)
+}
@@ -0,0 +1,179 @@
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// lambertConformalConic follows Snyder (1987), Map Projections: A Working
+// Manual, pp. 107-109
+type lambertConformalConic struct {
+	e, lon0, x0, y0 float64
+	// n is the cone constant, aF the scaled radius factor and rho0 the
+	// radius at the latitude of origin
+	n, aF, rho0 float64
+}
+
+// lccT is Snyder's t, equation 15-9
+func lccT(phi, e float64) float64 {
+	sin := e * math.Sin(phi)
+	return math.Tan(math.Pi/4-phi/2) / math.Pow((1-sin)/(1+sin), e/2)
+}
+
+// lccM is Snyder's m, equation 14-15
+func lccM(phi, e float64) float64 {
+	sin := e * math.Sin(phi)
+	return math.Cos(phi) / math.Sqrt(1-sin*sin)
+}
+
+// NewLambertConformalConic returns a Lambert Conformal Conic projection
+// with two standard parallels, which may be equal for the one parallel
+// form, a central meridian and latitude of origin in degrees, and a false
+// easting and northing in metres
+func NewLambertConformalConic(ell Ellipsoid, lat1, lat2, lon0, lat0, falseEasting, falseNorthing float64) Projection {
+	return newLCC(ell, lat1, lat2, lon0, lat0, 1, falseEasting, falseNorthing)
+}
+
+// NewLambertConformalConic1SP returns a Lambert Conformal Conic projection
+// with one standard parallel at the latitude of origin, where the scale is
+// k0
+func NewLambertConformalConic1SP(ell Ellipsoid, lon0, lat0, k0, falseEasting, falseNorthing float64) Projection {
+	return newLCC(ell, lat0, lat0, lon0, lat0, k0, falseEasting, falseNorthing)
+}
+
+func newLCC(ell Ellipsoid, lat1, lat2, lon0, lat0, k0, x0, y0 float64) Projection {
+	e := ell.e()
+	phi1, phi2, phi0 := lat1*radiansPerDeg, lat2*radiansPerDeg, lat0*radiansPerDeg
+	m1, t1 := lccM(phi1, e), lccT(phi1, e)
+	n := math.Sin(phi1)
+	if phi1 != phi2 {
+		n = (math.Log(m1) - math.Log(lccM(phi2, e))) / (math.Log(t1) - math.Log(lccT(phi2, e)))
+	}
+	aF := ell.A * k0 * m1 / (n * math.Pow(t1, n))
+	return &lambertConformalConic{
+		e: e, lon0: lon0, x0: x0, y0: y0,
+		n: n, aF: aF, rho0: aF * math.Pow(lccT(phi0, e), n),
+	}
+}
+
+func (p *lambertConformalConic) Forward(lon, lat float64) (x, y float64, err error) {
+	if math.Abs(lat) > 90 {
+		return 0, 0, fmt.Errorf("latitude %v out of range", lat)
+	}
+	phi := lat * radiansPerDeg
+	if math.Abs(lat) == 90 && (lat > 0) != (p.n > 0) {
+		return 0, 0, errors.New("the pole opposite the cone's apex cannot be projected")
+	}
+	rho := 0.0
+	if math.Abs(lat) != 90 {
+		rho = p.aF * math.Pow(lccT(phi, p.e), p.n)
+	}
+	theta := p.n * math.Remainder(lon-p.lon0, 360) * radiansPerDeg
+	sin, cos := math.Sincos(theta)
+	return p.x0 + rho*sin, p.y0 + p.rho0 - rho*cos, nil
+}
+
+func (p *lambertConformalConic) Inverse(x, y float64) (lon, lat float64, err error) {
+	dx, dy := x-p.x0, p.rho0-(y-p.y0)
+	if p.n < 0 {
+		dx, dy = -dx, -dy
+	}
+	rho := math.Copysign(math.Hypot(dx, dy), p.n)
+	theta := math.Atan2(dx, dy)
+	if rho == 0 {
+		return p.lon0, math.Copysign(90, p.n), nil
+	}
+	t := math.Pow(rho/p.aF, 1/p.n)
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 15; i++ {
+		sin := p.e * math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-sin)/(1+sin), p.e/2))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return math.Remainder(theta/p.n*degreesPerRad+p.lon0, 360), phi * degreesPerRad, nil
+}
+
+// albersEqualArea follows Snyder (1987), pp. 101-102
+type albersEqualArea struct {
+	e, a, lon0, x0, y0 float64
+	// n is the cone constant, c Snyder's C and rho0 the radius at the
+	// latitude of origin
+	n, c, rho0 float64
+}
+
+// albersQ is Snyder's q, equation 3-12
+func albersQ(phi, e float64) float64 {
+	sin := math.Sin(phi)
+	if e == 0 {
+		return 2 * sin
+	}
+	es := e * sin
+	return (1 - e*e) * (sin/(1-es*es) - math.Log((1-es)/(1+es))/(2*e))
+}
+
+// NewAlbersEqualArea returns an Albers Equal Area Conic projection with two
+// standard parallels, a central meridian and latitude of origin in degrees,
+// and a false easting and northing in metres
+func NewAlbersEqualArea(ell Ellipsoid, lat1, lat2, lon0, lat0, falseEasting, falseNorthing float64) Projection {
+	e := ell.e()
+	phi1, phi2 := lat1*radiansPerDeg, lat2*radiansPerDeg
+	m1, q1 := lccM(phi1, e), albersQ(phi1, e)
+	n := math.Sin(phi1)
+	if phi1 != phi2 {
+		m2 := lccM(phi2, e)
+		n = (m1*m1 - m2*m2) / (albersQ(phi2, e) - q1)
+	}
+	c := m1*m1 + n*q1
+	return &albersEqualArea{
+		e: e, a: ell.A, lon0: lon0, x0: falseEasting, y0: falseNorthing,
+		n: n, c: c, rho0: ell.A * math.Sqrt(c-n*albersQ(lat0*radiansPerDeg, e)) / n,
+	}
+}
+
+func (p *albersEqualArea) Forward(lon, lat float64) (x, y float64, err error) {
+	if math.Abs(lat) > 90 {
+		return 0, 0, fmt.Errorf("latitude %v out of range", lat)
+	}
+	rho := p.a * math.Sqrt(math.Max(0, p.c-p.n*albersQ(lat*radiansPerDeg, p.e))) / p.n
+	theta := p.n * math.Remainder(lon-p.lon0, 360) * radiansPerDeg
+	sin, cos := math.Sincos(theta)
+	return p.x0 + rho*sin, p.y0 + p.rho0 - rho*cos, nil
+}
+
+func (p *albersEqualArea) Inverse(x, y float64) (lon, lat float64, err error) {
+	dx, dy := x-p.x0, p.rho0-(y-p.y0)
+	if p.n < 0 {
+		dx, dy = -dx, -dy
+	}
+	rho := math.Hypot(dx, dy)
+	theta := math.Atan2(dx, dy)
+	q := (p.c - rho*rho*p.n*p.n/(p.a*p.a)) / p.n
+	qp := albersQ(math.Pi/2, p.e)
+	if math.Abs(q) > qp*(1+1e-12) {
+		return 0, 0, errors.New("position outside the projection's domain")
+	}
+
+	var phi float64
+	if math.Abs(q) >= qp*(1-1e-12) {
+		phi = math.Copysign(math.Pi/2, q)
+	} else {
+		// Newton's method on q, from Snyder's equation 3-16
+		phi = math.Asin(q / 2)
+		e2 := p.e * p.e
+		for i := 0; i < 15; i++ {
+			sin, cos := math.Sincos(phi)
+			w := 1 - e2*sin*sin
+			dphi := (q - albersQ(phi, p.e)) * w * w / (2 * (1 - e2) * cos)
+			phi += dphi
+			if math.Abs(dphi) < 1e-14 {
+				break
+			}
+		}
+	}
+	return math.Remainder(theta/p.n*degreesPerRad+p.lon0, 360), phi * degreesPerRad, nil
+}
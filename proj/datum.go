@@ -0,0 +1,152 @@
+package proj
+
+import (
+	"errors"
+	"math"
+)
+
+// DatumShift converts longitude and latitude in degrees from a datum to
+// WGS84, and back
+type DatumShift interface {
+	Forward(lon, lat float64) (float64, float64, error)
+	Inverse(lon, lat float64) (float64, float64, error)
+}
+
+// Datum is a geodetic datum, the ellipsoid and its placement that
+// positions are referenced to
+type Datum struct {
+	Name      string
+	Ellipsoid Ellipsoid
+	// ToWGS84 converts positions on the datum to WGS84, and is nil for WGS84
+	// and datums treated as coincident with it
+	ToWGS84 DatumShift
+}
+
+// WithShift returns a copy of the datum converted to WGS84 by shift, such
+// as a grid loaded with LoadNTv2 in place of a Helmert transformation
+func (d *Datum) WithShift(shift DatumShift) *Datum {
+	out := *d
+	out.ToWGS84 = shift
+	return &out
+}
+
+var (
+	WGS84Datum = &Datum{Name: "WGS 84", Ellipsoid: WGS84}
+	// NAD83Datum and ETRS89Datum are treated as coincident with WGS84, from
+	// which they differ by a metre or two
+	NAD83Datum  = &Datum{Name: "NAD83", Ellipsoid: GRS80}
+	ETRS89Datum = &Datum{Name: "ETRS89", Ellipsoid: GRS80}
+	// NAD27Datum is converted with the EPSG:1173 transformation for the
+	// conterminous United States, accurate to about 10 m. A NADCON or NTv2
+	// grid is more accurate where one is available.
+	NAD27Datum = &Datum{Name: "NAD27", Ellipsoid: Clarke1866,
+		ToWGS84: NewHelmertShift(Clarke1866, Helmert{TX: -8, TY: 160, TZ: 176}, WGS84)}
+	// OSGB36Datum is converted with the EPSG:1314 transformation, accurate
+	// to about 2 m
+	OSGB36Datum = &Datum{Name: "OSGB 1936", Ellipsoid: Airy1830,
+		ToWGS84: NewHelmertShift(Airy1830, Helmert{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489}, WGS84)}
+)
+
+// ToGeocentric returns the earth-centred, earth-fixed cartesian coordinates
+// in metres of a longitude and latitude in degrees and a height in metres
+// above the ellipsoid
+func (ell Ellipsoid) ToGeocentric(lon, lat, h float64) (x, y, z float64) {
+	e2 := ell.F * (2 - ell.F)
+	sinPhi, cosPhi := math.Sincos(lat * radiansPerDeg)
+	sinLambda, cosLambda := math.Sincos(lon * radiansPerDeg)
+	n := ell.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	return (n + h) * cosPhi * cosLambda, (n + h) * cosPhi * sinLambda, (n*(1-e2) + h) * sinPhi
+}
+
+// FromGeocentric returns the longitude and latitude in degrees and the
+// height above the ellipsoid in metres of earth-centred, earth-fixed
+// cartesian coordinates
+func (ell Ellipsoid) FromGeocentric(x, y, z float64) (lon, lat, h float64) {
+	e2 := ell.F * (2 - ell.F)
+	p := math.Hypot(x, y)
+	phi := math.Atan2(z, p*(1-e2))
+	for i := 0; i < 10; i++ {
+		sinPhi, cosPhi := math.Sincos(phi)
+		n := ell.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+		h = p*cosPhi + z*sinPhi - ell.A*ell.A/n
+		next := math.Atan2(z, p*(1-e2*n/(n+h)))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	sinPhi, cosPhi := math.Sincos(phi)
+	n := ell.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	h = p*cosPhi + z*sinPhi - ell.A*ell.A/n
+	return math.Atan2(y, x) * degreesPerRad, phi * degreesPerRad, h
+}
+
+// Helmert is a seven parameter transformation of geocentric coordinates,
+// with translations in metres, rotations in arc-seconds and a scale
+// difference in parts per million. Rotations follow the position vector
+// convention (EPSG method 1033), as in the PROJ towgs84 parameter; negate
+// them for parameters published in the coordinate frame convention.
+type Helmert struct {
+	TX, TY, TZ float64
+	RX, RY, RZ float64
+	S          float64
+}
+
+func (h Helmert) rotations() (rx, ry, rz, m float64) {
+	const arcSecond = math.Pi / (180 * 3600)
+	return h.RX * arcSecond, h.RY * arcSecond, h.RZ * arcSecond, 1 + h.S*1e-6
+}
+
+// Apply transforms geocentric coordinates
+func (h Helmert) Apply(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz, m := h.rotations()
+	return h.TX + m*(x-rz*y+ry*z),
+		h.TY + m*(rz*x+y-rx*z),
+		h.TZ + m*(-ry*x+rx*y+z)
+}
+
+// ApplyInverse undoes Apply exactly
+func (h Helmert) ApplyInverse(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz, m := h.rotations()
+	// solve R (x0, y0, z0) = (x - TX, y - TY, z - TZ) / m by Cramer's rule
+	bx, by, bz := (x-h.TX)/m, (y-h.TY)/m, (z-h.TZ)/m
+	det := func(a, b, c [3]float64) float64 {
+		return a[0]*(b[1]*c[2]-b[2]*c[1]) - a[1]*(b[0]*c[2]-b[2]*c[0]) + a[2]*(b[0]*c[1]-b[1]*c[0])
+	}
+	r0, r1, r2 := [3]float64{1, -rz, ry}, [3]float64{rz, 1, -rx}, [3]float64{-ry, rx, 1}
+	d := det(r0, r1, r2)
+	b := [3]float64{bx, by, bz}
+	return det([3]float64{b[0], r0[1], r0[2]}, [3]float64{b[1], r1[1], r1[2]}, [3]float64{b[2], r2[1], r2[2]}) / d,
+		det([3]float64{r0[0], b[0], r0[2]}, [3]float64{r1[0], b[1], r1[2]}, [3]float64{r2[0], b[2], r2[2]}) / d,
+		det([3]float64{r0[0], r0[1], b[0]}, [3]float64{r1[0], r1[1], b[1]}, [3]float64{r2[0], r2[1], b[2]}) / d
+}
+
+// helmertShift converts between datums through geocentric coordinates
+type helmertShift struct {
+	from, to Ellipsoid
+	h        Helmert
+}
+
+// NewHelmertShift returns a DatumShift that applies a Helmert
+// transformation from a datum on one ellipsoid to another. Positions are
+// taken to lie on the ellipsoid, so that heights are not changed.
+func NewHelmertShift(from Ellipsoid, h Helmert, to Ellipsoid) DatumShift {
+	return helmertShift{from, to, h}
+}
+
+func (s helmertShift) Forward(lon, lat float64) (float64, float64, error) {
+	if math.Abs(lat) > 90 {
+		return 0, 0, errors.New("latitude out of range")
+	}
+	lon, lat, _ = s.to.FromGeocentric(s.h.Apply(s.from.ToGeocentric(lon, lat, 0)))
+	return lon, lat, nil
+}
+
+func (s helmertShift) Inverse(lon, lat float64) (float64, float64, error) {
+	if math.Abs(lat) > 90 {
+		return 0, 0, errors.New("latitude out of range")
+	}
+	lon, lat, _ = s.from.FromGeocentric(s.h.ApplyInverse(s.to.ToGeocentric(lon, lat, 0)))
+	return lon, lat, nil
+}
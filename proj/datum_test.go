@@ -0,0 +1,257 @@
+package proj
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+func TestGeocentric(t *testing.T) {
+	x, y, z := WGS84.ToGeocentric(0, 0, 0)
+	if x != WGS84.A || y != 0 || z != 0 {
+		fmt.Println("recieved    ", x, y, z)
+		t.Fail()
+	}
+	for _, pos := range [][3]float64{{-79.98, 40.45, 300}, {170, -89.9, -50}, {10, 90, 0}} {
+		lon, lat, h := WGS84.FromGeocentric(WGS84.ToGeocentric(pos[0], pos[1], pos[2]))
+		if math.Abs(lat-pos[1]) > 1e-11 || math.Abs(h-pos[2]) > 1e-6 ||
+			(math.Abs(pos[1]) != 90 && math.Abs(lon-pos[0]) > 1e-11) {
+			fmt.Println("recieved    ", lon, lat, h)
+			fmt.Println("but expected", pos)
+			t.Fail()
+		}
+	}
+}
+
+func TestHelmert(t *testing.T) {
+	h := Helmert{446.448, -125.157, 542.06, 0.15, 0.247, 0.842, -20.489}
+	x, y, z := h.ApplyInverse(h.Apply(3874938.8, 116218.6, 5047168.2))
+	if math.Abs(x-3874938.8) > 1e-6 || math.Abs(y-116218.6) > 1e-6 || math.Abs(z-5047168.2) > 1e-6 {
+		fmt.Println("recieved    ", x, y, z)
+		t.Fail()
+	}
+
+	// the Ordnance Survey's worked example at Caister water tower
+	bng, _ := Lookup(27700)
+	lon, lat, err := bng.ToGeographic(651409.903, 313177.270)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expLon, expLat := 1+43./60+4.5177/3600, 52+39./60+27.2531/3600
+	if math.Abs(lon-expLon) > 1e-8 || math.Abs(lat-expLat) > 1e-8 {
+		fmt.Println("recieved    ", lon, lat)
+		fmt.Println("but expected", expLon, expLat)
+		t.Fail()
+	}
+	wgs84, _ := Lookup(4326)
+	pos, err := Transform([]float64{651409.903, 313177.270}, bng, wgs84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// WGS84 is a few arc-seconds west and north of OSGB36 in East Anglia
+	dlon, dlat := (pos[0]-lon)*3600, (pos[1]-lat)*3600
+	if dlon > -4 || dlon < -10 || dlat < 0 || dlat > 4 {
+		fmt.Println("recieved    ", dlon, dlat)
+		t.Fail()
+	}
+	// heights are taken to be zero in both directions, so the round trip is
+	// not exact
+	back, err := Transform(pos, wgs84, bng)
+	if err != nil || math.Abs(back[0]-651409.903) > 0.01 || math.Abs(back[1]-313177.270) > 0.01 {
+		fmt.Println("recieved    ", back, err)
+		t.Fail()
+	}
+}
+
+// ntv2Subgrid describes a subgrid with a constant shift in arc-seconds, for
+// writing test files
+type ntv2Subgrid struct {
+	name, parent                 string
+	south, north, east, west     float64
+	latInc, lonInc               float64
+	latShift, lonShiftPosWestSec float32
+}
+
+func writeNTv2(order binary.ByteOrder, grids []ntv2Subgrid) []byte {
+	var buf bytes.Buffer
+	key := func(k string) {
+		buf.WriteString(fmt.Sprintf("%-8s", k))
+	}
+	putInt := func(k string, v int) {
+		key(k)
+		binary.Write(&buf, order, int32(v))
+		buf.Write(make([]byte, 4))
+	}
+	putString := func(k, v string) {
+		key(k)
+		buf.WriteString(fmt.Sprintf("%-8s", v))
+	}
+	putFloat := func(k string, v float64) {
+		key(k)
+		binary.Write(&buf, order, v)
+	}
+	putInt("NUM_OREC", 11)
+	putInt("NUM_SREC", 11)
+	putInt("NUM_FILE", len(grids))
+	putString("GS_TYPE", "SECONDS")
+	putString("VERSION", "NTv2.0")
+	putString("SYSTEM_F", "NAD27")
+	putString("SYSTEM_T", "NAD83")
+	putFloat("MAJOR_F", Clarke1866.A)
+	putFloat("MINOR_F", Clarke1866.A*(1-Clarke1866.F))
+	putFloat("MAJOR_T", GRS80.A)
+	putFloat("MINOR_T", GRS80.A*(1-GRS80.F))
+	for _, g := range grids {
+		putString("SUB_NAME", g.name)
+		putString("PARENT", g.parent)
+		putString("CREATED", "")
+		putString("UPDATED", "")
+		// bounds in arc-seconds with longitudes positive west
+		putFloat("S_LAT", g.south*3600)
+		putFloat("N_LAT", g.north*3600)
+		putFloat("E_LONG", -g.east*3600)
+		putFloat("W_LONG", -g.west*3600)
+		putFloat("LAT_INC", g.latInc*3600)
+		putFloat("LONG_INC", g.lonInc*3600)
+		rows := int(math.Round((g.north-g.south)/g.latInc)) + 1
+		cols := int(math.Round((g.east-g.west)/g.lonInc)) + 1
+		putInt("GS_COUNT", rows*cols)
+		for i := 0; i < rows*cols; i++ {
+			binary.Write(&buf, order, []float32{g.latShift, g.lonShiftPosWestSec, 0, 0})
+		}
+	}
+	key("END")
+	buf.Write(make([]byte, 8))
+	return buf.Bytes()
+}
+
+var testGrids = []ntv2Subgrid{
+	{"PARENT", "NONE", 40, 42, -70, -72, 0.5, 0.5, 1.8, 3.6},
+	{"CHILD", "PARENT", 41, 41.5, -70.5, -71, 0.25, 0.25, 3.6, -7.2},
+}
+
+func TestNTv2(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		grid, err := ReadNTv2(bytes.NewReader(writeNTv2(order, testGrids)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if grid.From != "NAD27" || grid.To != "NAD83" {
+			fmt.Println("recieved    ", grid.From, grid.To)
+			t.Fail()
+		}
+		// the parent shifts 1.8" north and 3.6" west
+		lon, lat, err := grid.Forward(-71.75, 40.1)
+		if err != nil || math.Abs(lon-(-71.75-0.001)) > 1e-9 || math.Abs(lat-(40.1+0.0005)) > 1e-9 {
+			fmt.Println("recieved    ", lon, lat, err)
+			t.Fail()
+		}
+		// the child shifts 3.6" north and 7.2" east
+		lon, lat, err = grid.Forward(-70.8, 41.2)
+		if err != nil || math.Abs(lon-(-70.8+0.002)) > 1e-9 || math.Abs(lat-(41.2+0.001)) > 1e-9 {
+			fmt.Println("recieved    ", lon, lat, err)
+			t.Fail()
+		}
+		lon, lat, err = grid.Inverse(lon, lat)
+		if err != nil || math.Abs(lon+70.8) > 1e-9 || math.Abs(lat-41.2) > 1e-9 {
+			fmt.Println("recieved    ", lon, lat, err)
+			t.Fail()
+		}
+		if _, _, err = grid.Forward(-80, 41); err == nil {
+			t.Fail()
+		}
+	}
+
+	if _, err := ReadNTv2(bytes.NewReader([]byte("NUM_OREC"))); err == nil {
+		t.Fail()
+	}
+}
+
+func TestReprojectToGrid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.gsb")
+	if err := os.WriteFile(path, writeNTv2(binary.LittleEndian, testGrids), 0644); err != nil {
+		t.Fatal(err)
+	}
+	grid, err := LoadNTv2(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// use the grid for NAD27 in place of the Helmert transformation
+	nad27, _ := Lookup(4267)
+	defer Register(nad27)
+	if err = Register(nad27.WithDatum(NAD27Datum.WithShift(grid))); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := &geojson.Geo{Type: "FeatureCollection", FeatureCollection: &geojson.FeatureCollection{
+		CRSReferencable: geojson.CRSReferencable{CRS: geojson.NewNamedCRS("EPSG:4267")},
+		Features: []geojson.Feature{
+			{ID: "nad27", Geometry: geojson.Geo{Type: "Point", Point: &geojson.Point{Coordinates: []float64{-71.75, 40.1}}}},
+			{ID: "latlon", CRSReferencable: geojson.CRSReferencable{CRS: geojson.NewNamedCRS("urn:ogc:def:crs:EPSG::4326")},
+				Geometry: geojson.Geo{Type: "Point", Point: &geojson.Point{Coordinates: []float64{40.1, -71.75}}}},
+		},
+	}}
+	wgs84, _ := Lookup(4326)
+	out, err := ReprojectTo(fc, wgs84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := out.FeatureCollection.Features[0].Geometry.Point.Coordinates
+	b := out.FeatureCollection.Features[1].Geometry.Point.Coordinates
+	if math.Abs(a[0]-(-71.751)) > 1e-9 || math.Abs(a[1]-40.1005) > 1e-9 || fmt.Sprint(b) != "[-71.75 40.1]" {
+		fmt.Println("recieved    ", a, b)
+		t.Fail()
+	}
+	if out.FeatureCollection.CRS.Properties["name"] != "urn:ogc:def:crs:OGC:1.3:CRS84" ||
+		out.FeatureCollection.Features[1].CRS != nil {
+		fmt.Println("recieved    ", out.FeatureCollection.CRS, out.FeatureCollection.Features[1].CRS)
+		t.Fail()
+	}
+
+	// positions outside the grid cannot be converted
+	fc.FeatureCollection.Features[0].Geometry.Point.Coordinates = []float64{-100, 40}
+	if _, err = ReprojectTo(fc, wgs84); err == nil {
+		t.Fail()
+	}
+	unknown := &geojson.Geo{Type: "Point", Point: &geojson.Point{
+		CRSReferencable: geojson.CRSReferencable{CRS: geojson.NewNamedCRS("EPSG:9999999")},
+		Coordinates:     []float64{0, 0},
+	}}
+	if _, err = ReprojectTo(unknown, wgs84); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCRSEllipsoid(t *testing.T) {
+	// registered systems take the ellipsoid of their datum
+	for code, ell := range map[int]Ellipsoid{4326: WGS84, 3857: WGS84, 4269: GRS80, 26715: Clarke1866, 27700: Airy1830} {
+		crs, _ := Lookup(code)
+		if crs.Ellipsoid != ell {
+			fmt.Println("recieved    ", crs.Ellipsoid, "for", crs)
+			fmt.Println("but expected", ell)
+			t.Fail()
+		}
+	}
+	wgs84, _ := Lookup(4326)
+	if wgs84.WithDatum(OSGB36Datum).Ellipsoid != Airy1830 {
+		t.Fail()
+	}
+
+	// a system without a datum is coincident with WGS84 on its ellipsoid
+	sphere := &CRS{Code: 990001, Name: "sphere", Ellipsoid: Sphere}
+	if err := Register(sphere); err != nil || sphere.Datum != nil || sphere.Ellipsoid != Sphere {
+		t.Fail()
+	}
+	if err := Register(&CRS{Code: 990002, Name: "mismatched", Ellipsoid: Sphere, Datum: NAD27Datum}); err == nil {
+		t.Fail()
+	}
+	registryMu.Lock()
+	delete(registry, 990001)
+	registryMu.Unlock()
+}
@@ -0,0 +1,207 @@
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dmsAngle is a latitude or longitude read from text, as up to three
+// numbers of degrees, minutes and seconds with an optional hemisphere
+type dmsAngle struct {
+	parts      []string
+	hemisphere rune
+}
+
+func (a *dmsAngle) empty() bool {
+	return len(a.parts) == 0 && a.hemisphere == 0
+}
+
+// value returns the angle in degrees, negative to the south and west
+func (a *dmsAngle) value() (float64, error) {
+	if len(a.parts) == 0 {
+		return 0, errors.New("missing degrees")
+	}
+	var v [3]float64
+	for i, part := range a.parts {
+		x, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number '%s'", part)
+		}
+		if i > 0 && (x < 0 || x >= 60) {
+			return 0, fmt.Errorf("minutes and seconds must be in [0, 60), found %s", part)
+		}
+		if i < len(a.parts)-1 && x != math.Trunc(x) {
+			return 0, fmt.Errorf("only the last part of an angle may have a fraction, found %s", part)
+		}
+		v[i] = x
+	}
+	negative := strings.HasPrefix(a.parts[0], "-")
+	deg := math.Abs(v[0]) + v[1]/60 + v[2]/3600
+	if negative && a.hemisphere != 0 {
+		return 0, errors.New("angle has both a sign and a hemisphere")
+	}
+	if a.hemisphere == 'S' || a.hemisphere == 'W' {
+		negative = true
+	}
+	if negative {
+		deg = -deg
+	}
+	return deg, nil
+}
+
+// dmsSymbol classifies the unit symbols that may follow a number
+func dmsSymbol(r rune) (rank int, ok bool) {
+	switch r {
+	case '°', 'º', '˚':
+		return 1, true
+	case '\'', '′', '’':
+		return 2, true
+	case '"', '″', '”':
+		return 3, true
+	}
+	return 0, false
+}
+
+// splitDMS splits text into angles
+func splitDMS(s string) ([]*dmsAngle, error) {
+	var angles []*dmsAngle
+	cur := &dmsAngle{}
+	closeAngle := func() {
+		if !cur.empty() {
+			angles = append(angles, cur)
+		}
+		cur = &dmsAngle{}
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsDigit(r) || r == '.' || ((r == '-' || r == '+') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			number := string(runes[i:j])
+			// skip spaces to find a unit symbol
+			k := j
+			for k < len(runes) && runes[k] == ' ' {
+				k++
+			}
+			rank, hasSymbol := 0, false
+			if k < len(runes) {
+				rank, hasSymbol = dmsSymbol(runes[k])
+				// two single quotes are seconds
+				if rank == 2 && k+1 < len(runes) && runes[k+1] == '\'' {
+					rank, k = 3, k+1
+				}
+			}
+			// degrees, or a fourth number, start a new angle
+			if len(cur.parts) == 3 || (hasSymbol && rank == 1 && len(cur.parts) > 0) {
+				closeAngle()
+			}
+			if hasSymbol && rank != len(cur.parts)+1 {
+				return nil, fmt.Errorf("unexpected unit after %s", number)
+			}
+			cur.parts = append(cur.parts, number)
+			i = j
+			if hasSymbol {
+				i = k + 1
+			}
+		case strings.ContainsRune("NSEWnsew", r):
+			h := unicode.ToUpper(r)
+			if len(cur.parts) > 0 && cur.hemisphere == 0 {
+				// a suffix closes its angle
+				cur.hemisphere = h
+				closeAngle()
+			} else {
+				// a prefix opens a new one
+				closeAngle()
+				cur.hemisphere = h
+			}
+			i++
+		case r == ',' || r == ';' || r == '/':
+			closeAngle()
+			i++
+		case unicode.IsSpace(r):
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character '%c'", r)
+		}
+	}
+	closeAngle()
+
+	// without units or hemispheres, numbers are shared equally
+	if len(angles) == 1 && len(angles[0].parts) > 1 && len(angles[0].parts)%2 == 0 && angles[0].hemisphere == 0 {
+		n := len(angles[0].parts) / 2
+		angles = []*dmsAngle{{parts: angles[0].parts[:n]}, {parts: angles[0].parts[n:]}}
+	}
+	return angles, nil
+}
+
+// ParseDMS parses a latitude and longitude written in degrees, minutes and
+// seconds, such as 40°26'46"N 79°58'56"W, or in decimal degrees or degrees
+// and decimal minutes. Hemispheres may come before or after each angle, or
+// be replaced by signs, in which case the latitude is taken to come first.
+func ParseDMS(s string) (lon, lat float64, err error) {
+	angles, err := splitDMS(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(angles) != 2 {
+		return 0, 0, fmt.Errorf("expected a latitude and a longitude in '%s'", s)
+	}
+	latAngle, lonAngle := angles[0], angles[1]
+	isLon := func(a *dmsAngle) bool { return a.hemisphere == 'E' || a.hemisphere == 'W' }
+	isLat := func(a *dmsAngle) bool { return a.hemisphere == 'N' || a.hemisphere == 'S' }
+	if isLon(latAngle) || isLat(lonAngle) {
+		latAngle, lonAngle = lonAngle, latAngle
+	}
+	if isLon(latAngle) || isLat(lonAngle) {
+		return 0, 0, fmt.Errorf("both angles are in the same direction in '%s'", s)
+	}
+	if lat, err = latAngle.value(); err != nil {
+		return 0, 0, err
+	}
+	if lon, err = lonAngle.value(); err != nil {
+		return 0, 0, err
+	}
+	if math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return 0, 0, fmt.Errorf("position out of range in '%s'", s)
+	}
+	return lon, lat, nil
+}
+
+// formatDMS writes an angle as degrees, minutes and seconds with a number
+// of decimal places of seconds
+func formatDMS(angle float64, decimals int, pos, neg byte) string {
+	hemisphere := pos
+	if angle < 0 {
+		hemisphere = neg
+	}
+	scale := math.Pow(10, float64(decimals))
+	// round once, so that seconds never round up to 60
+	total := math.Round(math.Abs(angle)*3600*scale) / scale
+	deg := math.Floor(total / 3600)
+	min := math.Floor((total - deg*3600) / 60)
+	sec := total - deg*3600 - min*60
+	width := 2
+	if decimals > 0 {
+		width = 3 + decimals
+	}
+	return fmt.Sprintf("%.0f°%02.0f'%0*.*f\"%c", deg, min, width, decimals, sec, hemisphere)
+}
+
+// FormatDMS writes a position as latitude and longitude in degrees, minutes
+// and seconds, such as 40°26'46"N 79°58'56"W, with a number of decimal
+// places of seconds
+func FormatDMS(lon, lat float64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	return formatDMS(lat, decimals, 'N', 'S') + " " + formatDMS(lon, decimals, 'E', 'W')
+}
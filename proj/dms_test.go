@@ -0,0 +1,83 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseDMS(t *testing.T) {
+	lon, lat := -(79 + 58./60 + 56./3600), 40+26./60+46./3600
+	for _, s := range []string{
+		`40°26'46"N 79°58'56"W`,
+		`40° 26' 46" N, 79° 58' 56" W`,
+		`79°58′56″W 40°26′46″N`,
+		`N40 26 46 W79 58 56`,
+		`40 26 46 N 79 58 56 W`,
+		`40°26'46''N 79°58'56''W`,
+		`40 26 46 -79 58 56`,
+		`40°26'46" -79°58'56"`,
+	} {
+		x, y, err := ParseDMS(s)
+		if err != nil || math.Abs(x-lon) > 1e-12 || math.Abs(y-lat) > 1e-12 {
+			fmt.Println("recieved    ", x, y, err, "for", s)
+			fmt.Println("but expected", lon, lat)
+			t.Fail()
+		}
+	}
+
+	// decimal degrees and decimal minutes
+	x, y, err := ParseDMS("40.446°N 79.982°W")
+	if err != nil || x != -79.982 || y != 40.446 {
+		fmt.Println("recieved    ", x, y, err)
+		t.Fail()
+	}
+	x, y, err = ParseDMS("S 33 52.2, E 151 12.6")
+	if err != nil || math.Abs(x-151.21) > 1e-12 || math.Abs(y+33.87) > 1e-12 {
+		fmt.Println("recieved    ", x, y, err)
+		t.Fail()
+	}
+
+	for _, s := range []string{
+		`40°26'46"N`,
+		`40°26'46"N 79°58'56"S`,
+		`40°61'N 79°58'W`,
+		`40.5°26'N 79°W`,
+		`-40°N 79°W`,
+		`91°N 79°W`,
+		`40°N 79°W 3°E`,
+		`40°N 79°W!`,
+		`40'N 79°W`,
+	} {
+		if _, _, err := ParseDMS(s); err == nil {
+			fmt.Println("expected an error for", s)
+			t.Fail()
+		}
+	}
+}
+
+func TestFormatDMS(t *testing.T) {
+	for _, c := range []struct {
+		lon, lat float64
+		decimals int
+		expected string
+	}{
+		{-79.98222222, 40.44611111, 0, `40°26'46"N 79°58'56"W`},
+		{-79.98222222, 40.44611111, 2, `40°26'46.00"N 79°58'56.00"W`},
+		{151.21, -33.87, 1, `33°52'12.0"S 151°12'36.0"E`},
+		// seconds carry into minutes and degrees when rounded
+		{0.99999999, 0, 1, `0°00'00.0"N 1°00'00.0"E`},
+	} {
+		s := FormatDMS(c.lon, c.lat, c.decimals)
+		if s != c.expected {
+			fmt.Println("recieved    ", s)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+		lon, lat, err := ParseDMS(s)
+		if err != nil || math.Abs(lon-c.lon) > 1e-3 || math.Abs(lat-c.lat) > 1e-3 {
+			fmt.Println("recieved    ", lon, lat, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+)
+
+// webMercator is the spherical Mercator projection of geographic
+// coordinates used by web map tiles
+type webMercator struct {
+	a float64
+}
+
+// MaxWebMercatorLatitude is the latitude at which the Web Mercator
+// projection is square, the limit of web map tiles
+var MaxWebMercatorLatitude = math.Atan(math.Sinh(math.Pi)) * degreesPerRad
+
+// NewWebMercator returns the Web Mercator projection used by EPSG:3857,
+// which projects WGS84 longitude and latitude as though they were on a
+// sphere with the WGS84 semi-major axis
+func NewWebMercator() Projection {
+	return webMercator{WGS84.A}
+}
+
+func (p webMercator) Forward(lon, lat float64) (x, y float64, err error) {
+	if math.Abs(lat) >= 90 {
+		return 0, 0, fmt.Errorf("latitude %v cannot be projected by Web Mercator", lat)
+	}
+	phi := lat * radiansPerDeg
+	return p.a * math.Remainder(lon, 360) * radiansPerDeg, p.a * math.Atanh(math.Sin(phi)), nil
+}
+
+func (p webMercator) Inverse(x, y float64) (lon, lat float64, err error) {
+	return math.Remainder(x/p.a*degreesPerRad, 360), math.Atan(math.Sinh(y/p.a)) * degreesPerRad, nil
+}
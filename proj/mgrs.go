@@ -0,0 +1,234 @@
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// latitudeBands are the 8° bands of UTM and MGRS from 80°S, the last of
+// which, X, extends to 84°N
+const latitudeBands = "CDEFGHJKLMNPQRSTUVWX"
+
+// mgrsColumns are the 100 km column letters of the three zone sets, and
+// mgrsRows the row letters, which repeat every 2000 km
+var mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+const mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+
+// latitudeBand returns the band letter of a latitude
+func latitudeBand(lat float64) (byte, error) {
+	if lat < -80 || lat > 84 {
+		return 0, fmt.Errorf("latitude %v is outside the UTM area", lat)
+	}
+	i := int(math.Floor((lat + 80) / 8))
+	if i > len(latitudeBands)-1 {
+		i = len(latitudeBands) - 1
+	}
+	return latitudeBands[i], nil
+}
+
+// bandSouth returns the southern latitude of a band, or an error for a
+// letter that is not a band
+func bandSouth(band byte) (float64, error) {
+	i := strings.IndexByte(latitudeBands, band)
+	if i < 0 {
+		return 0, fmt.Errorf("invalid latitude band '%c'", band)
+	}
+	return float64(-80 + 8*i), nil
+}
+
+// inBand returns true if a latitude lies in a band, allowing a degree
+// either side for positions near its edges
+func inBand(band byte, lat float64) bool {
+	south, err := bandSouth(band)
+	if err != nil {
+		return false
+	}
+	north := south + 8
+	if band == 'X' {
+		north = 84
+	}
+	return lat >= south-1 && lat <= north+1
+}
+
+// toUTM returns the zone, band, easting and northing of a position on WGS84
+func toUTM(lon, lat float64) (zone int, band byte, easting, northing float64, err error) {
+	if band, err = latitudeBand(lat); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	zone = UTMZone(lon, lat)
+	easting, northing, err = utm(WGS84, zone, lat < 0).Forward(lon, lat)
+	return zone, band, easting, northing, err
+}
+
+// fromUTM returns the position of an easting and northing in a zone and
+// band on WGS84
+func fromUTM(zone int, band byte, easting, northing float64) (lon, lat float64, err error) {
+	if zone < 1 || zone > 60 {
+		return 0, 0, fmt.Errorf("invalid UTM zone %d", zone)
+	}
+	south, err := bandSouth(band)
+	if err != nil {
+		return 0, 0, err
+	}
+	return utm(WGS84, zone, south < 0).Inverse(easting, northing)
+}
+
+// splitZone reads the zone number and band letter at the start of a
+// reference, returning the remainder
+func splitZone(s string) (zone int, band byte, rest string, err error) {
+	i := 0
+	for i < len(s) && i < 2 && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, 0, "", errors.New("missing UTM zone")
+	}
+	zone, _ = strconv.Atoi(s[:i])
+	rest = strings.TrimLeft(s[i:], " ")
+	if rest == "" {
+		return 0, 0, "", errors.New("missing latitude band")
+	}
+	band = byte(unicode.ToUpper(rune(rest[0])))
+	if _, err = bandSouth(band); err != nil {
+		return 0, 0, "", err
+	}
+	if zone < 1 || zone > 60 {
+		return 0, 0, "", fmt.Errorf("invalid UTM zone %d", zone)
+	}
+	return zone, band, rest[1:], nil
+}
+
+// FormatUTM writes a WGS84 position as a UTM zone, latitude band, easting
+// and northing in metres, such as 17T 589410 4477731
+func FormatUTM(lon, lat float64) (string, error) {
+	zone, band, easting, northing, err := toUTM(lon, lat)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d%c %.0f %.0f", zone, band, easting, northing), nil
+}
+
+// ParseUTM parses a UTM zone, latitude band, easting and northing, such as
+// 17T 589410 4477731 or 17T 589410mE 4477731mN, and returns the WGS84
+// position. The letter after the zone is a latitude band, so that 33S is
+// in the northern hemisphere, and positions outside their band are rejected.
+func ParseUTM(s string) (lon, lat float64, err error) {
+	zone, band, rest, err := splitZone(strings.TrimSpace(s))
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(rest)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected an easting and a northing in '%s'", s)
+	}
+	var en [2]float64
+	for i, suffix := range []string{"mE", "mN"} {
+		field := strings.TrimSuffix(strings.TrimSuffix(fields[i], suffix), "m")
+		if en[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid UTM coordinate '%s'", fields[i])
+		}
+	}
+	if en[0] < 0 || en[0] > 1000000 || en[1] < 0 || en[1] > 10000000 {
+		return 0, 0, fmt.Errorf("UTM coordinates out of range in '%s'", s)
+	}
+	if lon, lat, err = fromUTM(zone, band, en[0], en[1]); err != nil {
+		return 0, 0, err
+	}
+	if !inBand(band, lat) {
+		return 0, 0, fmt.Errorf("UTM position in '%s' is not in band %c", s, band)
+	}
+	return lon, lat, nil
+}
+
+// FormatMGRS writes a WGS84 position as a Military Grid Reference System
+// reference, such as 18SUJ2337106519, with a number of digits from 0 to 5
+// for each of the easting and northing within the 100 km square. Digits
+// are truncated, so that the reference names the square that contains the
+// position. The polar regions, which MGRS covers with UPS, are not
+// supported.
+func FormatMGRS(lon, lat float64, digits int) (string, error) {
+	if digits < 0 || digits > 5 {
+		return "", fmt.Errorf("MGRS references have 0 to 5 digits, not %d", digits)
+	}
+	zone, band, easting, northing, err := toUTM(lon, lat)
+	if err != nil {
+		return "", err
+	}
+	col := int(math.Floor(easting/100000)) - 1
+	if col < 0 || col > 7 {
+		return "", fmt.Errorf("easting %v is outside zone %d", easting, zone)
+	}
+	row := int(math.Floor(northing/100000)) % 20
+	if zone%2 == 0 {
+		row = (row + 5) % 20
+	}
+	ref := fmt.Sprintf("%d%c%c%c", zone, band, mgrsColumns[(zone-1)%3][col], mgrsRows[row])
+	if digits == 0 {
+		return ref, nil
+	}
+	unit := math.Pow(10, float64(5-digits))
+	e := math.Floor(math.Mod(easting, 100000) / unit)
+	n := math.Floor(math.Mod(northing, 100000) / unit)
+	return fmt.Sprintf("%s%0*.0f%0*.0f", ref, digits, e, digits, n), nil
+}
+
+// ParseMGRS parses a Military Grid Reference System reference, such as
+// 18SUJ2337106519 or 18S UJ 23371 06519, and returns the WGS84 position of
+// the centre of the square it names
+func ParseMGRS(s string) (lon, lat float64, err error) {
+	zone, band, rest, err := splitZone(strings.TrimSpace(s))
+	if err != nil {
+		return 0, 0, err
+	}
+	rest = strings.ToUpper(strings.Join(strings.Fields(rest), ""))
+	if len(rest) < 2 || len(rest)%2 != 0 || len(rest) > 12 {
+		return 0, 0, fmt.Errorf("invalid MGRS reference '%s'", s)
+	}
+	col := strings.IndexByte(mgrsColumns[(zone-1)%3], rest[0])
+	row := strings.IndexByte(mgrsRows, rest[1])
+	if col < 0 || row < 0 {
+		return 0, 0, fmt.Errorf("invalid 100 km square '%s' for zone %d", rest[:2], zone)
+	}
+	if zone%2 == 0 {
+		row = (row + 15) % 20
+	}
+	digits := rest[2:]
+	half := len(digits) / 2
+	unit := math.Pow(10, float64(5-half))
+	var e, n float64
+	if half > 0 {
+		ei, err1 := strconv.ParseUint(digits[:half], 10, 32)
+		ni, err2 := strconv.ParseUint(digits[half:], 10, 32)
+		if err1 != nil || err2 != nil {
+			return 0, 0, fmt.Errorf("invalid MGRS digits '%s'", digits)
+		}
+		e, n = float64(ei)*unit, float64(ni)*unit
+	}
+	easting := float64(col+1)*100000 + e + unit/2
+	northing := float64(row)*100000 + n + unit/2
+
+	// the row letters repeat every 2000 km, so the band fixes the northing
+	// as the first repeat north of the band's southern edge
+	south, _ := bandSouth(band)
+	p := utm(WGS84, zone, south < 0)
+	cm := float64(6*zone - 183)
+	_, y0, _ := p.Forward(cm, south)
+	_, y1, _ := p.Forward(cm+3, south)
+	minNorthing := math.Floor(math.Min(y0, y1)/100000) * 100000
+	for northing < minNorthing {
+		northing += 2000000
+	}
+	if lon, lat, err = p.Inverse(easting, northing); err != nil {
+		return 0, 0, err
+	}
+	// the square should overlap the band
+	if !inBand(band, lat) {
+		return 0, 0, fmt.Errorf("MGRS square '%s' is not in band %c", rest[:2], band)
+	}
+	return lon, lat, nil
+}
@@ -0,0 +1,115 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestUTMString(t *testing.T) {
+	s, err := FormatUTM(15, 45)
+	if err != nil || s != "33T 500000 4982950" {
+		fmt.Println("recieved    ", s, err)
+		fmt.Println("but expected", "33T 500000 4982950")
+		t.Fail()
+	}
+	for _, s := range []string{"33T 500000 4982950", "33t 500000mE 4982950mN", "33 T 500000 4982950"} {
+		lon, lat, err := ParseUTM(s)
+		if err != nil || math.Abs(lon-15) > 1e-6 || math.Abs(lat-45) > 1e-5 {
+			fmt.Println("recieved    ", lon, lat, err)
+			fmt.Println("but expected", 15, 45)
+			t.Fail()
+		}
+	}
+	// the band letter places the position south of the equator
+	s, _ = FormatUTM(-58.38, -34.6)
+	lon, lat, err := ParseUTM(s)
+	if s[:3] != "21H" || err != nil || math.Abs(lon+58.38) > 1e-5 || math.Abs(lat+34.6) > 1e-5 {
+		fmt.Println("recieved    ", s, lon, lat, err)
+		t.Fail()
+	}
+	for _, s := range []string{"61T 500000 4982950", "33I 500000 4982950", "33T 500000", "T 500000 4982950", "33T east 4982950",
+		"33C 500000 1000000", "33X 500000 4982950"} {
+		if _, _, err := ParseUTM(s); err == nil {
+			fmt.Println("expected an error for", s)
+			t.Fail()
+		}
+	}
+	if _, err := FormatUTM(0, 85); err == nil {
+		t.Fail()
+	}
+}
+
+func TestMGRS(t *testing.T) {
+	s, err := FormatMGRS(15, 45, 5)
+	if err != nil || s != "33TWK0000082950" {
+		fmt.Println("recieved    ", s, err)
+		fmt.Println("but expected", "33TWK0000082950")
+		t.Fail()
+	}
+	for digits, expected := range []string{"33TWK", "33TWK08", "33TWK0082", "33TWK000829", "33TWK00008295"} {
+		if s, _ = FormatMGRS(15, 45, digits); s != expected {
+			fmt.Println("recieved    ", s)
+			fmt.Println("but expected", expected)
+			t.Fail()
+		}
+	}
+
+	// in even zones the row letters are offset by five
+	lon, lat, err := ParseMGRS("18S UJ 23371 06519")
+	expLon, expLat, _ := fromUTM(18, 'S', 323371.5, 4306519.5)
+	if err != nil || math.Abs(lon-expLon) > 1e-9 || math.Abs(lat-expLat) > 1e-9 {
+		fmt.Println("recieved    ", lon, lat, err)
+		fmt.Println("but expected", expLon, expLat)
+		t.Fail()
+	}
+
+	// round trips, including the southern hemisphere, the widened zones and
+	// positions near band edges
+	positions := [][2]float64{{-77.0365, 38.8977}, {-58.38, -34.6}, {151.2, -33.87}, {10.5, 60.4},
+		{20, 78}, {-179.9, -79.9}, {179.9, 83.9}, {0.1, 0.001}, {0.1, -0.001}, {-123.1, 48.0001}}
+	for _, pos := range positions {
+		s, err := FormatMGRS(pos[0], pos[1], 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lon, lat, err := ParseMGRS(s)
+		if err != nil {
+			t.Fatal(s, err)
+		}
+		// within the 1 m square
+		dx := (lon - pos[0]) * math.Cos(pos[1]*math.Pi/180) * 111320
+		dy := (lat - pos[1]) * 110570
+		if math.Hypot(dx, dy) > 1 {
+			fmt.Println("recieved    ", s, lon, lat)
+			fmt.Println("but expected", pos)
+			t.Fail()
+		}
+	}
+
+	for _, s := range []string{"18SUI2337106519", "18SUJ233710651", "18SAJ23371065", "18SUJ2337a06519", "18S"} {
+		if _, _, err := ParseMGRS(s); err == nil {
+			fmt.Println("expected an error for", s)
+			t.Fail()
+		}
+	}
+	if _, err := FormatMGRS(15, 45, 6); err == nil {
+		t.Fail()
+	}
+	if _, err := FormatMGRS(15, -85, 5); err == nil {
+		t.Fail()
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	for _, s := range []string{"33TWK0000082950", "33T 500000 4982950", `45°N 15°E`} {
+		pt, err := ParsePoint(s)
+		if err != nil || math.Abs(pt.Coordinates[0]-15) > 1e-4 || math.Abs(pt.Coordinates[1]-45) > 1e-4 {
+			fmt.Println("recieved    ", pt, err)
+			t.Fail()
+		}
+	}
+	if _, err := ParsePoint("somewhere"); err == nil {
+		t.Fail()
+	}
+}
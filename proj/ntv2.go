@@ -0,0 +1,295 @@
+package proj
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Grid is an NTv2 grid shift, which converts longitude and latitude from
+// one datum to another by interpolating shifts measured at the nodes of a
+// set of regular grids. Finer subgrids refine their parents over parts of
+// the area.
+type Grid struct {
+	// From and To name the datums of the grid, such as "NAD27" and "NAD83"
+	From, To string
+	grids    []*subgrid
+}
+
+// subgrid is one grid of an NTv2 file. Bounds and spacing are in degrees,
+// with longitudes positive east, and shifts are in degrees.
+type subgrid struct {
+	name, parent       string
+	hasParent          bool
+	south, north       float64
+	east, west         float64
+	latInc, lonInc     float64
+	rows, cols         int
+	latShift, lonShift []float32
+	unitsPerDegree     float64
+	children           []*subgrid
+}
+
+// LoadNTv2 reads an NTv2 grid shift file, such as ntv2_0.gsb for NAD27 to
+// NAD83 in Canada
+func LoadNTv2(path string) (*Grid, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadNTv2(f)
+}
+
+// ntv2Record is a header record of an eight character key and an eight
+// byte value
+type ntv2Record struct {
+	key   string
+	value [8]byte
+}
+
+type ntv2Reader struct {
+	r     io.Reader
+	order binary.ByteOrder
+}
+
+func (nr *ntv2Reader) record() (ntv2Record, error) {
+	var buf [16]byte
+	if _, err := io.ReadFull(nr.r, buf[:]); err != nil {
+		return ntv2Record{}, err
+	}
+	rec := ntv2Record{key: strings.TrimSpace(string(bytes.TrimRight(buf[:8], "\x00")))}
+	copy(rec.value[:], buf[8:])
+	return rec, nil
+}
+
+func (nr *ntv2Reader) expect(key string) (ntv2Record, error) {
+	rec, err := nr.record()
+	if err != nil {
+		return rec, err
+	}
+	if rec.key != key {
+		return rec, fmt.Errorf("expected NTv2 record %s, found '%s'", key, rec.key)
+	}
+	return rec, nil
+}
+
+func (nr *ntv2Reader) int(key string) (int, error) {
+	rec, err := nr.expect(key)
+	return int(int32(nr.order.Uint32(rec.value[:4]))), err
+}
+
+func (nr *ntv2Reader) float(key string) (float64, error) {
+	rec, err := nr.expect(key)
+	return math.Float64frombits(nr.order.Uint64(rec.value[:])), err
+}
+
+func (nr *ntv2Reader) string(key string) (string, error) {
+	rec, err := nr.expect(key)
+	return strings.TrimSpace(string(bytes.TrimRight(rec.value[:], "\x00"))), err
+}
+
+// ReadNTv2 reads an NTv2 grid shift in the binary format used by .gsb
+// files, in either byte order
+func ReadNTv2(r io.Reader) (*Grid, error) {
+	nr := &ntv2Reader{r: r, order: binary.LittleEndian}
+	rec, err := nr.expect("NUM_OREC")
+	if err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(rec.value[:4]) != 11 {
+		nr.order = binary.BigEndian
+		if binary.BigEndian.Uint32(rec.value[:4]) != 11 {
+			return nil, errors.New("NTv2 overview header must have 11 records")
+		}
+	}
+	if _, err = nr.int("NUM_SREC"); err != nil {
+		return nil, err
+	}
+	numFiles, err := nr.int("NUM_FILE")
+	if err != nil {
+		return nil, err
+	}
+	gsType, err := nr.string("GS_TYPE")
+	if err != nil {
+		return nil, err
+	}
+	var unitsPerDegree float64
+	switch strings.ToUpper(gsType) {
+	case "SECONDS":
+		unitsPerDegree = 3600
+	case "MINUTES":
+		unitsPerDegree = 60
+	case "DEGREES":
+		unitsPerDegree = 1
+	default:
+		return nil, fmt.Errorf("unhandled NTv2 GS_TYPE: '%s'", gsType)
+	}
+	if _, err = nr.string("VERSION"); err != nil {
+		return nil, err
+	}
+	grid := &Grid{}
+	if grid.From, err = nr.string("SYSTEM_F"); err != nil {
+		return nil, err
+	}
+	if grid.To, err = nr.string("SYSTEM_T"); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"MAJOR_F", "MINOR_F", "MAJOR_T", "MINOR_T"} {
+		if _, err = nr.float(key); err != nil {
+			return nil, err
+		}
+	}
+
+	byName := make(map[string]*subgrid)
+	for i := 0; i < numFiles; i++ {
+		sg, err := nr.subgrid(unitsPerDegree)
+		if err != nil {
+			return nil, err
+		}
+		byName[sg.name] = sg
+		grid.grids = append(grid.grids, sg)
+	}
+	// link subgrids to their parents, keeping only top level grids in the
+	// list
+	top := grid.grids[:0]
+	for _, sg := range grid.grids {
+		if parent, ok := byName[sg.parent]; ok && sg.hasParent && parent != sg {
+			parent.children = append(parent.children, sg)
+		} else {
+			top = append(top, sg)
+		}
+	}
+	grid.grids = top
+	return grid, nil
+}
+
+func (nr *ntv2Reader) subgrid(unitsPerDegree float64) (*subgrid, error) {
+	sg := &subgrid{unitsPerDegree: unitsPerDegree}
+	var err error
+	if sg.name, err = nr.string("SUB_NAME"); err != nil {
+		return nil, err
+	}
+	if sg.parent, err = nr.string("PARENT"); err != nil {
+		return nil, err
+	}
+	sg.hasParent = !strings.EqualFold(sg.parent, "NONE")
+	if _, err = nr.string("CREATED"); err != nil {
+		return nil, err
+	}
+	if _, err = nr.string("UPDATED"); err != nil {
+		return nil, err
+	}
+	var bounds [6]float64
+	for i, key := range []string{"S_LAT", "N_LAT", "E_LONG", "W_LONG", "LAT_INC", "LONG_INC"} {
+		if bounds[i], err = nr.float(key); err != nil {
+			return nil, err
+		}
+		bounds[i] /= unitsPerDegree
+	}
+	// NTv2 longitudes are positive west
+	sg.south, sg.north, sg.east, sg.west = bounds[0], bounds[1], -bounds[2], -bounds[3]
+	sg.latInc, sg.lonInc = bounds[4], bounds[5]
+	if !(sg.latInc > 0) || !(sg.lonInc > 0) || sg.north < sg.south || sg.west > sg.east {
+		return nil, fmt.Errorf("invalid extent for NTv2 subgrid '%s'", sg.name)
+	}
+	sg.rows = int(math.Round((sg.north-sg.south)/sg.latInc)) + 1
+	sg.cols = int(math.Round((sg.east-sg.west)/sg.lonInc)) + 1
+	count, err := nr.int("GS_COUNT")
+	if err != nil {
+		return nil, err
+	}
+	if count != sg.rows*sg.cols {
+		return nil, fmt.Errorf("NTv2 subgrid '%s' has %d nodes, expected %d", sg.name, count, sg.rows*sg.cols)
+	}
+
+	// nodes run from east to west along rows from south to north, each a
+	// latitude shift, longitude shift and their accuracies
+	buf := make([]byte, 16*count)
+	if _, err = io.ReadFull(nr.r, buf); err != nil {
+		return nil, err
+	}
+	sg.latShift = make([]float32, count)
+	sg.lonShift = make([]float32, count)
+	for i := 0; i < count; i++ {
+		sg.latShift[i] = math.Float32frombits(nr.order.Uint32(buf[16*i:]))
+		sg.lonShift[i] = math.Float32frombits(nr.order.Uint32(buf[16*i+4:]))
+	}
+	return sg, nil
+}
+
+func (sg *subgrid) contains(lon, lat float64) bool {
+	return lat >= sg.south && lat <= sg.north && lon >= sg.west && lon <= sg.east
+}
+
+// shift returns the shift in degrees at a position by bilinear
+// interpolation, with the longitude shift positive east
+func (sg *subgrid) shift(lon, lat float64) (dlon, dlat float64) {
+	fx := (sg.east - lon) / sg.lonInc
+	fy := (lat - sg.south) / sg.latInc
+	col := int(math.Min(math.Floor(fx), float64(sg.cols-2)))
+	row := int(math.Min(math.Floor(fy), float64(sg.rows-2)))
+	col, row = int(math.Max(float64(col), 0)), int(math.Max(float64(row), 0))
+	tx, ty := fx-float64(col), fy-float64(row)
+	node := func(values []float32, r, c int) float64 {
+		r, c = int(math.Min(float64(r), float64(sg.rows-1))), int(math.Min(float64(c), float64(sg.cols-1)))
+		return float64(values[r*sg.cols+c])
+	}
+	interp := func(values []float32) float64 {
+		return (1-ty)*((1-tx)*node(values, row, col)+tx*node(values, row, col+1)) +
+			ty*((1-tx)*node(values, row+1, col)+tx*node(values, row+1, col+1))
+	}
+	return -interp(sg.lonShift) / sg.unitsPerDegree, interp(sg.latShift) / sg.unitsPerDegree
+}
+
+// find returns the finest subgrid containing a position
+func (g *Grid) find(lon, lat float64) *subgrid {
+	var found *subgrid
+	grids := g.grids
+	for grids != nil {
+		var next []*subgrid
+		for _, sg := range grids {
+			if sg.contains(lon, lat) {
+				found, next = sg, sg.children
+				break
+			}
+		}
+		grids = next
+	}
+	return found
+}
+
+// Forward shifts a position from the datum From to the datum To
+func (g *Grid) Forward(lon, lat float64) (float64, float64, error) {
+	sg := g.find(lon, lat)
+	if sg == nil {
+		return 0, 0, fmt.Errorf("position (%v, %v) is outside the grid", lon, lat)
+	}
+	dlon, dlat := sg.shift(lon, lat)
+	return lon + dlon, lat + dlat, nil
+}
+
+// Inverse shifts a position from the datum To to the datum From
+func (g *Grid) Inverse(lon, lat float64) (float64, float64, error) {
+	// the shift at the source position is found by fixed point iteration
+	x, y := lon, lat
+	for i := 0; i < 20; i++ {
+		sg := g.find(x, y)
+		if sg == nil {
+			return 0, 0, fmt.Errorf("position (%v, %v) is outside the grid", lon, lat)
+		}
+		dlon, dlat := sg.shift(x, y)
+		nx, ny := lon-dlon, lat-dlat
+		done := math.Abs(nx-x) < 1e-12 && math.Abs(ny-y) < 1e-12
+		x, y = nx, ny
+		if done {
+			break
+		}
+	}
+	return x, y, nil
+}
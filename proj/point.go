@@ -0,0 +1,24 @@
+package proj
+
+import (
+	"fmt"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// ParsePoint returns a WGS84 Point from a position written as an MGRS
+// reference, a UTM coordinate or a latitude and longitude in degrees,
+// minutes and seconds, as understood by ParseMGRS, ParseUTM and ParseDMS.
+// MGRS references become the centre of the square they name.
+func ParsePoint(s string) (*geojson.Point, error) {
+	lon, lat, err := ParseMGRS(s)
+	if err != nil {
+		lon, lat, err = ParseUTM(s)
+	}
+	if err != nil {
+		if lon, lat, err = ParseDMS(s); err != nil {
+			return nil, fmt.Errorf("'%s' is not an MGRS, UTM or DMS position: %v", s, err)
+		}
+	}
+	return &geojson.Point{Coordinates: []float64{lon, lat}}, nil
+}
@@ -0,0 +1,362 @@
+// Package proj transforms positions between coordinate reference systems,
+// with forward and inverse map projections, Helmert and NTv2 datum shifts,
+// and a registry of common systems keyed by EPSG code. It also reads and
+// writes positions as UTM coordinates, MGRS references and degrees, minutes
+// and seconds. Positions are always in x, y (longitude, latitude) order, as
+// in GeoJSON, whatever the axis order of the system's definition.
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// Ellipsoid is a reference ellipsoid, given by its semi-major axis in
+// metres and its flattening
+type Ellipsoid struct {
+	A, F float64
+}
+
+var (
+	WGS84      = Ellipsoid{6378137, 1 / 298.257223563}
+	GRS80      = Ellipsoid{6378137, 1 / 298.257222101}
+	Clarke1866 = Ellipsoid{6378206.4, 1 / 294.978698214}
+	Airy1830   = Ellipsoid{6377563.396, 1 / 299.3249646}
+	Sphere     = Ellipsoid{6378137, 0}
+)
+
+// e returns the first eccentricity of the ellipsoid
+func (ell Ellipsoid) e() float64 {
+	return math.Sqrt(ell.F * (2 - ell.F))
+}
+
+// Projection is a map projection from longitude and latitude in degrees to
+// plane coordinates in metres
+type Projection interface {
+	Forward(lon, lat float64) (x, y float64, err error)
+	Inverse(x, y float64) (lon, lat float64, err error)
+}
+
+// Units of projected coordinates, in metres
+const (
+	Metre        = 1.0
+	Foot         = 0.3048
+	USSurveyFoot = 1200.0 / 3937.0
+)
+
+const (
+	radiansPerDeg = math.Pi / 180
+	degreesPerRad = 180 / math.Pi
+)
+
+// CRS is a coordinate reference system. A geographic system has a nil
+// Projection and coordinates in degrees of longitude and latitude; a
+// projected system has coordinates in Units.
+type CRS struct {
+	// Code is the EPSG code of the system, or 0 for a custom system
+	Code      int
+	Name      string
+	Ellipsoid Ellipsoid
+	// Datum places the ellipsoid relative to WGS84, and is nil for systems
+	// treated as coincident with it. Register sets a zero Ellipsoid to
+	// that of the Datum.
+	Datum      *Datum
+	Projection Projection
+	// Units is the length of a unit of projected coordinates in metres
+	Units float64
+}
+
+// Geographic returns true if the system has longitude and latitude
+// coordinates
+func (c *CRS) Geographic() bool {
+	return c.Projection == nil
+}
+
+// ToGeographic converts coordinates in the system to longitude and latitude
+// in degrees
+func (c *CRS) ToGeographic(x, y float64) (lon, lat float64, err error) {
+	if c.Projection == nil {
+		return x, y, nil
+	}
+	return c.Projection.Inverse(x*c.Units, y*c.Units)
+}
+
+// FromGeographic converts longitude and latitude in degrees to coordinates
+// in the system
+func (c *CRS) FromGeographic(lon, lat float64) (x, y float64, err error) {
+	if c.Projection == nil {
+		return lon, lat, nil
+	}
+	x, y, err = c.Projection.Forward(lon, lat)
+	return x / c.Units, y / c.Units, err
+}
+
+// GeoJSON returns the named CRS member for the system. Projected systems
+// are named by OGC URN, such as "urn:ogc:def:crs:EPSG::3857". The EPSG
+// definitions of geographic systems have latitude first, so these are
+// named in forms with longitude first, such as
+// "urn:ogc:def:crs:OGC:1.3:CRS84" or the legacy "EPSG:4258".
+func (c *CRS) GeoJSON() *geojson.CRS {
+	switch {
+	case c.Code == 0:
+		return geojson.NewNamedCRS(c.Name)
+	case !c.Geographic():
+		return geojson.NewNamedCRS(fmt.Sprintf("urn:ogc:def:crs:EPSG::%d", c.Code))
+	}
+	for name, code := range ogcGeographic {
+		if code == c.Code {
+			return geojson.NewNamedCRS("urn:ogc:def:crs:OGC:1.3:" + name)
+		}
+	}
+	return geojson.NewNamedCRS(fmt.Sprintf("EPSG:%d", c.Code))
+}
+
+// ogcGeographic are the EPSG codes of the OGC's longitude first geographic
+// systems
+var ogcGeographic = map[string]int{"CRS84": 4326, "CRS83": 4269, "CRS27": 4267}
+
+// FromGeoJSON returns the registered system identified by a CRS member. A
+// nil member is CRS84, the default in RFC 7946.
+func FromGeoJSON(crs *geojson.CRS) (*CRS, error) {
+	id, err := crs.Identifier()
+	if err != nil {
+		return nil, err
+	}
+	switch id.Authority {
+	case "EPSG":
+		code, err := strconv.Atoi(id.Code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EPSG code '%s'", id.Code)
+		}
+		return Lookup(code)
+	case "OGC":
+		if code, ok := ogcGeographic[id.Code]; ok {
+			return Lookup(code)
+		}
+	}
+	return nil, fmt.Errorf("unknown CRS %s", id)
+}
+
+func (c *CRS) String() string {
+	if c.Code != 0 {
+		return fmt.Sprintf("EPSG:%d (%s)", c.Code, c.Name)
+	}
+	return c.Name
+}
+
+// WithDatum returns a copy of the system on another datum, such as one
+// converted to WGS84 by a grid shift
+func (c *CRS) WithDatum(d *Datum) *CRS {
+	out := *c
+	out.Datum = d
+	if d != nil {
+		out.Ellipsoid = d.Ellipsoid
+	}
+	return &out
+}
+
+// shiftDatum converts longitude and latitude from one datum to another
+// through WGS84. A nil datum, or one without a shift, is coincident with
+// WGS84.
+func shiftDatum(lon, lat float64, from, to *Datum) (float64, float64, error) {
+	if from == to {
+		return lon, lat, nil
+	}
+	var err error
+	if from != nil && from.ToWGS84 != nil {
+		if lon, lat, err = from.ToWGS84.Forward(lon, lat); err != nil {
+			return 0, 0, err
+		}
+	}
+	if to != nil && to.ToWGS84 != nil {
+		return to.ToWGS84.Inverse(lon, lat)
+	}
+	return lon, lat, nil
+}
+
+// Transform converts a position from one system to another, shifting it
+// between their datums. Coordinates beyond the second are copied unchanged.
+func Transform(pos []float64, from, to *CRS) ([]float64, error) {
+	if len(pos) < 2 {
+		return nil, errors.New("positions must have at least two coordinates")
+	}
+	lon, lat, err := from.ToGeographic(pos[0], pos[1])
+	if err != nil {
+		return nil, err
+	}
+	if lon, lat, err = shiftDatum(lon, lat, from.Datum, to.Datum); err != nil {
+		return nil, err
+	}
+	x, y, err := to.FromGeographic(lon, lat)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]float64{}, pos...)
+	out[0], out[1] = x, y
+	return out, nil
+}
+
+// replaceCRS sets a CRS member to crs if it is present, or if force is
+// true
+func replaceCRS(ref *geojson.CRSReferencable, crs *geojson.CRS, force bool) {
+	if force || ref.CRS != nil {
+		ref.CRS = crs
+	}
+}
+
+// setCRS sets the CRS member of an object, and replaces those of any
+// members that have their own
+func setCRS(g *geojson.Geo, crs *geojson.CRS, force bool) {
+	switch g.Type {
+	case "Point":
+		replaceCRS(&g.Point.CRSReferencable, crs, force)
+	case "MultiPoint":
+		replaceCRS(&g.MultiPoint.CRSReferencable, crs, force)
+	case "LineString":
+		replaceCRS(&g.LineString.CRSReferencable, crs, force)
+	case "MultiLineString":
+		replaceCRS(&g.MultiLineString.CRSReferencable, crs, force)
+	case "Polygon":
+		replaceCRS(&g.Polygon.CRSReferencable, crs, force)
+	case "MultiPolygon":
+		replaceCRS(&g.MultiPolygon.CRSReferencable, crs, force)
+	case "GeometryCollection":
+		replaceCRS(&g.GeometryCollection.CRSReferencable, crs, force)
+		for _, member := range g.GeometryCollection.Geometries {
+			setCRS(member, crs, false)
+		}
+	case "Feature":
+		replaceCRS(&g.Feature.CRSReferencable, crs, force)
+		setCRS(&g.Feature.Geometry, crs, false)
+	case "FeatureCollection":
+		replaceCRS(&g.FeatureCollection.CRSReferencable, crs, force)
+		for i := range g.FeatureCollection.Features {
+			setCRS(&geojson.Geo{Type: "Feature", Feature: &g.FeatureCollection.Features[i]}, crs, false)
+		}
+	}
+}
+
+// Reproject returns a copy of any geometry, Feature or FeatureCollection
+// with every position converted from one coordinate reference system to
+// another. The CRS member of the result, and of any members that have their
+// own, names the new system. Positions are shifted between the datums of
+// the systems, and heights are unchanged.
+func Reproject(g *geojson.Geo, from, to *CRS) (*geojson.Geo, error) {
+	if from == nil || to == nil {
+		return nil, errors.New("nil CRS")
+	}
+	var err error
+	out, mapErr := g.MapPositions(func(pos []float64) []float64 {
+		if err != nil {
+			return pos
+		}
+		var moved []float64
+		if moved, err = Transform(pos, from, to); err != nil {
+			return pos
+		}
+		return moved
+	})
+	if mapErr != nil {
+		return nil, mapErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	setCRS(out, to.GeoJSON(), true)
+	return out, nil
+}
+
+// reprojectTo returns a copy of g converted to the system to from the
+// systems named by its CRS members, given the member inherited from its
+// parent. CRS members are removed from the copy.
+func reprojectTo(g *geojson.Geo, inherited *geojson.CRS, to *CRS) (*geojson.Geo, error) {
+	own, err := g.GetCRS()
+	if err != nil {
+		return nil, err
+	}
+	crs := inherited
+	if own != nil {
+		crs = own
+	}
+
+	switch g.Type {
+	case "GeometryCollection":
+		coll := &geojson.GeometryCollection{Geometries: make([]*geojson.Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			if coll.Geometries[i], err = reprojectTo(member, crs, to); err != nil {
+				return nil, err
+			}
+		}
+		return &geojson.Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		geom, err := reprojectTo(&g.Feature.Geometry, crs, to)
+		if err != nil {
+			return nil, err
+		}
+		f := *g.Feature
+		f.CRS = nil
+		f.Geometry = *geom
+		return &geojson.Geo{Type: "Feature", Feature: &f}, nil
+	case "FeatureCollection":
+		coll := &geojson.FeatureCollection{Features: make([]geojson.Feature, len(g.FeatureCollection.Features))}
+		for i := range g.FeatureCollection.Features {
+			f, err := reprojectTo(&geojson.Geo{Type: "Feature", Feature: &g.FeatureCollection.Features[i]}, crs, to)
+			if err != nil {
+				return nil, err
+			}
+			coll.Features[i] = *f.Feature
+		}
+		return &geojson.Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+
+	from, err := FromGeoJSON(crs)
+	if err != nil {
+		return nil, err
+	}
+	swap := crs.AxisOrder() == geojson.NorthEast
+	var transformErr error
+	out, err := g.MapPositions(func(pos []float64) []float64 {
+		if transformErr != nil || len(pos) < 2 {
+			return pos
+		}
+		if swap {
+			pos[0], pos[1] = pos[1], pos[0]
+		}
+		var moved []float64
+		if moved, transformErr = Transform(pos, from, to); transformErr != nil {
+			return pos
+		}
+		return moved
+	})
+	if err != nil {
+		return nil, err
+	}
+	if transformErr != nil {
+		return nil, transformErr
+	}
+	setCRS(out, nil, true)
+	return out, nil
+}
+
+// ReprojectTo returns a copy of any geometry, Feature or FeatureCollection
+// converted to a system from the systems named by its own CRS members,
+// including any datum shifts between them. Members without a CRS member
+// use that of their parent, and the default for the whole object is CRS84.
+// Positions referenced to systems with latitude first, such as
+// "urn:ogc:def:crs:EPSG::4326", are read in that order. The result has a
+// single CRS member naming the new system.
+func ReprojectTo(g *geojson.Geo, to *CRS) (*geojson.Geo, error) {
+	if to == nil {
+		return nil, errors.New("nil CRS")
+	}
+	out, err := reprojectTo(g, nil, to)
+	if err != nil {
+		return nil, err
+	}
+	setCRS(out, to.GeoJSON(), true)
+	return out, nil
+}
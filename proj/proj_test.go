@@ -0,0 +1,224 @@
+package proj
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+func checkForward(t *testing.T, p Projection, lon, lat, x, y, tol float64) {
+	px, py, err := p.Forward(lon, lat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(px-x) > tol || math.Abs(py-y) > tol {
+		fmt.Println("recieved    ", px, py)
+		fmt.Println("but expected", x, y)
+		t.Fail()
+	}
+	roundTrip(t, p, lon, lat)
+}
+
+func roundTrip(t *testing.T, p Projection, lon, lat float64) {
+	x, y, err := p.Forward(lon, lat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plon, plat, err := p.Inverse(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(math.Remainder(plon-lon, 360)) > 1e-9 || math.Abs(plat-lat) > 1e-9 {
+		fmt.Println("recieved    ", plon, plat)
+		fmt.Println("but expected", lon, lat)
+		t.Fail()
+	}
+}
+
+func TestTransverseMercator(t *testing.T) {
+	// Snyder (1987), p. 269
+	p := NewTransverseMercator(Clarke1866, -75, 0, 0.9996, 0, 0)
+	checkForward(t, p, -73.5, 40.5, 127106.5, 4484124.4, 0.1)
+
+	// the northing on the central meridian is the scaled meridian arc
+	utm33, err := UTM(33, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForward(t, utm33.Projection, 15, 45, 500000, 4982950.400, 1e-3)
+	roundTrip(t, utm33.Projection, 12.3, 67.8)
+	roundTrip(t, utm33.Projection, 60, -80)
+
+	if _, _, err = utm33.Projection.Forward(120, 0); err == nil {
+		t.Fail()
+	}
+	if _, err = UTM(61, false); err == nil {
+		t.Fail()
+	}
+}
+
+func TestUTMZone(t *testing.T) {
+	for _, c := range []struct {
+		lon, lat float64
+		zone     int
+	}{{-74, 40.7, 18}, {180, 0, 60}, {-180, 0, 1}, {5, 60, 32}, {20, 78, 33}, {5, 50, 31}} {
+		if zone := UTMZone(c.lon, c.lat); zone != c.zone {
+			fmt.Println("recieved    ", zone, "for", c.lon, c.lat)
+			fmt.Println("but expected", c.zone)
+			t.Fail()
+		}
+	}
+}
+
+func TestConics(t *testing.T) {
+	// Snyder (1987), pp. 295-297
+	lcc := NewLambertConformalConic(Clarke1866, 33, 45, -96, 23, 0, 0)
+	checkForward(t, lcc, -75, 35, 1894410.9, 1564649.5, 0.1)
+	aea := NewAlbersEqualArea(Clarke1866, 29.5, 45.5, -96, 23, 0, 0)
+	checkForward(t, aea, -75, 35, 1885472.7, 1535925.0, 0.1)
+
+	// southern hemisphere cones
+	checkForward(t, NewAlbersEqualArea(GRS80, -18, -36, 132, 0, 0, 0), 132, 0, 0, 0, 1e-6)
+	roundTrip(t, NewAlbersEqualArea(GRS80, -18, -36, 132, 0, 0, 0), 150, -40)
+	roundTrip(t, NewLambertConformalConic(GRS80, -20, -40, 140, -30, 0, 0), 150, -25)
+	roundTrip(t, NewLambertConformalConic1SP(GRS80, 10, 45, 0.9998, 0, 0), -5, 70)
+}
+
+func TestWebMercator(t *testing.T) {
+	p := NewWebMercator()
+	checkForward(t, p, 180, 0, 20037508.342789244, 0, 1e-6)
+	checkForward(t, p, 0, MaxWebMercatorLatitude, 0, 20037508.342789244, 1e-6)
+	if _, _, err := p.Forward(0, 90); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	for _, code := range Codes() {
+		crs, err := Lookup(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crs.Geographic() {
+			continue
+		}
+		// positions near the false origin and a common false northing
+		found := false
+		for _, pos := range [][]float64{{0, 0}, {1000, 1000}, {600000, 5000000}} {
+			lon, lat, err := crs.ToGeographic(pos[0], pos[1])
+			if err != nil {
+				continue
+			}
+			x, y, err := crs.FromGeographic(lon, lat)
+			if err != nil {
+				continue
+			}
+			found = true
+			if math.Abs(x-pos[0]) > 1e-6 || math.Abs(y-pos[1]) > 1e-6 {
+				fmt.Println("recieved    ", x, y, "for", crs)
+				fmt.Println("but expected", pos)
+				t.Fail()
+			}
+		}
+		if !found {
+			fmt.Println("no position round trips for", crs)
+			t.Fail()
+		}
+	}
+	if _, err := Lookup(1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGeoJSONNames(t *testing.T) {
+	// names read back as the same system, with longitude first for
+	// geographic systems
+	for _, code := range Codes() {
+		crs, _ := Lookup(code)
+		member := crs.GeoJSON()
+		found, err := FromGeoJSON(member)
+		if err != nil || found.Code != code {
+			fmt.Println("recieved    ", found, err, "for", member.Properties["name"])
+			fmt.Println("but expected", crs)
+			t.Fail()
+		}
+		if crs.Geographic() && member.AxisOrder() != geojson.EastNorth {
+			fmt.Println("latitude first name", member.Properties["name"], "for", crs)
+			t.Fail()
+		}
+	}
+	for code, name := range map[int]string{
+		4326:  "urn:ogc:def:crs:OGC:1.3:CRS84",
+		4269:  "urn:ogc:def:crs:OGC:1.3:CRS83",
+		4258:  "EPSG:4258",
+		32633: "urn:ogc:def:crs:EPSG::32633",
+	} {
+		crs, _ := Lookup(code)
+		if crs.GeoJSON().Properties["name"] != name {
+			fmt.Println("recieved    ", crs.GeoJSON().Properties["name"])
+			fmt.Println("but expected", name)
+			t.Fail()
+		}
+	}
+}
+
+func TestStatePlaneFeet(t *testing.T) {
+	metres, _ := Lookup(26943)
+	feet, _ := Lookup(2227)
+	pos, err := Transform([]float64{6561666.667, 1640416.667}, feet, metres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(pos[0]-2000000) > 1e-3 || math.Abs(pos[1]-500000) > 1e-3 {
+		fmt.Println("recieved    ", pos)
+		t.Fail()
+	}
+	lon, lat, _ := metres.ToGeographic(2000000, 500000)
+	if math.Abs(lon+120.5) > 1e-9 || math.Abs(lat-36.5) > 1e-9 {
+		fmt.Println("recieved    ", lon, lat)
+		t.Fail()
+	}
+}
+
+func TestReproject(t *testing.T) {
+	wgs84, _ := Lookup(4326)
+	mercator, _ := Lookup(3857)
+	fc := &geojson.Geo{Type: "FeatureCollection", FeatureCollection: &geojson.FeatureCollection{
+		CRSReferencable: geojson.CRSReferencable{CRS: wgs84.GeoJSON()},
+		Features: []geojson.Feature{{
+			ID: "a",
+			Geometry: geojson.Geo{Type: "LineString", LineString: &geojson.LineString{
+				CRSReferencable: geojson.CRSReferencable{CRS: wgs84.GeoJSON()},
+				Coordinates:     [][]float64{{0, 0, 10}, {180, 0, 20}},
+			}},
+		}},
+	}}
+	out, err := Reproject(fc, wgs84, mercator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := out.FeatureCollection.Features[0].Geometry.LineString
+	expected := [][]float64{{0, 0, 10}, {20037508.342789244, 0, 20}}
+	if fmt.Sprint(line.Coordinates) != fmt.Sprint(expected) {
+		fmt.Println("recieved    ", line.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	if out.FeatureCollection.CRS.Properties["name"] != "urn:ogc:def:crs:EPSG::3857" ||
+		line.CRS.Properties["name"] != "urn:ogc:def:crs:EPSG::3857" || out.FeatureCollection.Features[0].CRS != nil {
+		fmt.Println("recieved    ", out.FeatureCollection.CRS, line.CRS)
+		t.Fail()
+	}
+	// the input is unchanged
+	if fc.FeatureCollection.CRS.Properties["name"] != "urn:ogc:def:crs:OGC:1.3:CRS84" ||
+		fc.FeatureCollection.Features[0].Geometry.LineString.Coordinates[1][0] != 180 {
+		t.Fail()
+	}
+
+	pole := &geojson.Geo{Type: "Point", Point: &geojson.Point{Coordinates: []float64{0, 90}}}
+	if _, err = Reproject(pole, wgs84, mercator); err == nil {
+		t.Fail()
+	}
+}
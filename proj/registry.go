@@ -0,0 +1,136 @@
+package proj
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[int]*CRS)
+)
+
+// Lookup returns the registered system with an EPSG code
+func Lookup(code int) (*CRS, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	crs, ok := registry[code]
+	if !ok {
+		return nil, fmt.Errorf("unknown CRS EPSG:%d", code)
+	}
+	return crs, nil
+}
+
+// Register adds a system to the registry under its Code, replacing any
+// system registered with the same code
+func Register(crs *CRS) error {
+	if crs.Code <= 0 {
+		return fmt.Errorf("cannot register CRS %q without an EPSG code", crs.Name)
+	}
+	if crs.Projection != nil && !(crs.Units > 0) {
+		return fmt.Errorf("projected CRS %q must have positive units", crs.Name)
+	}
+	if crs.Datum != nil {
+		if crs.Ellipsoid == (Ellipsoid{}) {
+			crs.Ellipsoid = crs.Datum.Ellipsoid
+		} else if crs.Ellipsoid != crs.Datum.Ellipsoid {
+			return fmt.Errorf("CRS %q has an ellipsoid other than that of its datum", crs.Name)
+		}
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[crs.Code] = crs
+	return nil
+}
+
+// Codes returns the EPSG codes of the registered systems in increasing
+// order
+func Codes() []int {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codes := make([]int, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func mustRegister(crs *CRS) {
+	if err := Register(crs); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	mustRegister(&CRS{Code: 4326, Name: "WGS 84", Datum: WGS84Datum})
+	mustRegister(&CRS{Code: 4269, Name: "NAD83", Datum: NAD83Datum})
+	mustRegister(&CRS{Code: 4258, Name: "ETRS89", Datum: ETRS89Datum})
+	mustRegister(&CRS{Code: 3857, Name: "WGS 84 / Pseudo-Mercator", Datum: WGS84Datum,
+		Projection: NewWebMercator(), Units: Metre})
+
+	for zone := 1; zone <= 60; zone++ {
+		mustRegister(&CRS{Code: 32600 + zone, Name: fmt.Sprintf("WGS 84 / UTM zone %dN", zone),
+			Datum: WGS84Datum, Projection: utm(WGS84, zone, false), Units: Metre})
+		mustRegister(&CRS{Code: 32700 + zone, Name: fmt.Sprintf("WGS 84 / UTM zone %dS", zone),
+			Datum: WGS84Datum, Projection: utm(WGS84, zone, true), Units: Metre})
+	}
+	for zone := 1; zone <= 23; zone++ {
+		mustRegister(&CRS{Code: 26900 + zone, Name: fmt.Sprintf("NAD83 / UTM zone %dN", zone),
+			Datum: NAD83Datum, Projection: utm(GRS80, zone, false), Units: Metre})
+	}
+	for zone := 28; zone <= 38; zone++ {
+		mustRegister(&CRS{Code: 25800 + zone, Name: fmt.Sprintf("ETRS89 / UTM zone %dN", zone),
+			Datum: ETRS89Datum, Projection: utm(GRS80, zone, false), Units: Metre})
+	}
+
+	// older datums, shifted to WGS84 by Helmert transformations
+	mustRegister(&CRS{Code: 4267, Name: "NAD27", Datum: NAD27Datum})
+	for zone := 3; zone <= 22; zone++ {
+		mustRegister(&CRS{Code: 26700 + zone, Name: fmt.Sprintf("NAD27 / UTM zone %dN", zone),
+			Datum: NAD27Datum, Projection: utm(Clarke1866, zone, false), Units: Metre})
+	}
+	mustRegister(&CRS{Code: 4277, Name: "OSGB 1936", Datum: OSGB36Datum})
+	mustRegister(&CRS{Code: 27700, Name: "OSGB 1936 / British National Grid", Datum: OSGB36Datum,
+		Projection: NewTransverseMercator(Airy1830, -2, 49, 0.9996012717, 400000, -100000), Units: Metre})
+
+	// national and continental systems, on datums within a metre or two of
+	// WGS84
+	gda94 := &Datum{Name: "GDA94", Ellipsoid: GRS80}
+	nzgd2000 := &Datum{Name: "NZGD2000", Ellipsoid: GRS80}
+	mustRegister(&CRS{Code: 5070, Name: "NAD83 / Conus Albers", Datum: NAD83Datum,
+		Projection: NewAlbersEqualArea(GRS80, 29.5, 45.5, -96, 23, 0, 0), Units: Metre})
+	mustRegister(&CRS{Code: 3577, Name: "GDA94 / Australian Albers", Datum: gda94,
+		Projection: NewAlbersEqualArea(GRS80, -18, -36, 132, 0, 0, 0), Units: Metre})
+	mustRegister(&CRS{Code: 2154, Name: "RGF93 / Lambert-93", Datum: ETRS89Datum,
+		Projection: NewLambertConformalConic(GRS80, 49, 44, 3, 46.5, 700000, 6600000), Units: Metre})
+	mustRegister(&CRS{Code: 2193, Name: "NZGD2000 / New Zealand Transverse Mercator 2000", Datum: nzgd2000,
+		Projection: NewTransverseMercator(GRS80, 173, 0, 0.9996, 1600000, 10000000), Units: Metre})
+
+	// US state plane zones, in metres and US survey feet
+	statePlane := []struct {
+		metreCode, footCode int
+		name                string
+		projection          Projection
+	}{
+		{26929, 0, "Alabama East", NewTransverseMercator(GRS80, -85.83333333333333, 30.5, 0.99996, 200000, 0)},
+		{26943, 2227, "California zone 3", NewLambertConformalConic(GRS80,
+			38.43333333333333, 37.06666666666667, -120.5, 36.5, 2000000, 500000)},
+		{26958, 2236, "Florida East", NewTransverseMercator(GRS80, -81, 24.33333333333333, 0.999941177, 200000, 0)},
+		{26986, 2249, "Massachusetts Mainland", NewLambertConformalConic(GRS80,
+			42.68333333333333, 41.71666666666667, -71.5, 41, 200000, 750000)},
+		{32118, 2263, "New York Long Island", NewLambertConformalConic(GRS80,
+			41.03333333333333, 40.66666666666666, -74, 40.16666666666666, 300000, 0)},
+		{32139, 2277, "Texas Central", NewLambertConformalConic(GRS80,
+			31.88333333333333, 30.11666666666667, -100.3333333333333, 29.66666666666667, 700000, 3000000)},
+	}
+	for _, sp := range statePlane {
+		mustRegister(&CRS{Code: sp.metreCode, Name: "NAD83 / " + sp.name, Datum: NAD83Datum,
+			Projection: sp.projection, Units: Metre})
+		if sp.footCode != 0 {
+			mustRegister(&CRS{Code: sp.footCode, Name: "NAD83 / " + sp.name + " (ftUS)", Datum: NAD83Datum,
+				Projection: sp.projection, Units: USSurveyFoot})
+		}
+	}
+}
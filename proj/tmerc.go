@@ -0,0 +1,169 @@
+package proj
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// transverseMercator uses Krüger's series to sixth order in the third
+// flattening, following Karney (2011), which is accurate to a few
+// nanometres within 4000 km of the central meridian
+type transverseMercator struct {
+	e, lon0, k0, x0, y0 float64
+	// a is the rectifying radius and xi0 the conformal northing of the
+	// latitude of origin
+	a, xi0      float64
+	alpha, beta [6]float64
+}
+
+// NewTransverseMercator returns a Transverse Mercator projection with a
+// central meridian and latitude of origin in degrees, a scale factor on the
+// central meridian, and a false easting and northing in metres
+func NewTransverseMercator(ell Ellipsoid, lon0, lat0, k0, falseEasting, falseNorthing float64) Projection {
+	n := ell.F / (2 - ell.F)
+	n2 := n * n
+	n3, n4, n5, n6 := n2*n, n2*n2, n2*n2*n, n2*n2*n2
+	p := &transverseMercator{
+		e: ell.e(), lon0: lon0, k0: k0, x0: falseEasting, y0: falseNorthing,
+		a: ell.A / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+		alpha: [6]float64{
+			n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+			13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+			61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+			49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+			34729*n5/80640 - 3418889*n6/1995840,
+			212378941 * n6 / 319334400,
+		},
+		beta: [6]float64{
+			n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+			n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+			17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+			4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+			4583*n5/161280 - 108847*n6/3991680,
+			20648693 * n6 / 638668800,
+		},
+	}
+	p.xi0, _ = p.conformal(lat0*radiansPerDeg, 0)
+	return p
+}
+
+// conformalTan returns the tangent of the conformal latitude from the
+// tangent of the geodetic latitude
+func conformalTan(tau, e float64) float64 {
+	sigma := math.Sinh(e * math.Atanh(e*tau/math.Hypot(1, tau)))
+	return tau*math.Hypot(1, sigma) - sigma*math.Hypot(1, tau)
+}
+
+// geodeticTan inverts conformalTan by Newton's method
+func geodeticTan(taup, e float64) float64 {
+	e2 := e * e
+	tau := taup / (1 - e2)
+	for i := 0; i < 10; i++ {
+		taupi := conformalTan(tau, e)
+		dtau := (taup - taupi) / math.Hypot(1, taupi) *
+			(1 + (1-e2)*tau*tau) / ((1 - e2) * math.Hypot(1, tau))
+		tau += dtau
+		if math.Abs(dtau) <= 1e-12*math.Max(1, math.Abs(tau)) {
+			break
+		}
+	}
+	return tau
+}
+
+// conformal returns the normalised northing and easting (xi, eta) of a
+// latitude and longitude from the central meridian, in radians
+func (p *transverseMercator) conformal(phi, lambda float64) (xi, eta float64) {
+	taup := conformalTan(math.Tan(phi), p.e)
+	sinl, cosl := math.Sincos(lambda)
+	xip := math.Atan2(taup, cosl)
+	etap := math.Asinh(sinl / math.Hypot(taup, cosl))
+	xi, eta = xip, etap
+	for j := 1; j <= 6; j++ {
+		k := 2 * float64(j)
+		xi += p.alpha[j-1] * math.Sin(k*xip) * math.Cosh(k*etap)
+		eta += p.alpha[j-1] * math.Cos(k*xip) * math.Sinh(k*etap)
+	}
+	return xi, eta
+}
+
+func (p *transverseMercator) Forward(lon, lat float64) (x, y float64, err error) {
+	if math.Abs(lat) > 90 {
+		return 0, 0, fmt.Errorf("latitude %v out of range", lat)
+	}
+	lambda := math.Remainder(lon-p.lon0, 360) * radiansPerDeg
+	if math.Abs(lambda) >= math.Pi/2 {
+		return 0, 0, errors.New("position is 90 degrees or more from the central meridian")
+	}
+	xi, eta := p.conformal(lat*radiansPerDeg, lambda)
+	return p.x0 + p.k0*p.a*eta, p.y0 + p.k0*p.a*(xi-p.xi0), nil
+}
+
+func (p *transverseMercator) Inverse(x, y float64) (lon, lat float64, err error) {
+	xi := (y-p.y0)/(p.k0*p.a) + p.xi0
+	eta := (x - p.x0) / (p.k0 * p.a)
+	xip, etap := xi, eta
+	for j := 1; j <= 6; j++ {
+		k := 2 * float64(j)
+		xip -= p.beta[j-1] * math.Sin(k*xi) * math.Cosh(k*eta)
+		etap -= p.beta[j-1] * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	if math.IsNaN(xip) || math.IsNaN(etap) || math.IsInf(etap, 0) {
+		return 0, 0, errors.New("position outside the projection's domain")
+	}
+	sinhEta := math.Sinh(etap)
+	sinXi, cosXi := math.Sincos(xip)
+	taup := sinXi / math.Hypot(sinhEta, cosXi)
+	lambda := math.Atan2(sinhEta, cosXi)
+	phi := math.Atan(geodeticTan(taup, p.e))
+	return math.Remainder(p.lon0+lambda*degreesPerRad, 360), phi * degreesPerRad, nil
+}
+
+// UTM returns the WGS84 Universal Transverse Mercator system for a zone
+// from 1 to 60 in the northern or southern hemisphere, EPSG:32601 to 32660
+// and 32701 to 32760
+func UTM(zone int, south bool) (*CRS, error) {
+	if zone < 1 || zone > 60 {
+		return nil, fmt.Errorf("invalid UTM zone %d", zone)
+	}
+	code := 32600 + zone
+	if south {
+		code = 32700 + zone
+	}
+	return Lookup(code)
+}
+
+// UTMZone returns the UTM zone that contains a position, including the
+// exceptions for southwest Norway and Svalbard
+func UTMZone(lon, lat float64) int {
+	lon = math.Remainder(lon, 360)
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+	switch {
+	case lat >= 56 && lat < 64 && lon >= 3 && lon < 12:
+		zone = 32
+	case lat >= 72 && lat <= 84 && lon >= 0 && lon < 42:
+		// zones 31, 33, 35 and 37 are widened over Svalbard
+		switch {
+		case lon < 9:
+			zone = 31
+		case lon < 21:
+			zone = 33
+		case lon < 33:
+			zone = 35
+		default:
+			zone = 37
+		}
+	}
+	return zone
+}
+
+func utm(ell Ellipsoid, zone int, south bool) Projection {
+	northing := 0.0
+	if south {
+		northing = 10000000
+	}
+	return NewTransverseMercator(ell, float64(6*zone-183), 0, 0.9996, 500000, northing)
+}
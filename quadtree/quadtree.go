@@ -256,8 +256,120 @@ func overlaps(bb0, bb1 *[4]float64) bool {
 	return !(bb0[0] > bb1[2] || bb0[2] < bb1[0] || bb0[1] > bb1[3] || bb0[3] < bb1[1])
 }
 
-//func (q *QuadTree) Delete(pt Point) (int, error) {
-//}
+// child returns the child of an internal node whose quadrant contains a
+// Point, and the bounding box of the quadrant
+func (n *Node) child(bbox [4]float64, pt Point) (*Node, [4]float64) {
+	xmid := 0.5 * (bbox[0] + bbox[2])
+	ymid := 0.5 * (bbox[1] + bbox[3])
+	if pt.X < xmid {
+		if pt.Y < ymid {
+			return n.LL, [4]float64{bbox[0], bbox[1], xmid, ymid}
+		}
+		return n.UL, [4]float64{bbox[0], ymid, xmid, bbox[3]}
+	}
+	if pt.Y < ymid {
+		return n.LR, [4]float64{xmid, bbox[1], bbox[2], ymid}
+	}
+	return n.UR, [4]float64{xmid, ymid, bbox[2], bbox[3]}
+}
+
+// size returns the number of points in the subtree, counting no further
+// than limit
+func (n *Node) size(limit int) int {
+	if n.LL == nil {
+		return len(n.Labels)
+	}
+	count := 0
+	for _, c := range []*Node{n.LL, n.LR, n.UL, n.UR} {
+		count += c.size(limit - count)
+		if count >= limit {
+			break
+		}
+	}
+	return count
+}
+
+// collapse moves the points of the subtree into the node, which becomes a
+// leaf
+func (n *Node) collapse() {
+	if n.LL == nil {
+		return
+	}
+	for _, c := range []*Node{n.LL, n.LR, n.UL, n.UR} {
+		c.collapse()
+		n.Points = append(n.Points, c.Points...)
+		n.Labels = append(n.Labels, c.Labels...)
+	}
+	n.LL, n.LR, n.UL, n.UR = nil, nil, nil, nil
+}
+
+// remove deletes the points of a subtree for which match returns true,
+// descending only into the quadrants that contain pt, or into all of them
+// when pt is nil. Subtrees left with fewer than MaxChildren points are
+// merged back into leaves. Returns the number of points removed.
+func (q *QuadTree) remove(node *Node, bbox [4]float64, pt *Point, match func(Point, int) bool) int {
+	removed := 0
+	if node.LL == nil {
+		k := 0
+		for i := 0; i != len(node.Labels); i++ {
+			if match(node.Points[i], node.Labels[i]) {
+				removed++
+				continue
+			}
+			node.Points[k] = node.Points[i]
+			node.Labels[k] = node.Labels[i]
+			k++
+		}
+		node.Points = node.Points[:k]
+		node.Labels = node.Labels[:k]
+		return removed
+	}
+
+	if pt != nil {
+		child, childBbox := node.child(bbox, *pt)
+		removed = q.remove(child, childBbox, pt, match)
+	} else {
+		for _, c := range []*Node{node.LL, node.LR, node.UL, node.UR} {
+			removed += q.remove(c, bbox, nil, match)
+		}
+	}
+	if removed != 0 && node.size(q.MaxChildren) < q.MaxChildren {
+		node.collapse()
+	}
+	return removed
+}
+
+// Delete removes a Point with a label, and returns a non-nil error if the
+// point is missing. Subtrees left with fewer than MaxChildren points are
+// merged back into leaves.
+func (q *QuadTree) Delete(pt Point, label int) error {
+	bbox := q.Bbox
+	if q.Root == nil || pt.X < bbox[0] || pt.X > bbox[2] || pt.Y < bbox[1] || pt.Y > bbox[3] {
+		return errors.New("missing")
+	}
+	found := false
+	q.remove(q.Root, bbox, &pt, func(p Point, l int) bool {
+		if found || p != pt || l != label {
+			return false
+		}
+		found = true
+		return true
+	})
+	if !found {
+		return errors.New("missing")
+	}
+	return nil
+}
+
+// Remove deletes every point with a label, searching the whole tree, and
+// returns a non-nil error if there is none. Subtrees left with fewer than
+// MaxChildren points are merged back into leaves.
+func (q *QuadTree) Remove(label int) error {
+	if q.Root == nil || q.remove(q.Root, q.Bbox, nil, func(_ Point, l int) bool { return l == label }) == 0 {
+		return errors.New("missing")
+	}
+	return nil
+}
 
 // String returns a position in the format '(x,y)'
 func (p Point) String() string {
@@ -193,6 +193,123 @@ func TestNoRoot(t *testing.T) {
 	}
 }
 
+// checkCollapsed returns false if an internal node holds fewer than
+// MaxChildren points
+func checkCollapsed(q *QuadTree, node *Node) bool {
+	if node.LL == nil {
+		return true
+	}
+	if node.size(q.MaxChildren) < q.MaxChildren {
+		return false
+	}
+	for _, c := range []*Node{node.LL, node.LR, node.UL, node.UR} {
+		if !checkCollapsed(q, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDelete(t *testing.T) {
+	quadtree := new(QuadTree)
+	quadtree.MaxChildren = 5
+	quadtree.Bbox = [4]float64{0, 0, 1, 1}
+
+	r := rand.New(rand.NewSource(49))
+	points := make([]Point, 1000)
+	for i := range points {
+		points[i] = Point{r.Float64(), r.Float64()}
+		quadtree.Insert(points[i], i)
+	}
+	deep := quadtree.Depth()
+
+	// the label must match as well as the position
+	if err := quadtree.Delete(points[0], 1); err == nil {
+		t.Fail()
+	}
+	if err := quadtree.Delete(Point{2, 2}, 0); err == nil {
+		t.Fail()
+	}
+
+	for _, i := range r.Perm(len(points))[:900] {
+		if err := quadtree.Delete(points[i], i); err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if _, err := quadtree.Get(points[i]); err == nil {
+			fmt.Printf("point %d was not deleted\n", i)
+			t.Fail()
+		}
+		if err := quadtree.Delete(points[i], i); err == nil {
+			t.Fail()
+		}
+	}
+	labels, _ := quadtree.Select(&quadtree.Bbox)
+	if len(labels) != 100 {
+		fmt.Printf("%d labels remain, but expected %d\n", len(labels), 100)
+		t.Fail()
+	}
+	for _, label := range labels {
+		if got, err := quadtree.Get(points[label]); err != nil || got != label {
+			fmt.Println(label, got, err)
+			t.Fail()
+		}
+	}
+	if !checkCollapsed(quadtree, quadtree.Root) {
+		t.Fail()
+	}
+	if depth := quadtree.Depth(); depth >= deep {
+		fmt.Printf("depth was %d, but expected less than %d\n", depth, deep)
+		t.Fail()
+	}
+}
+
+func TestRemove(t *testing.T) {
+	quadtree := new(QuadTree)
+	quadtree.MaxChildren = 4
+	quadtree.Bbox = [4]float64{0, 0, 1, 1}
+
+	i := 0
+	for x := 0.0; x < 1.0; x = x + 0.1 {
+		for y := 0.0; y < 1.0; y = y + 0.1 {
+			quadtree.Insert(Point{x, y}, i)
+			i++
+		}
+	}
+	for label := 0; label != i; label++ {
+		if err := quadtree.Remove(label); err != nil {
+			fmt.Println(label, err)
+			t.Error()
+		}
+		if !checkCollapsed(quadtree, quadtree.Root) {
+			fmt.Printf("tree not collapsed after removing %d\n", label)
+			t.Fail()
+		}
+	}
+	if err := quadtree.Remove(0); err == nil {
+		t.Fail()
+	}
+	if quadtree.Depth() != 1 || len(quadtree.Root.Labels) != 0 {
+		t.Fail()
+	}
+
+	// points can move by deleting and reinserting them
+	quadtree.Insert(Point{0.2, 0.2}, 7)
+	quadtree.Delete(Point{0.2, 0.2}, 7)
+	quadtree.Insert(Point{0.8, 0.8}, 7)
+	if label, err := quadtree.Get(Point{0.8, 0.8}); err != nil || label != 7 {
+		t.Fail()
+	}
+	if _, err := quadtree.Get(Point{0.2, 0.2}); err == nil {
+		t.Fail()
+	}
+
+	empty := new(QuadTree)
+	if err := empty.Remove(0); err == nil {
+		t.Fail()
+	}
+}
+
 func BenchmarkBuildRandom(b *testing.B) {
 	quadtree := new(QuadTree)
 	quadtree.MaxChildren = 50
@@ -0,0 +1,520 @@
+/* functions for computing DE-9IM intersection matrices and spatial predicates */
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Location identifies the part of a geometry that a position falls on
+type Location int
+
+const (
+	Interior Location = iota
+	Boundary
+	Exterior
+)
+
+// Dimension values stored in an IntersectionMatrix. DimFalse marks an empty
+// intersection.
+const (
+	DimFalse = -1
+	DimPoint = 0
+	DimLine  = 1
+	DimArea  = 2
+)
+
+// IntersectionMatrix is a DE-9IM matrix, indexed by the Location in the first
+// geometry and then by the Location in the second, holding the dimension of
+// each intersection
+type IntersectionMatrix [3][3]int
+
+// String returns the matrix in the conventional nine-character form, such as
+// "212101212"
+func (m IntersectionMatrix) String() string {
+	var sb strings.Builder
+	for i := 0; i != 3; i++ {
+		for j := 0; j != 3; j++ {
+			if m[i][j] == DimFalse {
+				sb.WriteByte('F')
+			} else {
+				sb.WriteByte(byte('0' + m[i][j]))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Matches tests the matrix against a nine-character pattern, in which 'T'
+// matches any non-empty intersection, 'F' an empty one, '*' anything and a
+// digit that exact dimension
+func (m IntersectionMatrix) Matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+	for k := 0; k != 9; k++ {
+		d := m[k/3][k%3]
+		switch c := pattern[k]; c {
+		case '*':
+		case 'T', 't':
+			if d == DimFalse {
+				return false
+			}
+		case 'F', 'f':
+			if d != DimFalse {
+				return false
+			}
+		case '0', '1', '2':
+			if d != int(c-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Transpose returns the matrix with the roles of the geometries exchanged
+func (m IntersectionMatrix) Transpose() IntersectionMatrix {
+	var t IntersectionMatrix
+	for i := 0; i != 3; i++ {
+		for j := 0; j != 3; j++ {
+			t[i][j] = m[j][i]
+		}
+	}
+	return t
+}
+
+func (m *IntersectionMatrix) raise(a, b Location, dim int) {
+	if m[a][b] < dim {
+		m[a][b] = dim
+	}
+}
+
+// locateOnLine returns the Location of pos with respect to a single line.
+// Endpoints of an open line form its boundary; closed lines have none.
+func locateOnLine(pos []float64, line [][]float64) Location {
+	n := len(line)
+	if n == 0 {
+		return Exterior
+	}
+	closed := samePosition(line[0], line[n-1])
+	if !closed && (samePosition(pos, line[0]) || samePosition(pos, line[n-1])) {
+		return Boundary
+	}
+	if n == 1 {
+		return Exterior
+	}
+	for i := 1; i != n; i++ {
+		if onSegment(pos, line[i-1], line[i]) {
+			return Interior
+		}
+	}
+	return Exterior
+}
+
+func samePosition(a, b []float64) bool {
+	return a[0] == b[0] && a[1] == b[1]
+}
+
+// locate returns the Location of pos with respect to the union of the
+// components. The mod-2 rule applies to line endpoints shared between
+// parts, while a position on a polygon ring is on the boundary unless it
+// lies strictly inside another areal part.
+func (c *components) locate(pos []float64) Location {
+	isIn, inArea, onRing := false, false, false
+	nb := 0
+	for _, pt := range c.points {
+		if samePosition(pos, pt) {
+			isIn = true
+		}
+	}
+	for _, line := range c.lines {
+		switch locateOnLine(pos, line) {
+		case Interior:
+			isIn = true
+		case Boundary:
+			nb++
+		}
+	}
+	for _, poly := range c.polygons {
+		switch pointInPolygon(pos, poly) {
+		case 1:
+			inArea = true
+		case 0:
+			onRing = true
+		}
+	}
+	if onRing && !inArea {
+		return Boundary
+	}
+	if nb%2 == 1 {
+		return Boundary
+	}
+	if nb > 0 || isIn || inArea {
+		return Interior
+	}
+	return Exterior
+}
+
+// segments returns every segment of the linework of the components,
+// including polygon rings
+func (c *components) segments() [][2][]float64 {
+	segs := [][2][]float64{}
+	addPath := func(path [][]float64) {
+		for i := 1; i < len(path); i++ {
+			segs = append(segs, [2][]float64{path[i-1], path[i]})
+		}
+	}
+	for _, line := range c.lines {
+		addPath(line)
+	}
+	for _, poly := range c.polygons {
+		for _, ring := range poly {
+			addPath(ring)
+			if n := len(ring); n > 1 && !samePosition(ring[0], ring[n-1]) {
+				segs = append(segs, [2][]float64{ring[n-1], ring[0]})
+			}
+		}
+	}
+	return segs
+}
+
+// segmentIntersection returns the positions shared by the segments a0-a1 and
+// b0-b1: none, a single crossing point, or the ends of a collinear overlap
+func segmentIntersection(a0, a1, b0, b1 []float64) [][]float64 {
+	if !segmentsIntersect(a0, a1, b0, b1) {
+		return nil
+	}
+	d1 := cross(b0, b1, a0)
+	d2 := cross(b0, b1, a1)
+	d3 := cross(a0, a1, b0)
+	d4 := cross(a0, a1, b1)
+	if d1 == 0 && d2 == 0 {
+		// collinear overlap
+		out := [][]float64{}
+		for _, p := range [][]float64{a0, a1} {
+			if onSegment(p, b0, b1) {
+				out = append(out, p)
+			}
+		}
+		for _, p := range [][]float64{b0, b1} {
+			if onSegment(p, a0, a1) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	// exact endpoints avoid introducing round-off at shared vertices
+	switch {
+	case d1 == 0:
+		return [][]float64{a0}
+	case d2 == 0:
+		return [][]float64{a1}
+	case d3 == 0:
+		return [][]float64{b0}
+	case d4 == 0:
+		return [][]float64{b1}
+	}
+	t := d1 / (d1 - d2)
+	return [][]float64{{a0[0] + t*(a1[0]-a0[0]), a0[1] + t*(a1[1]-a0[1])}}
+}
+
+// splitSegment divides seg at the given positions, which must lie on it
+func splitSegment(seg [2][]float64, cuts [][]float64) [][2][]float64 {
+	dx := seg[1][0] - seg[0][0]
+	dy := seg[1][1] - seg[0][1]
+	type cut struct {
+		t   float64
+		pos []float64
+	}
+	ts := []cut{{0, seg[0]}, {1, seg[1]}}
+	for _, p := range cuts {
+		var t float64
+		if math.Abs(dx) > math.Abs(dy) {
+			t = (p[0] - seg[0][0]) / dx
+		} else if dy != 0 {
+			t = (p[1] - seg[0][1]) / dy
+		}
+		if t > 0 && t < 1 {
+			ts = append(ts, cut{t, p})
+		}
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].t < ts[j].t })
+	out := [][2][]float64{}
+	for k := 1; k < len(ts); k++ {
+		if !samePosition(ts[k-1].pos, ts[k].pos) {
+			out = append(out, [2][]float64{ts[k-1].pos, ts[k].pos})
+		}
+	}
+	return out
+}
+
+// node splits the segments of a wherever they meet the segments or isolated
+// points of b, returning the pieces and the meeting positions
+func node(a, b [][2][]float64, bpoints [][]float64) ([][2][]float64, [][]float64) {
+	pieces := [][2][]float64{}
+	nodes := [][]float64{}
+	for _, sa := range a {
+		cuts := [][]float64{}
+		for _, sb := range b {
+			if pts := segmentIntersection(sa[0], sa[1], sb[0], sb[1]); pts != nil {
+				cuts = append(cuts, pts...)
+			}
+		}
+		for _, p := range bpoints {
+			if onSegment(p, sa[0], sa[1]) {
+				cuts = append(cuts, p)
+			}
+		}
+		nodes = append(nodes, cuts...)
+		pieces = append(pieces, splitSegment(sa, cuts)...)
+	}
+	return pieces, nodes
+}
+
+func relateComponents(a, b *components) IntersectionMatrix {
+	var m IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = DimFalse
+		}
+	}
+	m[Exterior][Exterior] = DimArea
+
+	classify := func(pos []float64, dim int) {
+		la := a.locate(pos)
+		lb := b.locate(pos)
+		if dim == DimArea && (la == Boundary || lb == Boundary) {
+			return
+		}
+		m.raise(la, lb, dim)
+	}
+
+	segsA := a.segments()
+	segsB := b.segments()
+	piecesA, nodesA := node(segsA, segsB, b.points)
+	piecesB, nodesB := node(segsB, segsA, a.points)
+
+	// vertices and intersection points
+	for _, set := range [][][]float64{a.points, b.points, nodesA, nodesB} {
+		for _, pos := range set {
+			classify(pos, DimPoint)
+		}
+	}
+	for _, segs := range [][][2][]float64{segsA, segsB} {
+		for _, seg := range segs {
+			classify(seg[0], DimPoint)
+			classify(seg[1], DimPoint)
+		}
+	}
+
+	// the noded linework has a constant location along each piece, so the
+	// midpoint stands for the whole piece, and points just either side of it
+	// stand for the faces of the arrangement
+	pieces := append(piecesA, piecesB...)
+	for k, piece := range pieces {
+		mid := []float64{0.5 * (piece[0][0] + piece[1][0]), 0.5 * (piece[0][1] + piece[1][1])}
+		classify(mid, DimLine)
+
+		length := math.Hypot(piece[1][0]-piece[0][0], piece[1][1]-piece[0][1])
+		clearance := length
+		for l, other := range pieces {
+			if l == k {
+				continue
+			}
+			if d := segmentDistance(mid, other[0], other[1]); d > 0 && d < clearance {
+				clearance = d
+			}
+		}
+		for _, set := range [][][]float64{a.points, b.points} {
+			for _, pos := range set {
+				if d := math.Hypot(pos[0]-mid[0], pos[1]-mid[1]); d > 0 && d < clearance {
+					clearance = d
+				}
+			}
+		}
+		eps := clearance / 4
+		nx := -(piece[1][1] - piece[0][1]) / length * eps
+		ny := (piece[1][0] - piece[0][0]) / length * eps
+		classify([]float64{mid[0] + nx, mid[1] + ny}, DimArea)
+		classify([]float64{mid[0] - nx, mid[1] - ny}, DimArea)
+	}
+	return m
+}
+
+// Relate returns the DE-9IM intersection matrix describing how two geometries
+// (or the geometries of Features and FeatureCollections) interact. The
+// computation is quadratic in the number of segments.
+func Relate(a, b *Geo) (IntersectionMatrix, error) {
+	var m IntersectionMatrix
+	ca := new(components)
+	if err := ca.add(a); err != nil {
+		return m, err
+	}
+	cb := new(components)
+	if err := cb.add(b); err != nil {
+		return m, err
+	}
+	return relateComponents(ca, cb), nil
+}
+
+// dimensions returns the highest dimension of each geometry
+func dimensions(a, b *Geo) (int, int, error) {
+	ca := new(components)
+	if err := ca.add(a); err != nil {
+		return 0, 0, err
+	}
+	cb := new(components)
+	if err := cb.add(b); err != nil {
+		return 0, 0, err
+	}
+	return ca.dimension(), cb.dimension(), nil
+}
+
+// pointPolygonLocation returns the location of a Point with respect to a
+// Polygon or MultiPolygon without building the full matrix, or false if the
+// geometries are not of those types
+func pointPolygonLocation(pt, area *Geo) (Location, bool) {
+	if pt.Type != "Point" {
+		return Exterior, false
+	}
+	switch area.Type {
+	case "Polygon", "MultiPolygon":
+		c := new(components)
+		c.add(area)
+		return c.locate(pt.Point.Coordinates), true
+	}
+	return Exterior, false
+}
+
+// Disjoint returns true if the geometries share no points
+func (g *Geo) Disjoint(other *Geo) (bool, error) {
+	if loc, ok := pointPolygonLocation(g, other); ok {
+		return loc == Exterior, nil
+	}
+	if loc, ok := pointPolygonLocation(other, g); ok {
+		return loc == Exterior, nil
+	}
+	m, err := Relate(g, other)
+	return m.Matches("FF*FF****"), err
+}
+
+// Intersects returns true if the geometries share at least one point
+func (g *Geo) Intersects(other *Geo) (bool, error) {
+	disjoint, err := g.Disjoint(other)
+	return !disjoint, err
+}
+
+// Touches returns true if the geometries meet only at their boundaries
+func (g *Geo) Touches(other *Geo) (bool, error) {
+	if loc, ok := pointPolygonLocation(g, other); ok {
+		return loc == Boundary, nil
+	}
+	if loc, ok := pointPolygonLocation(other, g); ok {
+		return loc == Boundary, nil
+	}
+	m, err := Relate(g, other)
+	return m.Matches("FT*******") || m.Matches("F**T*****") || m.Matches("F***T****"), err
+}
+
+// Within returns true if the geometry lies inside other, with at least one
+// interior point in common
+func (g *Geo) Within(other *Geo) (bool, error) {
+	if loc, ok := pointPolygonLocation(g, other); ok {
+		return loc == Interior, nil
+	}
+	m, err := Relate(g, other)
+	return m.Matches("T*F**F***"), err
+}
+
+// Contains returns true if other lies inside the geometry, with at least one
+// interior point in common. A Point on the boundary of a Polygon is not
+// contained by it.
+func (g *Geo) Contains(other *Geo) (bool, error) {
+	if loc, ok := pointPolygonLocation(other, g); ok {
+		return loc == Interior, nil
+	}
+	m, err := Relate(g, other)
+	return m.Matches("T*****FF*"), err
+}
+
+// Covers returns true if no point of other lies outside the geometry
+func (g *Geo) Covers(other *Geo) (bool, error) {
+	if loc, ok := pointPolygonLocation(other, g); ok {
+		return loc != Exterior, nil
+	}
+	m, err := Relate(g, other)
+	return m.Matches("T*****FF*") || m.Matches("*T****FF*") ||
+		m.Matches("***T**FF*") || m.Matches("****T*FF*"), err
+}
+
+// CoveredBy returns true if no point of the geometry lies outside other
+func (g *Geo) CoveredBy(other *Geo) (bool, error) {
+	return other.Covers(g)
+}
+
+// Crosses returns true if the geometries share some but not all interior
+// points, and the intersection has lower dimension than the larger geometry
+func (g *Geo) Crosses(other *Geo) (bool, error) {
+	da, db, err := dimensions(g, other)
+	if err != nil {
+		return false, err
+	}
+	m, err := Relate(g, other)
+	switch {
+	case da < db:
+		return m.Matches("T*T******"), err
+	case da > db:
+		return m.Matches("T*****T**"), err
+	case da == DimLine && db == DimLine:
+		return m.Matches("0********"), err
+	}
+	return false, err
+}
+
+// Overlaps returns true if geometries of the same dimension share some but
+// not all of their interior points
+func (g *Geo) Overlaps(other *Geo) (bool, error) {
+	da, db, err := dimensions(g, other)
+	if err != nil {
+		return false, err
+	}
+	if da != db {
+		return false, nil
+	}
+	m, err := Relate(g, other)
+	if da == DimLine {
+		return m.Matches("1*T***T**"), err
+	}
+	return m.Matches("T*T***T**"), err
+}
+
+// ContainsPoint returns true if the position lies in the interior of the
+// Polygon, outside any holes
+func (g *Polygon) ContainsPoint(pt *Point) bool {
+	return pointInPolygon(pt.Coordinates, g.Coordinates) == 1
+}
+
+// ContainsPoint returns true if the position lies in the interior of the
+// MultiPolygon
+func (g *MultiPolygon) ContainsPoint(pt *Point) bool {
+	c := &components{polygons: g.Coordinates}
+	return c.locate(pt.Coordinates) == Interior
+}
+
+// String returns the name of the Location
+func (loc Location) String() string {
+	switch loc {
+	case Interior:
+		return "Interior"
+	case Boundary:
+		return "Boundary"
+	case Exterior:
+		return "Exterior"
+	}
+	return fmt.Sprintf("Location(%d)", int(loc))
+}
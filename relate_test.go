@@ -0,0 +1,176 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func polygonGeo(rings ...[][]float64) *Geo {
+	return &Geo{Type: "Polygon", Polygon: &Polygon{Coordinates: rings}}
+}
+
+func lineGeo(positions ...[]float64) *Geo {
+	return &Geo{Type: "LineString", LineString: &LineString{Coordinates: positions}}
+}
+
+func pointGeo(x, y float64) *Geo {
+	return &Geo{Type: "Point", Point: &Point{Coordinates: []float64{x, y}}}
+}
+
+func square(x0, y0, size float64) [][]float64 {
+	return [][]float64{{x0, y0}, {x0 + size, y0}, {x0 + size, y0 + size}, {x0, y0 + size}, {x0, y0}}
+}
+
+func TestRelateMatrix(t *testing.T) {
+	cases := []struct {
+		a, b     *Geo
+		expected string
+	}{
+		// overlapping squares
+		{polygonGeo(square(0, 0, 2)), polygonGeo(square(1, 1, 2)), "212101212"},
+		// squares sharing an edge
+		{polygonGeo(square(0, 0, 1)), polygonGeo(square(1, 0, 1)), "FF2F11212"},
+		// disjoint squares
+		{polygonGeo(square(0, 0, 1)), polygonGeo(square(5, 5, 1)), "FF2FF1212"},
+		// identical squares
+		{polygonGeo(square(0, 0, 1)), polygonGeo(square(0, 0, 1)), "2FFF1FFF2"},
+		// line crossing a square
+		{lineGeo([]float64{-1, 0.5}, []float64{2, 0.5}), polygonGeo(square(0, 0, 1)), "101FF0212"},
+		// crossing lines
+		{lineGeo([]float64{0, 0}, []float64{2, 2}), lineGeo([]float64{0, 2}, []float64{2, 0}), "0F1FF0102"},
+		// point on the boundary of a square
+		{pointGeo(1, 0.5), polygonGeo(square(0, 0, 1)), "F0FFFF212"},
+	}
+	for _, c := range cases {
+		m, err := Relate(c.a, c.b)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if m.String() != c.expected {
+			fmt.Println("recieved    ", m)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestRelateHole(t *testing.T) {
+	outer := polygonGeo(square(0, 0, 10), [][]float64{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}})
+	inner := polygonGeo(square(4, 4, 1))
+	m, err := Relate(outer, inner)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if m.String() != "FF2FF1212" {
+		fmt.Println("recieved", m)
+		t.Fail()
+	}
+	// filling the hole exactly makes the polygons touch along the hole
+	fill := polygonGeo([][]float64{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}})
+	touches, _ := outer.Touches(fill)
+	if !touches {
+		t.Fail()
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	poly := polygonGeo(square(0, 0, 10), [][]float64{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}})
+	cases := []struct {
+		x, y     float64
+		contains bool
+		covers   bool
+	}{
+		{1, 1, true, true},
+		{5, 5, false, false},
+		{0, 5, false, true},
+		{2, 5, false, true},
+		{11, 5, false, false},
+	}
+	for _, c := range cases {
+		pt := pointGeo(c.x, c.y)
+		contains, _ := poly.Contains(pt)
+		covers, _ := poly.Covers(pt)
+		within, _ := pt.Within(poly)
+		if contains != c.contains || within != c.contains || covers != c.covers {
+			fmt.Println("wrong result for", c.x, c.y)
+			t.Fail()
+		}
+		if poly.Polygon.ContainsPoint(pt.Point) != c.contains {
+			t.Fail()
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	a := polygonGeo(square(0, 0, 2))
+	b := polygonGeo(square(1, 1, 2))
+	c := polygonGeo(square(2, 0, 1))
+	d := polygonGeo(square(0.5, 0.5, 1))
+	line := lineGeo([]float64{-1, 1}, []float64{3, 1})
+
+	check := func(name string, got bool, err error, expected bool) {
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if got != expected {
+			fmt.Println(name, "returned", got)
+			t.Fail()
+		}
+	}
+	r, err := a.Intersects(b)
+	check("intersects", r, err, true)
+	r, err = a.Overlaps(b)
+	check("overlaps", r, err, true)
+	r, err = a.Touches(c)
+	check("touches", r, err, true)
+	r, err = a.Disjoint(polygonGeo(square(5, 5, 1)))
+	check("disjoint", r, err, true)
+	r, err = a.Contains(d)
+	check("contains", r, err, true)
+	r, err = d.Within(a)
+	check("within", r, err, true)
+	r, err = a.Within(d)
+	check("within", r, err, false)
+	r, err = line.Crosses(a)
+	check("crosses", r, err, true)
+	r, err = a.Covers(polygonGeo(square(0, 0, 1)))
+	check("covers", r, err, true)
+	r, err = polygonGeo(square(0, 0, 1)).CoveredBy(a)
+	check("covered by", r, err, true)
+
+	// a boundary line is covered but not contained
+	edge := lineGeo([]float64{0, 0}, []float64{2, 0})
+	r, err = a.Contains(edge)
+	check("contains edge", r, err, false)
+	r, err = a.Covers(edge)
+	check("covers edge", r, err, true)
+
+	// a vertex shared by two parts of a MultiPolygon is on its boundary
+	corners := &Geo{Type: "MultiPolygon", MultiPolygon: &MultiPolygon{Coordinates: [][][][]float64{
+		{square(0, 0, 1)}, {square(1, 1, 1)}}}}
+	vertex := pointGeo(1, 1)
+	m, err := Relate(vertex, corners)
+	if err != nil || m.String() != "F0FFFF212" {
+		fmt.Println("recieved    ", m, err)
+		fmt.Println("but expected", "F0FFFF212")
+		t.Fail()
+	}
+	r, err = vertex.Touches(corners)
+	check("touches shared vertex", r, err, true)
+	r, err = vertex.Within(corners)
+	check("within shared vertex", r, err, false)
+}
+
+func TestIntersectionMatrixMatches(t *testing.T) {
+	var m IntersectionMatrix
+	m = IntersectionMatrix{{2, 1, 2}, {1, 0, 1}, {2, 1, 2}}
+	if !m.Matches("T*T***T**") || m.Matches("FF*FF****") || m.Matches("2") {
+		t.Fail()
+	}
+	if m.Transpose().String() != "212101212" {
+		t.Fail()
+	}
+}
@@ -0,0 +1,201 @@
+/* discrete Hausdorff and Fréchet distances for comparing the shapes of geometries */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// densified returns g with positions inserted so that no segment is longer
+// than maxLength, or g itself when maxLength is zero
+func (m lineMeasure) densified(g *Geo, maxLength float64) (*Geo, error) {
+	if maxLength == 0 {
+		return g, nil
+	}
+	return m.densifyGeo(g, maxLength)
+}
+
+// hausdorff returns the largest distance from a position of either geometry
+// to the nearest position of the other
+func (m lineMeasure) hausdorff(a, b *Geo, maxLength float64) (float64, error) {
+	var positions [2][][]float64
+	for i, g := range []*Geo{a, b} {
+		dense, err := m.densified(g, maxLength)
+		if err != nil {
+			return 0, err
+		}
+		c := new(components)
+		if err = c.add(dense); err != nil {
+			return 0, err
+		}
+		positions[i] = append(positions[i], c.points...)
+		for _, line := range c.lines {
+			positions[i] = append(positions[i], line...)
+		}
+		for _, poly := range c.polygons {
+			for _, ring := range poly {
+				positions[i] = append(positions[i], ring...)
+			}
+		}
+		if len(positions[i]) == 0 {
+			return 0, errors.New("cannot measure the distance to an empty geometry")
+		}
+	}
+	// directed returns the largest distance from a position of p to the
+	// nearest position of q
+	directed := func(p, q [][]float64) float64 {
+		var dmax float64
+		for _, pp := range p {
+			dmin := math.Inf(1)
+			for _, qq := range q {
+				if d := m.length(pp, qq); d < dmin {
+					dmin = d
+					if dmin <= dmax {
+						// pp cannot raise the maximum
+						break
+					}
+				}
+			}
+			dmax = math.Max(dmax, dmin)
+		}
+		return dmax
+	}
+	return math.Max(directed(positions[0], positions[1]), directed(positions[1], positions[0])), nil
+}
+
+// tracePath returns the sequence of positions that a geometry follows, for
+// measuring Fréchet distance, and whether it is a ring, which has no
+// particular start or direction
+func tracePath(g *Geo) ([][]float64, bool, error) {
+	switch g.Type {
+	case "Point":
+		return [][]float64{g.Point.Coordinates}, false, nil
+	case "MultiPoint":
+		return g.MultiPoint.Coordinates, false, nil
+	case "LineString":
+		return g.LineString.Coordinates, false, nil
+	case "Polygon":
+		if len(g.Polygon.Coordinates) == 0 {
+			return nil, false, nil
+		}
+		return g.Polygon.Coordinates[0], true, nil
+	case "Feature":
+		return tracePath(&g.Feature.Geometry)
+	case "MultiLineString", "MultiPolygon", "GeometryCollection", "FeatureCollection":
+		return nil, false, fmt.Errorf("cannot measure Fréchet distance for a %s", g.Type)
+	}
+	return nil, false, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+// coupling returns the discrete Fréchet distance between two paths
+func (m lineMeasure) coupling(p, q [][]float64) float64 {
+	// coupling distances are found row by row, keeping only the previous row
+	prev := make([]float64, len(q))
+	cur := make([]float64, len(q))
+	for i := range p {
+		for j := range q {
+			d := m.length(p[i], q[j])
+			switch {
+			case i == 0 && j == 0:
+				cur[j] = d
+			case i == 0:
+				cur[j] = math.Max(cur[j-1], d)
+			case j == 0:
+				cur[j] = math.Max(prev[j], d)
+			default:
+				cur[j] = math.Max(math.Min(math.Min(prev[j], prev[j-1]), cur[j-1]), d)
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(q)-1]
+}
+
+// frechet returns the discrete Fréchet distance between the paths traced
+// by two geometries
+func (m lineMeasure) frechet(a, b *Geo, maxLength float64) (float64, error) {
+	var paths [2][][]float64
+	var rings [2]bool
+	for i, g := range []*Geo{a, b} {
+		dense, err := m.densified(g, maxLength)
+		if err != nil {
+			return 0, err
+		}
+		if paths[i], rings[i], err = tracePath(dense); err != nil {
+			return 0, err
+		}
+		if len(paths[i]) == 0 {
+			return 0, errors.New("cannot measure the distance to an empty geometry")
+		}
+	}
+	p, q := paths[0], paths[1]
+	if !rings[0] && !rings[1] {
+		return m.coupling(p, q), nil
+	}
+	// every traversal of one ring is tried, which is enough when both are
+	// rings because a coupling around two rings may be cut anywhere
+	if !rings[0] {
+		p, q = q, p
+	}
+	ring := openRing(p)
+	n := len(ring)
+	path := make([][]float64, n+1)
+	best := math.Inf(1)
+	for start := 0; start != n; start++ {
+		for _, step := range []int{1, n - 1} {
+			for k := range path {
+				path[k] = ring[(start+k*step)%n]
+			}
+			best = math.Min(best, m.coupling(path, q))
+		}
+	}
+	return best, nil
+}
+
+// HausdorffDistance returns the discrete Hausdorff distance between two
+// geometries, Features or FeatureCollections: the largest distance from a
+// position of either one to the nearest position of the other, in the units
+// of the coordinates. It measures how far apart two shapes are at worst,
+// such as the drift of a boundary between versions of a dataset. When
+// maxSegmentLength is positive, positions are first inserted so that no
+// segment is longer, which brings the result closer to the continuous
+// Hausdorff distance; when it is zero, only the positions given are used.
+// The computation is quadratic in the number of positions.
+func HausdorffDistance(a, b *Geo, maxSegmentLength float64) (float64, error) {
+	return planarMeasure.hausdorff(a, b, maxSegmentLength)
+}
+
+// GeodesicHausdorffDistance returns the discrete Hausdorff distance in
+// metres between two geometries of longitude and latitude positions,
+// measured along geodesics on the WGS84 ellipsoid. maxSegmentLength, in
+// metres, is used as by GeodesicDensify. See HausdorffDistance.
+func GeodesicHausdorffDistance(a, b *Geo, maxSegmentLength float64) (float64, error) {
+	return geodesicMeasure.hausdorff(a, b, maxSegmentLength)
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the paths
+// traced by two geometries, in the units of the coordinates. It is the
+// shortest leash that lets two walkers cover the paths from start to end,
+// each moving forward from position to position, and so unlike the
+// Hausdorff distance it takes the direction and order of the paths into
+// account, as when scoring the similarity of GPS traces. Paths are the
+// positions of a LineString or MultiPoint in order, the exterior ring of a
+// Polygon, or the position of a Point, and may belong to Features; other
+// types return an error. maxSegmentLength is used as by HausdorffDistance.
+// The computation is quadratic in the number of positions. A ring has no
+// start or direction, so for Polygons the least distance over every start
+// position and both directions of the ring is taken, which multiplies the
+// cost by twice the number of positions in the ring.
+func FrechetDistance(a, b *Geo, maxSegmentLength float64) (float64, error) {
+	return planarMeasure.frechet(a, b, maxSegmentLength)
+}
+
+// GeodesicFrechetDistance returns the discrete Fréchet distance in metres
+// between the paths traced by two geometries of longitude and latitude
+// positions, measured along geodesics on the WGS84 ellipsoid.
+// maxSegmentLength, in metres, is used as by GeodesicDensify. See
+// FrechetDistance.
+func GeodesicFrechetDistance(a, b *Geo, maxSegmentLength float64) (float64, error) {
+	return geodesicMeasure.frechet(a, b, maxSegmentLength)
+}
@@ -0,0 +1,141 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestHausdorffDistance(t *testing.T) {
+	a := lineGeo([]float64{0, 0}, []float64{10, 0})
+	b := lineGeo([]float64{0, 1}, []float64{10, 1})
+	d, err := HausdorffDistance(a, b, 0)
+	if err != nil || d != 1 {
+		fmt.Println("recieved    ", d, err)
+		fmt.Println("but expected", 1)
+		t.Fail()
+	}
+
+	// the apex lies 3 from the line, but √34 from its nearest vertex
+	peak := lineGeo([]float64{0, 0}, []float64{5, 3}, []float64{10, 0})
+	d, _ = HausdorffDistance(a, peak, 0)
+	if math.Abs(d-math.Sqrt(34)) > 1e-12 {
+		fmt.Println("recieved    ", d)
+		fmt.Println("but expected", math.Sqrt(34))
+		t.Fail()
+	}
+	d, _ = HausdorffDistance(a, peak, 1)
+	if math.Abs(d-3) > 1e-12 {
+		fmt.Println("recieved    ", d)
+		fmt.Println("but expected", 3)
+		t.Fail()
+	}
+
+	// the Hausdorff distance ignores direction
+	reversed := lineGeo([]float64{10, 0}, []float64{0, 0})
+	if d, _ = HausdorffDistance(a, reversed, 0); d != 0 {
+		t.Fail()
+	}
+
+	// a square drifting by 0.5
+	square := polygonGeo([][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}})
+	moved := polygonGeo([][]float64{{0.5, 0}, {2.5, 0}, {2.5, 2}, {0.5, 2}, {0.5, 0}})
+	if d, _ = HausdorffDistance(square, moved, 0); d != 0.5 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+
+	if _, err = HausdorffDistance(a, &Geo{Type: "MultiPoint", MultiPoint: &MultiPoint{}}, 0); err == nil {
+		t.Fail()
+	}
+	if _, err = HausdorffDistance(a, b, -1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	a := lineGeo([]float64{0, 0}, []float64{10, 0})
+	b := lineGeo([]float64{0, 1}, []float64{10, 1})
+	d, err := FrechetDistance(a, b, 0)
+	if err != nil || d != 1 {
+		fmt.Println("recieved    ", d, err)
+		t.Fail()
+	}
+
+	// the Fréchet distance follows the direction of the paths
+	reversed := lineGeo([]float64{10, 0}, []float64{0, 0})
+	if d, _ = FrechetDistance(a, reversed, 0); d != 10 {
+		fmt.Println("recieved    ", d)
+		fmt.Println("but expected", 10)
+		t.Fail()
+	}
+
+	// a trace that doubles back is close in Hausdorff distance but not in
+	// Fréchet distance
+	trace := lineGeo([]float64{0, 0}, []float64{8, 0}, []float64{2, 0}, []float64{10, 0})
+	h, _ := HausdorffDistance(a, trace, 1)
+	f, _ := FrechetDistance(a, trace, 1)
+	if h != 0 || f != 3 {
+		fmt.Println("recieved    ", h, f)
+		fmt.Println("but expected", 0, 3)
+		t.Fail()
+	}
+
+	// Features and Polygons trace their exterior rings
+	square := &Geo{Type: "Feature", Feature: &Feature{
+		Geometry: *polygonGeo([][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}})}}
+	moved := polygonGeo([][]float64{{0, 1}, {2, 1}, {2, 3}, {0, 3}, {0, 1}})
+	if d, _ = FrechetDistance(square, moved, 0); d != 1 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+
+	// rings are compared whatever their start and direction
+	for _, ring := range [][][]float64{
+		{{2, 2}, {0, 2}, {0, 0}, {2, 0}, {2, 2}},
+		{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}},
+		{{2, 0}, {0, 0}, {0, 2}, {2, 2}, {2, 0}},
+	} {
+		same := polygonGeo(ring)
+		for _, pair := range [][2]*Geo{{square, same}, {same, square}} {
+			if d, _ = FrechetDistance(pair[0], pair[1], 0.5); d != 0 {
+				fmt.Println("recieved    ", d, "for", ring)
+				fmt.Println("but expected", 0)
+				t.Fail()
+			}
+		}
+		if d, _ = FrechetDistance(moved, same, 0); d != 1 {
+			fmt.Println("recieved    ", d, "for", ring)
+			fmt.Println("but expected", 1)
+			t.Fail()
+		}
+	}
+	// a ring compared with a line is traversed from its best start
+	open := lineGeo([]float64{2, 2}, []float64{0, 2}, []float64{0, 0}, []float64{2, 0}, []float64{2, 2})
+	if d, _ = FrechetDistance(open, square, 0); d != 0 {
+		fmt.Println("recieved    ", d)
+		t.Fail()
+	}
+
+	mls := &Geo{Type: "MultiLineString", MultiLineString: &MultiLineString{Coordinates: [][][]float64{{{0, 0}, {1, 1}}}}}
+	if _, err = FrechetDistance(a, mls, 0); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGeodesicSimilarity(t *testing.T) {
+	// one degree of longitude on the equator
+	a := lineGeo([]float64{0, 0}, []float64{0, 1})
+	b := lineGeo([]float64{1, 0}, []float64{1, 1})
+	h, err := GeodesicHausdorffDistance(a, b, 0)
+	if err != nil || math.Abs(h-111319.491) > 0.01 {
+		fmt.Println("recieved    ", h, err)
+		fmt.Println("but expected", 111319.491)
+		t.Fail()
+	}
+	f, err := GeodesicFrechetDistance(a, b, 10000)
+	if err != nil || f < h || f > h*1.001 {
+		fmt.Println("recieved    ", f, err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,455 @@
+/* functions for simplifying lines and polygons */
+package geojson
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+)
+
+// SimplifyMethod selects the algorithm used by Simplify
+type SimplifyMethod int
+
+const (
+	// DouglasPeucker drops vertices closer than the tolerance to the line
+	// joining the vertices that are kept
+	DouglasPeucker SimplifyMethod = iota
+	// VisvalingamWhyatt drops vertices whose effective triangle area is
+	// smaller than the tolerance, which is interpreted as an area
+	VisvalingamWhyatt
+)
+
+// PreserveTopology may be combined with a method, as in
+// DouglasPeucker|PreserveTopology, to restore vertices wherever
+// simplification would make lines or rings intersect
+const PreserveTopology SimplifyMethod = 1 << 8
+
+// simplifyPath tracks which vertices of a line or ring survive
+// simplification. Each vertex has an importance, which is the tolerance
+// above which it is retained; endpoints are always retained.
+type simplifyPath struct {
+	coords     [][]float64
+	importance []float64
+	keep       []bool
+	ring       bool
+}
+
+func newSimplifyPath(coords [][]float64, ring bool, method SimplifyMethod) *simplifyPath {
+	p := &simplifyPath{coords: coords, ring: ring}
+	p.importance = make([]float64, len(coords))
+	p.keep = make([]bool, len(coords))
+	if len(coords) == 0 {
+		return p
+	}
+	p.importance[0] = math.Inf(1)
+	p.importance[len(coords)-1] = math.Inf(1)
+	if method == VisvalingamWhyatt {
+		p.visvalingamImportance()
+	} else {
+		p.douglasPeuckerImportance()
+	}
+	return p
+}
+
+func (p *simplifyPath) douglasPeuckerImportance() {
+	type span struct {
+		i, j   int
+		parent float64
+	}
+	stack := []span{{0, len(p.coords) - 1, math.Inf(1)}}
+	for len(stack) != 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s.j-s.i < 2 {
+			continue
+		}
+		kmax, dmax := -1, -1.0
+		for k := s.i + 1; k < s.j; k++ {
+			if d := segmentDistance(p.coords[k], p.coords[s.i], p.coords[s.j]); d > dmax {
+				kmax, dmax = k, d
+			}
+		}
+		imp := math.Min(dmax, s.parent)
+		p.importance[kmax] = imp
+		stack = append(stack, span{s.i, kmax, imp}, span{kmax, s.j, imp})
+	}
+}
+
+// vwVertex is a vertex in the Visvalingam-Whyatt elimination queue
+type vwVertex struct {
+	i, prev, next int
+	area          float64
+	index         int
+}
+
+type vwQueue []*vwVertex
+
+func (q vwQueue) Len() int           { return len(q) }
+func (q vwQueue) Less(i, j int) bool { return q[i].area < q[j].area }
+func (q vwQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *vwQueue) Push(x interface{}) {
+	v := x.(*vwVertex)
+	v.index = len(*q)
+	*q = append(*q, v)
+}
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	v := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return v
+}
+
+func (p *simplifyPath) triangleArea(i, j, k int) float64 {
+	return 0.5 * math.Abs(cross(p.coords[i], p.coords[k], p.coords[j]))
+}
+
+func (p *simplifyPath) visvalingamImportance() {
+	n := len(p.coords)
+	if n < 3 {
+		return
+	}
+	vertices := make([]*vwVertex, n)
+	q := &vwQueue{}
+	for i := 1; i < n-1; i++ {
+		vertices[i] = &vwVertex{i: i, prev: i - 1, next: i + 1, area: p.triangleArea(i-1, i, i+1)}
+		heap.Push(q, vertices[i])
+	}
+	last := 0.0
+	for q.Len() != 0 {
+		v := heap.Pop(q).(*vwVertex)
+		// effective areas never decrease, so that a vertex is not
+		// eliminated before one that was less significant
+		last = math.Max(last, v.area)
+		p.importance[v.i] = last
+		if prev := vertices[v.prev]; prev != nil {
+			prev.next = v.next
+			prev.area = p.triangleArea(prev.prev, prev.i, prev.next)
+			heap.Fix(q, prev.index)
+		}
+		if next := vertices[v.next]; next != nil {
+			next.prev = v.prev
+			next.area = p.triangleArea(next.prev, next.i, next.next)
+			heap.Fix(q, next.index)
+		}
+		vertices[v.i] = nil
+	}
+}
+
+// apply retains the vertices more important than tolerance, keeping rings
+// to at least four positions
+func (p *simplifyPath) apply(tolerance float64) {
+	n := len(p.coords)
+	kept := 0
+	for i := range p.coords {
+		p.keep[i] = p.importance[i] > tolerance
+		if p.keep[i] {
+			kept++
+		}
+	}
+	if !p.ring || n < 4 {
+		return
+	}
+	for kept < 4 {
+		best := -1
+		for i := 1; i < n-1; i++ {
+			if !p.keep[i] && (best < 0 || p.importance[i] > p.importance[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		p.keep[best] = true
+		kept++
+	}
+}
+
+// segments returns the simplified segments as pairs of indices into coords
+func (p *simplifyPath) segments() [][2]int {
+	segs := [][2]int{}
+	prev := -1
+	for i := range p.coords {
+		if !p.keep[i] {
+			continue
+		}
+		if prev >= 0 {
+			segs = append(segs, [2]int{prev, i})
+		}
+		prev = i
+	}
+	return segs
+}
+
+// restore retains the most important dropped vertex between i and j,
+// returning its index or -1 if there is none
+func (p *simplifyPath) restore(i, j int) int {
+	best := -1
+	for k := i + 1; k < j; k++ {
+		if best < 0 || p.importance[k] > p.importance[best] {
+			best = k
+		}
+	}
+	if best >= 0 {
+		p.keep[best] = true
+	}
+	return best
+}
+
+func (p *simplifyPath) result() [][]float64 {
+	out := [][]float64{}
+	for i, pos := range p.coords {
+		if p.keep[i] {
+			out = append(out, pos)
+		}
+	}
+	return out
+}
+
+// simplifySeg is a segment of a simplified path, running between the
+// retained vertices i and j
+type simplifySeg struct {
+	path *simplifyPath
+	i, j int
+	dead bool
+}
+
+func (s *simplifySeg) simplified() bool {
+	return s.j-s.i > 1
+}
+
+// onOriginal returns true if pt lies on the part of the original path that
+// the segment replaces
+func (s *simplifySeg) onOriginal(pt []float64) bool {
+	for k := s.i; k < s.j; k++ {
+		if onSegment(pt, s.path.coords[k], s.path.coords[k+1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *simplifySeg) hasEnd(pt []float64) bool {
+	return samePosition(pt, s.path.coords[s.i]) || samePosition(pt, s.path.coords[s.j])
+}
+
+// segmentsConflict returns true if two segments meet other than at a
+// retained vertex of one that the original path of the other already
+// touched, such as a vertex shared by neighbouring segments or by lines
+// joined end to end. Collinear overlaps are always conflicts.
+func segmentsConflict(a, b *simplifySeg) bool {
+	pts := segmentIntersection(a.path.coords[a.i], a.path.coords[a.j], b.path.coords[b.i], b.path.coords[b.j])
+	if len(pts) == 0 {
+		return false
+	}
+	pt := pts[0]
+	for _, other := range pts[1:] {
+		if !samePosition(other, pt) {
+			return true
+		}
+	}
+	return !(a.hasEnd(pt) && b.onOriginal(pt)) && !(b.hasEnd(pt) && a.onOriginal(pt))
+}
+
+// segmentGrid is a uniform grid of cells indexing segments by their extents
+type segmentGrid struct {
+	x0, y0, size float64
+	cells        map[[2]int][]*simplifySeg
+}
+
+func newSegmentGrid(paths []*simplifyPath) *segmentGrid {
+	xmin, ymin := math.Inf(1), math.Inf(1)
+	xmax, ymax := math.Inf(-1), math.Inf(-1)
+	count := 0
+	for _, p := range paths {
+		for _, pos := range p.coords {
+			xmin, xmax = math.Min(xmin, pos[0]), math.Max(xmax, pos[0])
+			ymin, ymax = math.Min(ymin, pos[1]), math.Max(ymax, pos[1])
+		}
+		count += len(p.coords)
+	}
+	// aim for a few original segments per cell
+	w, h := xmax-xmin, ymax-ymin
+	size := math.Max(math.Sqrt(w*h/float64(count+1)), math.Max(w, h)/float64(count+1))
+	if !(size > 0) || math.IsInf(size, 0) {
+		size = 1
+	}
+	return &segmentGrid{xmin, ymin, size, make(map[[2]int][]*simplifySeg)}
+}
+
+// span returns the range of cells covered by the extent of a segment
+func (g *segmentGrid) span(s *simplifySeg) (int, int, int, int) {
+	a, b := s.path.coords[s.i], s.path.coords[s.j]
+	cell := func(v, v0 float64) int {
+		return int(math.Floor((v - v0) / g.size))
+	}
+	return cell(math.Min(a[0], b[0]), g.x0), cell(math.Min(a[1], b[1]), g.y0),
+		cell(math.Max(a[0], b[0]), g.x0), cell(math.Max(a[1], b[1]), g.y0)
+}
+
+func (g *segmentGrid) insert(s *simplifySeg) {
+	i0, j0, i1, j1 := g.span(s)
+	for i := i0; i <= i1; i++ {
+		for j := j0; j <= j1; j++ {
+			g.cells[[2]int{i, j}] = append(g.cells[[2]int{i, j}], s)
+		}
+	}
+}
+
+// remove marks a segment as dead and drops it from its cells
+func (g *segmentGrid) remove(s *simplifySeg) {
+	s.dead = true
+	i0, j0, i1, j1 := g.span(s)
+	for i := i0; i <= i1; i++ {
+		for j := j0; j <= j1; j++ {
+			cell := g.cells[[2]int{i, j}]
+			for k, t := range cell {
+				if t == s {
+					cell[k] = cell[len(cell)-1]
+					g.cells[[2]int{i, j}] = cell[:len(cell)-1]
+					break
+				}
+			}
+		}
+	}
+}
+
+// near returns the live segments sharing a cell with s
+func (g *segmentGrid) near(s *simplifySeg) []*simplifySeg {
+	i0, j0, i1, j1 := g.span(s)
+	seen := make(map[*simplifySeg]bool)
+	out := []*simplifySeg{}
+	for i := i0; i <= i1; i++ {
+		for j := j0; j <= j1; j++ {
+			for _, t := range g.cells[[2]int{i, j}] {
+				if t != s && !seen[t] {
+					seen[t] = true
+					out = append(out, t)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// preserveTopology restores vertices until no simplified segment meets any
+// other segment of the geometry where the original paths did not meet. The
+// segments are indexed in a grid, and only the segments created by restoring
+// a vertex are checked again.
+func preserveTopology(paths []*simplifyPath) {
+	grid := newSegmentGrid(paths)
+	work := []*simplifySeg{}
+	for _, p := range paths {
+		for _, ij := range p.segments() {
+			s := &simplifySeg{path: p, i: ij[0], j: ij[1]}
+			grid.insert(s)
+			work = append(work, s)
+		}
+	}
+	// split replaces a simplified segment by the two segments on either side
+	// of its most important dropped vertex
+	split := func(s *simplifySeg) {
+		k := s.path.restore(s.i, s.j)
+		grid.remove(s)
+		for _, t := range []*simplifySeg{{path: s.path, i: s.i, j: k}, {path: s.path, i: k, j: s.j}} {
+			grid.insert(t)
+			work = append(work, t)
+		}
+	}
+	for len(work) != 0 {
+		s := work[len(work)-1]
+		work = work[:len(work)-1]
+		for _, t := range grid.near(s) {
+			if s.dead {
+				break
+			}
+			if t.dead || (!s.simplified() && !t.simplified()) || !segmentsConflict(s, t) {
+				continue
+			}
+			if s.simplified() {
+				split(s)
+			} else {
+				split(t)
+			}
+		}
+	}
+}
+
+func simplifyPaths(lines [][][]float64, rings bool, tolerance float64, method SimplifyMethod) ([][][]float64, error) {
+	if tolerance < 0 {
+		return nil, errors.New("tolerance must not be negative")
+	}
+	base := method &^ PreserveTopology
+	if base != DouglasPeucker && base != VisvalingamWhyatt {
+		return nil, errors.New("unknown simplification method")
+	}
+	paths := make([]*simplifyPath, len(lines))
+	for i, line := range lines {
+		paths[i] = newSimplifyPath(line, rings, base)
+		paths[i].apply(tolerance)
+	}
+	if method&PreserveTopology != 0 {
+		preserveTopology(paths)
+	}
+	out := make([][][]float64, len(paths))
+	for i, p := range paths {
+		out[i] = p.result()
+	}
+	return out, nil
+}
+
+// Simplify returns a simplified copy of the LineString. For the
+// DouglasPeucker method tolerance is a distance, and for VisvalingamWhyatt
+// it is an area, both in the units of the coordinates.
+func (g *LineString) Simplify(tolerance float64, method SimplifyMethod) (*LineString, error) {
+	out, err := simplifyPaths([][][]float64{g.Coordinates}, false, tolerance, method)
+	if err != nil {
+		return nil, err
+	}
+	return &LineString{CRSReferencable: g.CRSReferencable, Coordinates: out[0]}, nil
+}
+
+// Simplify returns a simplified copy of the MultiLineString. With
+// PreserveTopology, lines are also kept from crossing one another.
+func (g *MultiLineString) Simplify(tolerance float64, method SimplifyMethod) (*MultiLineString, error) {
+	out, err := simplifyPaths(g.Coordinates, false, tolerance, method)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiLineString{CRSReferencable: g.CRSReferencable, Coordinates: out}, nil
+}
+
+// Simplify returns a simplified copy of the Polygon. Rings always keep at
+// least four positions, and with PreserveTopology neither self-intersect nor
+// cross other rings.
+func (g *Polygon) Simplify(tolerance float64, method SimplifyMethod) (*Polygon, error) {
+	out, err := simplifyPaths(g.Coordinates, true, tolerance, method)
+	if err != nil {
+		return nil, err
+	}
+	return &Polygon{CRSReferencable: g.CRSReferencable, Coordinates: out}, nil
+}
+
+// Simplify returns a simplified copy of the MultiPolygon. With
+// PreserveTopology, rings are also kept from crossing rings of other
+// polygons.
+func (g *MultiPolygon) Simplify(tolerance float64, method SimplifyMethod) (*MultiPolygon, error) {
+	rings := [][][]float64{}
+	for _, poly := range g.Coordinates {
+		rings = append(rings, poly...)
+	}
+	out, err := simplifyPaths(rings, true, tolerance, method)
+	if err != nil {
+		return nil, err
+	}
+	coords := make([][][][]float64, len(g.Coordinates))
+	k := 0
+	for i, poly := range g.Coordinates {
+		coords[i] = out[k : k+len(poly)]
+		k += len(poly)
+	}
+	return &MultiPolygon{CRSReferencable: g.CRSReferencable, Coordinates: coords}, nil
+}
@@ -0,0 +1,161 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSimplifyDouglasPeucker(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{
+		{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7}, {6, 8.1}, {7, 9}, {8, 9}, {9, 9},
+	}}
+	out, err := ls.Simplify(0.5, DouglasPeucker)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := "[[0 0] [2 -0.1] [3 5] [7 9] [9 9]]"
+	if fmt.Sprint(out.Coordinates) != expected {
+		fmt.Println("recieved    ", out.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	// the input is left unchanged
+	if len(ls.Coordinates) != 10 {
+		t.Fail()
+	}
+}
+
+func TestSimplifyVisvalingamWhyatt(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{
+		{0, 0}, {1, 0.1}, {2, 0}, {3, 3}, {4, 0},
+	}}
+	out, err := ls.Simplify(0.5, VisvalingamWhyatt)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := "[[0 0] [2 0] [3 3] [4 0]]"
+	if fmt.Sprint(out.Coordinates) != expected {
+		fmt.Println("recieved    ", out.Coordinates)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	out, _ = ls.Simplify(10, VisvalingamWhyatt)
+	if len(out.Coordinates) != 2 {
+		t.Fail()
+	}
+}
+
+func TestSimplifyRingMinimum(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{
+		{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}, {0, 0},
+	}}}
+	for _, method := range []SimplifyMethod{DouglasPeucker, VisvalingamWhyatt} {
+		out, err := poly.Simplify(100, method)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		ring := out.Coordinates[0]
+		if len(ring) != 4 || !samePosition(ring[0], ring[3]) || ringArea(ring) == 0 {
+			fmt.Println("recieved", ring)
+			t.Fail()
+		}
+	}
+}
+
+func TestSimplifyPreserveTopology(t *testing.T) {
+	// a hole sitting in a notch of the shell that plain simplification
+	// flattens, cutting through the hole
+	poly := &Polygon{Coordinates: [][][]float64{
+		{{0, 1}, {5, 0.2}, {10, 1}, {10, 10}, {0, 10}, {0, 1}},
+		{{4.5, 0.5}, {4.5, 1.5}, {5.5, 1.5}, {5.5, 0.5}, {4.5, 0.5}},
+	}}
+	plain, err := poly.Simplify(1, DouglasPeucker)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(plain.Coordinates[0]) != 5 {
+		t.Fatal("expected the shell vertex to be dropped")
+	}
+	preserved, err := poly.Simplify(1, DouglasPeucker|PreserveTopology)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(preserved.Coordinates[0]) != 6 {
+		fmt.Println("recieved", preserved.Coordinates[0])
+		t.Fail()
+	}
+}
+
+func TestSimplifyPreserveTopologyJoined(t *testing.T) {
+	// two long wiggly lines joined end to end only meet where they did
+	// originally, so they simplify as far as they would without the check
+	parts := make([][][]float64, 2)
+	for p := range parts {
+		for i := 0; i != 2000; i++ {
+			x := float64(p*1999 + i)
+			parts[p] = append(parts[p], []float64{x, 0.01 * float64(i%2)})
+		}
+		parts[p][0][1] = 0
+		parts[p][1999][1] = 0
+	}
+	mls := &MultiLineString{Coordinates: parts}
+	out, err := mls.Simplify(1, DouglasPeucker|PreserveTopology)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(out.Coordinates[0]) != 2 || len(out.Coordinates[1]) != 2 {
+		fmt.Println("recieved", len(out.Coordinates[0]), len(out.Coordinates[1]))
+		t.Fail()
+	}
+
+	// a short line under a peak of the second, which the simplified line
+	// would cut through
+	mls.Coordinates = append(mls.Coordinates, [][]float64{{2000.2, 0.003}, {2000.2, -0.005}})
+	out, err = mls.Simplify(1, DouglasPeucker|PreserveTopology)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(out.Coordinates[0]) != 2 || len(out.Coordinates[1]) == 2 {
+		fmt.Println("recieved", len(out.Coordinates[0]), len(out.Coordinates[1]))
+		t.Fail()
+	}
+}
+
+func TestSimplifyMultiPolygon(t *testing.T) {
+	mpoly := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {1, 0.01}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		{
+			{{5, 5}, {9, 5}, {9, 9}, {5, 9}, {5, 5}},
+			{{6, 6}, {6, 8}, {7, 8.01}, {8, 8}, {8, 6}, {6, 6}},
+		},
+	}}
+	out, err := mpoly.Simplify(0.1, DouglasPeucker)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(out.Coordinates) != 2 || len(out.Coordinates[1]) != 2 {
+		t.Fatal("structure was not preserved")
+	}
+	if len(out.Coordinates[0][0]) != 5 || len(out.Coordinates[1][1]) != 5 {
+		fmt.Println("recieved", out.Coordinates)
+		t.Fail()
+	}
+}
+
+func TestSimplifyInvalid(t *testing.T) {
+	ls := &LineString{Coordinates: [][]float64{{0, 0}, {1, 1}}}
+	if _, err := ls.Simplify(-1, DouglasPeucker); err == nil {
+		t.Fail()
+	}
+	if _, err := ls.Simplify(1, SimplifyMethod(7)); err == nil {
+		t.Fail()
+	}
+}
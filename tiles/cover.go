@@ -0,0 +1,369 @@
+package tiles
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// tileSet accumulates tiles at a single zoom level
+type tileSet struct {
+	z     int
+	tiles map[Tile]struct{}
+}
+
+func newTileSet(z int) (*tileSet, error) {
+	if err := checkZoom(z); err != nil {
+		return nil, err
+	}
+	return &tileSet{z, make(map[Tile]struct{})}, nil
+}
+
+func (s *tileSet) add(x, y int) {
+	s.tiles[Tile{s.z, x, y}.normalize()] = struct{}{}
+}
+
+// sorted returns the tiles ordered by row and then column
+func (s *tileSet) sorted() []Tile {
+	tiles := make([]Tile, 0, len(s.tiles))
+	for t := range s.tiles {
+		tiles = append(tiles, t)
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].Y != tiles[j].Y {
+			return tiles[i].Y < tiles[j].Y
+		}
+		return tiles[i].X < tiles[j].X
+	})
+	return tiles
+}
+
+// project converts a sequence of positions to tile units. Longitudes are
+// unwrapped so that consecutive positions are never more than 180 degrees
+// apart, which makes edges crossing the antimeridian take the short way round.
+// Positions beyond the Web Mercator limits are placed on the top or bottom
+// edge of the grid, and coordinates within round-off of a tile edge are
+// snapped to it so that boundaries along tile edges stay on them.
+func project(positions [][]float64, z int) ([][2]float64, error) {
+	n := math.Exp2(float64(z))
+	snap := func(v float64) float64 {
+		if r := math.Round(v); math.Abs(v-r) <= n*1e-12 {
+			return r
+		}
+		return v
+	}
+	out := make([][2]float64, len(positions))
+	var offset, prev float64
+	for i, pos := range positions {
+		if len(pos) < 2 {
+			return nil, fmt.Errorf("position has %d coordinates, expected at least 2", len(pos))
+		}
+		lon := pos[0]
+		if i != 0 {
+			for lon+offset-prev > 180 {
+				offset -= 360
+			}
+			for lon+offset-prev < -180 {
+				offset += 360
+			}
+		}
+		prev = lon + offset
+		x, y := fractional(prev, pos[1], z)
+		if pos[1] >= MaxLatitude {
+			y = 0
+		} else if pos[1] <= -MaxLatitude {
+			y = n
+		}
+		out[i] = [2]float64{snap(x), snap(y)}
+	}
+	return out, nil
+}
+
+func (s *tileSet) addPoint(pos []float64) error {
+	if len(pos) < 2 {
+		return fmt.Errorf("point has %d coordinates, expected at least 2", len(pos))
+	}
+	t, _ := FromLonLat(pos[0], pos[1], s.z)
+	s.tiles[t] = struct{}{}
+	return nil
+}
+
+// firstCell returns the cell that a segment starting at v and moving by d
+// enters, which is the cell before v when v is on a tile edge and d < 0
+func firstCell(v, d float64) int {
+	if d < 0 {
+		return int(math.Ceil(v)) - 1
+	}
+	return int(math.Floor(v))
+}
+
+// cellExit returns the parameter at which a segment starting at v and
+// moving by d leaves cell c
+func cellExit(v, d float64, c int) float64 {
+	if d > 0 {
+		return (float64(c+1) - v) / d
+	} else if d < 0 {
+		return (float64(c) - v) / d
+	}
+	return math.Inf(1)
+}
+
+// addSegment adds the tiles whose interiors are crossed by the segment from a
+// to b in tile units. Tiles that the segment only touches at a corner or at
+// its ends are left out.
+func (s *tileSet) addSegment(a, b [2]float64) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	x, y := firstCell(a[0], dx), firstCell(a[1], dy)
+	s.add(x, y)
+	for {
+		tx, ty := cellExit(a[0], dx, x), cellExit(a[1], dy, y)
+		t := math.Min(tx, ty)
+		if t >= 1 {
+			break
+		}
+		// a segment through a corner steps diagonally
+		if tx == t {
+			x += int(math.Copysign(1, dx))
+		}
+		if ty == t {
+			y += int(math.Copysign(1, dy))
+		}
+		s.add(x, y)
+	}
+}
+
+// addLine adds every tile touched by a polyline in tile units. Like
+// FromLonLat, tiles include their west and north edges, except that the east
+// edge of the grid belongs to the easternmost column, so a segment running
+// along a tile edge is placed in the tile to its east or south.
+func (s *tileSet) addLine(line [][2]float64) {
+	n := math.Exp2(float64(s.z))
+	onEdge := func(v float64) float64 {
+		if v != math.Floor(v) {
+			return v
+		} else if v == n {
+			return v - 0.5
+		}
+		return v + 0.5
+	}
+	moved := false
+	for i := 0; i+1 < len(line); i++ {
+		a, b := line[i], line[i+1]
+		if a == b {
+			continue
+		}
+		moved = true
+		if a[0] == b[0] {
+			a[0] = onEdge(a[0])
+			b[0] = a[0]
+		}
+		if a[1] == b[1] {
+			a[1] = onEdge(a[1])
+			b[1] = a[1]
+		}
+		s.addSegment(a, b)
+	}
+	if !moved && len(line) != 0 {
+		s.add(int(math.Floor(onEdge(line[0][0]))), int(math.Floor(onEdge(line[0][1]))))
+	}
+}
+
+// addPolygon adds the tiles whose interiors meet a polygon in tile units: the
+// tiles crossed by its boundary, and the tiles whose centres fall inside it.
+// Edges that run along tile edges add no tiles of their own, so a polygon
+// does not spill into the neighbours of tiles it only borders.
+func (s *tileSet) addPolygon(rings [][][2]float64) {
+	ymin, ymax := math.Inf(1), math.Inf(-1)
+	for _, ring := range rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[j], ring[i]
+			if a == b || (a[0] == b[0] && a[0] == math.Floor(a[0])) ||
+				(a[1] == b[1] && a[1] == math.Floor(a[1])) {
+				continue
+			}
+			s.addSegment(a, b)
+		}
+		for _, p := range ring {
+			ymin = math.Min(ymin, p[1])
+			ymax = math.Max(ymax, p[1])
+		}
+	}
+
+	for y := int(math.Floor(ymin)); y <= int(math.Floor(ymax)); y++ {
+		yc := float64(y) + 0.5
+		crossings := []float64{}
+		for _, ring := range rings {
+			for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+				a, b := ring[j], ring[i]
+				if (a[1] > yc) != (b[1] > yc) {
+					crossings = append(crossings, a[0]+(yc-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+				}
+			}
+		}
+		sort.Float64s(crossings)
+		for k := 0; k+1 < len(crossings); k += 2 {
+			x0 := int(math.Ceil(crossings[k] - 0.5))
+			x1 := int(math.Floor(crossings[k+1] - 0.5))
+			for x := x0; x <= x1; x++ {
+				s.add(x, y)
+			}
+		}
+	}
+}
+
+// PointCover returns the tile at zoom z that contains a Point
+func PointCover(g *geojson.Point, z int) ([]Tile, error) {
+	s, err := newTileSet(z)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.addPoint(g.Coordinates); err != nil {
+		return nil, err
+	}
+	return s.sorted(), nil
+}
+
+// LineStringCover returns the tiles at zoom z touched by a LineString
+func LineStringCover(g *geojson.LineString, z int) ([]Tile, error) {
+	s, err := newTileSet(z)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.addPositions(g.Coordinates); err != nil {
+		return nil, err
+	}
+	return s.sorted(), nil
+}
+
+// PolygonCover returns the tiles at zoom z that intersect a Polygon,
+// accounting for holes
+func PolygonCover(g *geojson.Polygon, z int) ([]Tile, error) {
+	s, err := newTileSet(z)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.addRings(g.Coordinates); err != nil {
+		return nil, err
+	}
+	return s.sorted(), nil
+}
+
+// projectRings projects the rings of a polygon, keeping holes in the same
+// longitude frame as the shell. A ring that runs around the globe is closed
+// along the edge of the grid at the pole it encloses, which following the
+// right-hand rule as CutAntimeridian does is the north pole for a shell that
+// runs eastward and the south pole for one that runs westward.
+func projectRings(rings [][][]float64, z int) ([][][2]float64, error) {
+	n := math.Exp2(float64(z))
+	out := make([][][2]float64, len(rings))
+	for i, ring := range rings {
+		r, err := project(ring, z)
+		if err != nil {
+			return nil, err
+		}
+		if k := len(r); k != 0 {
+			first, last := r[0], r[k-1]
+			net := last[0] + math.Remainder(first[0]-last[0], n) - first[0]
+			if math.Abs(net) > n/2 {
+				pole := n
+				if (net > 0) == (i == 0) {
+					pole = 0
+				}
+				r = append(r, [2]float64{first[0] + net, first[1]},
+					[2]float64{first[0] + net, pole}, [2]float64{first[0], pole}, first)
+			}
+		}
+		out[i] = r
+		if i != 0 && len(r) != 0 && len(out[0]) != 0 {
+			// shift the hole by whole turns so that it starts near the shell
+			shift := math.Round((out[0][0][0]-r[0][0])/n) * n
+			for k := range r {
+				r[k][0] += shift
+			}
+		}
+	}
+	return out, nil
+}
+
+// addPositions adds the tiles touched by a polyline of longitude and
+// latitude positions
+func (s *tileSet) addPositions(positions [][]float64) error {
+	line, err := project(positions, s.z)
+	if err != nil {
+		return err
+	}
+	s.addLine(line)
+	return nil
+}
+
+// addRings adds the tiles that intersect a polygon of longitude and latitude
+// positions
+func (s *tileSet) addRings(rings [][][]float64) error {
+	projected, err := projectRings(rings, s.z)
+	if err != nil {
+		return err
+	}
+	s.addPolygon(projected)
+	return nil
+}
+
+// Cover returns the tiles at zoom z that intersect any geometry, Feature or
+// FeatureCollection
+func Cover(g *geojson.Geo, z int) ([]Tile, error) {
+	s, err := newTileSet(z)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.addGeo(g); err != nil {
+		return nil, err
+	}
+	return s.sorted(), nil
+}
+
+func (s *tileSet) addGeo(g *geojson.Geo) error {
+	switch g.Type {
+	case "Point":
+		return s.addPoint(g.Point.Coordinates)
+	case "LineString":
+		return s.addPositions(g.LineString.Coordinates)
+	case "Polygon":
+		return s.addRings(g.Polygon.Coordinates)
+	case "MultiPoint":
+		for _, pos := range g.MultiPoint.Coordinates {
+			if err := s.addPoint(pos); err != nil {
+				return err
+			}
+		}
+	case "MultiLineString":
+		for _, line := range g.MultiLineString.Coordinates {
+			if err := s.addPositions(line); err != nil {
+				return err
+			}
+		}
+	case "MultiPolygon":
+		for _, poly := range g.MultiPolygon.Coordinates {
+			if err := s.addRings(poly); err != nil {
+				return err
+			}
+		}
+	case "GeometryCollection":
+		for _, geom := range g.GeometryCollection.Geometries {
+			if err := s.addGeo(geom); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		return s.addGeo(&g.Feature.Geometry)
+	case "FeatureCollection":
+		for i := range g.FeatureCollection.Features {
+			if err := s.addGeo(&g.FeatureCollection.Features[i].Geometry); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unhandled type: '%s'", g.Type)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package tiles
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+func TestPointCover(t *testing.T) {
+	tiles, err := PointCover(&geojson.Point{Coordinates: []float64{8.5417, 47.3769}}, 10)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(tiles) != 1 || tiles[0] != (Tile{10, 536, 358}) {
+		t.Fail()
+	}
+	for _, coords := range [][]float64{nil, {8.5417}} {
+		if _, err = PointCover(&geojson.Point{Coordinates: coords}, 10); err == nil {
+			fmt.Println("expected an error for", coords)
+			t.Fail()
+		}
+	}
+	mp := &geojson.Geo{Type: "MultiPoint", MultiPoint: &geojson.MultiPoint{Coordinates: [][]float64{{8.5417, 47.3769}, {8.5417}}}}
+	if _, err = Cover(mp, 10); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLineStringCover(t *testing.T) {
+	// a horizontal line along the equator row at zoom 2
+	ls := &geojson.LineString{Coordinates: [][]float64{{-170, 10}, {-10, 10}}}
+	tiles, err := LineStringCover(ls, 2)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := "[2/0/1 2/1/1]"
+	if fmt.Sprint(tiles) != expected {
+		fmt.Println("recieved    ", tiles)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestLineStringCoverDiagonal(t *testing.T) {
+	// a diagonal touches only the tiles it passes through, unlike a bbox
+	ls := &geojson.LineString{Coordinates: [][]float64{{-179, 84}, {179, -84}}}
+	tiles, err := LineStringCover(ls, 2)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(tiles) >= 16 {
+		fmt.Println("recieved", tiles)
+		t.Fail()
+	}
+}
+
+func TestLineStringCoverAntimeridian(t *testing.T) {
+	// the short way across the antimeridian touches the first and last columns
+	ls := &geojson.LineString{Coordinates: [][]float64{{170, 10}, {-170, 10}}}
+	tiles, err := LineStringCover(ls, 2)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := "[2/0/1 2/3/1]"
+	if fmt.Sprint(tiles) != expected {
+		fmt.Println("recieved    ", tiles)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
+
+func TestLineStringCoverEdges(t *testing.T) {
+	// a line ending on the antimeridian stays in the easternmost column, and
+	// one running along the equator takes the row to its south
+	for _, c := range []struct {
+		coords   [][]float64
+		expected string
+	}{
+		{[][]float64{{170, 10}, {180, 10}}, "[2/3/1]"},
+		{[][]float64{{10, 0}, {100, 0}}, "[2/2/2 2/3/2]"},
+		{[][]float64{{180, 10}, {180, 20}}, "[2/3/1]"},
+	} {
+		tiles, err := LineStringCover(&geojson.LineString{Coordinates: c.coords}, 2)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if fmt.Sprint(tiles) != c.expected {
+			fmt.Println("recieved    ", tiles)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+	}
+
+	if _, err := LineStringCover(&geojson.LineString{Coordinates: [][]float64{{1, 2}, {3}}}, 2); err == nil {
+		t.Fail()
+	}
+	poly := &geojson.Polygon{Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1}, {0, 0}}}}
+	if _, err := PolygonCover(poly, 2); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPolygonCover(t *testing.T) {
+	// a large square covering a 5x5 block of zoom 4 tiles; the centre tile is
+	// only reachable by filling the interior
+	poly := Tile{4, 5, 5}.Polygon()
+	bb0 := Tile{4, 3, 3}.Bbox()
+	bb1 := Tile{4, 7, 7}.Bbox()
+	poly.Coordinates[0] = [][]float64{
+		{bb0[0] + 0.1, bb1[1] + 0.1}, {bb1[2] - 0.1, bb1[1] + 0.1},
+		{bb1[2] - 0.1, bb0[3] - 0.1}, {bb0[0] + 0.1, bb0[3] - 0.1},
+		{bb0[0] + 0.1, bb1[1] + 0.1},
+	}
+	tiles, err := PolygonCover(poly, 4)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(tiles) != 25 {
+		fmt.Println("recieved", tiles)
+		t.Fail()
+	}
+
+	// a hole the size of the centre tile removes it from the cover
+	hb := Tile{4, 5, 5}.Bbox()
+	poly.Coordinates = append(poly.Coordinates, [][]float64{
+		{hb[0] - 0.01, hb[1] - 0.01}, {hb[0] - 0.01, hb[3] + 0.01},
+		{hb[2] + 0.01, hb[3] + 0.01}, {hb[2] + 0.01, hb[1] - 0.01},
+		{hb[0] - 0.01, hb[1] - 0.01},
+	})
+	tiles, err = PolygonCover(poly, 4)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if len(tiles) != 24 {
+		fmt.Println("recieved", tiles)
+		t.Fail()
+	}
+	for _, tile := range tiles {
+		if tile == (Tile{4, 5, 5}) {
+			t.Fail()
+		}
+	}
+}
+
+func TestCoverGeo(t *testing.T) {
+	geo := &geojson.Geo{Type: "Feature", Feature: &geojson.Feature{
+		Geometry: geojson.Geo{Type: "MultiPoint", MultiPoint: &geojson.MultiPoint{
+			Coordinates: [][]float64{{-100, 40}, {100, -40}, {-100, 40}},
+		}},
+	}}
+	tiles, err := Cover(geo, 1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected := "[1/0/0 1/1/1]"
+	if fmt.Sprint(tiles) != expected {
+		fmt.Println("recieved    ", tiles)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	if _, err = Cover(&geojson.Geo{Type: "Unknown"}, 1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPolygonCoverEdges(t *testing.T) {
+	// polygons bounded by tile edges do not spill into their neighbours
+	for _, c := range []struct {
+		poly     *geojson.Polygon
+		z        int
+		expected string
+	}{
+		{Tile{2, 3, 1}.Polygon(), 2, "[2/3/1]"},
+		{Tile{5, 31, 0}.Polygon(), 5, "[5/31/0]"},
+		{&geojson.Polygon{Coordinates: [][][]float64{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}, 2, "[2/2/1]"},
+	} {
+		tiles, err := PolygonCover(c.poly, c.z)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if fmt.Sprint(tiles) != c.expected {
+			fmt.Println("recieved    ", tiles)
+			fmt.Println("but expected", c.expected)
+			t.Fail()
+		}
+	}
+}
+
+func TestCoverPole(t *testing.T) {
+	// a ring around the north pole cut at the antimeridian covers the whole
+	// top row, as does the uncut ring
+	ring := [][]float64{{0, 70}, {90, 70}, {180, 70}, {-90, 70}, {0, 70}}
+	cut, err := (&geojson.Polygon{Coordinates: [][][]float64{ring}}).CutAntimeridian()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncut := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{ring}}}
+	expected := "[3/0/0 3/1/0 3/2/0 3/3/0 3/4/0 3/5/0 3/6/0 3/7/0 3/0/1 3/1/1 3/2/1 3/3/1 3/4/1 3/5/1 3/6/1 3/7/1]"
+	for _, g := range []*geojson.Geo{cut, uncut} {
+		tiles, err := Cover(g, 3)
+		if err != nil {
+			fmt.Println(err)
+			t.Error()
+		}
+		if fmt.Sprint(tiles) != expected {
+			fmt.Println("recieved    ", tiles)
+			fmt.Println("but expected", expected)
+			t.Fail()
+		}
+	}
+
+	// running westward, the ring contains the south pole instead
+	ring = [][]float64{{0, -70}, {-90, -70}, {180, -70}, {90, -70}, {0, -70}}
+	tiles, err := PolygonCover(&geojson.Polygon{Coordinates: [][][]float64{ring}}, 1)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	expected = "[1/0/1 1/1/1]"
+	if fmt.Sprint(tiles) != expected {
+		fmt.Println("recieved    ", tiles)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+}
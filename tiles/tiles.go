@@ -0,0 +1,168 @@
+// Package tiles implements conversions between geographic positions and
+// slippy-map (z/x/y) tiles, and computes the tiles covering GeoJSON geometries
+package tiles
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// MaxLatitude is the latitude limit of the Web Mercator projection, beyond
+// which positions are clamped
+const MaxLatitude = 85.0511287798066
+
+// MaxZoom is the deepest zoom level supported
+const MaxZoom = 30
+
+// Tile identifies a slippy-map tile by zoom level, column and row
+type Tile struct {
+	Z int
+	X int
+	Y int
+}
+
+func checkZoom(z int) error {
+	if z < 0 || z > MaxZoom {
+		return fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+	return nil
+}
+
+// fractional returns the position of lon, lat in tile units at zoom z,
+// without wrapping the longitude
+func fractional(lon, lat float64, z int) (float64, float64) {
+	n := math.Exp2(float64(z))
+	lat = math.Max(-MaxLatitude, math.Min(MaxLatitude, lat))
+	phi := lat * math.Pi / 180.0
+	x := (lon + 180.0) / 360.0 * n
+	y := (1.0 - math.Log(math.Tan(phi)+1.0/math.Cos(phi))/math.Pi) / 2.0 * n
+	return x, y
+}
+
+// normalize wraps the column around the antimeridian and clamps the row to
+// the valid range
+func (t Tile) normalize() Tile {
+	n := 1 << uint(t.Z)
+	t.X = ((t.X % n) + n) % n
+	if t.Y < 0 {
+		t.Y = 0
+	} else if t.Y >= n {
+		t.Y = n - 1
+	}
+	return t
+}
+
+// FromLonLat returns the tile at zoom z containing a position. Latitudes are
+// clamped to the Web Mercator limits and longitudes are wrapped to
+// [-180, 180), except that 180 itself is placed in the easternmost column.
+func FromLonLat(lon, lat float64, z int) (Tile, error) {
+	if err := checkZoom(z); err != nil {
+		return Tile{}, err
+	}
+	if lon != 180 {
+		lon = math.Mod(lon+180.0, 360.0)
+		if lon < 0 {
+			lon += 360.0
+		}
+		lon -= 180.0
+	}
+	x, y := fractional(lon, lat, z)
+	n := 1 << uint(z)
+	tx := int(math.Floor(x))
+	if tx >= n {
+		tx = n - 1
+	}
+	return Tile{z, tx, int(math.Floor(y))}.normalize(), nil
+}
+
+// LonLat returns the position of the north-west corner of the tile
+func (t Tile) LonLat() (float64, float64) {
+	n := math.Exp2(float64(t.Z))
+	lon := float64(t.X)/n*360.0 - 180.0
+	lat := math.Atan(math.Sinh(math.Pi*(1.0-2.0*float64(t.Y)/n))) * 180.0 / math.Pi
+	return lon, lat
+}
+
+// Bbox returns the extent of the tile as [xmin, ymin, xmax, ymax]
+func (t Tile) Bbox() [4]float64 {
+	west, north := t.LonLat()
+	east, south := Tile{t.Z, t.X + 1, t.Y + 1}.LonLat()
+	return [4]float64{west, south, east, north}
+}
+
+// Polygon returns the tile extent as a counter-clockwise Polygon
+func (t Tile) Polygon() *geojson.Polygon {
+	bb := t.Bbox()
+	return &geojson.Polygon{Coordinates: [][][]float64{{
+		{bb[0], bb[1]}, {bb[2], bb[1]}, {bb[2], bb[3]}, {bb[0], bb[3]}, {bb[0], bb[1]},
+	}}}
+}
+
+// Parent returns the tile at the next lower zoom level that contains t
+func (t Tile) Parent() (Tile, error) {
+	if t.Z == 0 {
+		return t, errors.New("zoom 0 tile has no parent")
+	}
+	return Tile{t.Z - 1, t.X >> 1, t.Y >> 1}, nil
+}
+
+// Children returns the four tiles at the next zoom level that make up t, in
+// the order NW, NE, SW, SE
+func (t Tile) Children() ([]Tile, error) {
+	if t.Z >= MaxZoom {
+		return nil, fmt.Errorf("zoom %d tile has no children", t.Z)
+	}
+	x, y, z := t.X<<1, t.Y<<1, t.Z+1
+	return []Tile{{z, x, y}, {z, x + 1, y}, {z, x, y + 1}, {z, x + 1, y + 1}}, nil
+}
+
+// Quadkey returns the Bing Maps quadkey of the tile
+func (t Tile) Quadkey() string {
+	var sb strings.Builder
+	for i := t.Z; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		sb.WriteByte(digit)
+	}
+	return sb.String()
+}
+
+// FromQuadkey returns the tile referred to by a quadkey
+func FromQuadkey(quadkey string) (Tile, error) {
+	t := Tile{Z: len(quadkey)}
+	if err := checkZoom(t.Z); err != nil {
+		return t, err
+	}
+	for _, r := range quadkey {
+		t.X <<= 1
+		t.Y <<= 1
+		switch r {
+		case '0':
+		case '1':
+			t.X |= 1
+		case '2':
+			t.Y |= 1
+		case '3':
+			t.X |= 1
+			t.Y |= 1
+		default:
+			return Tile{}, fmt.Errorf("invalid quadkey digit: '%c'", r)
+		}
+	}
+	return t, nil
+}
+
+// String returns the tile in the format 'z/x/y'
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
@@ -0,0 +1,101 @@
+package tiles
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFromLonLat(t *testing.T) {
+	tile, err := FromLonLat(8.5417, 47.3769, 10)
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if tile != (Tile{10, 536, 358}) {
+		fmt.Println("recieved    ", tile)
+		fmt.Println("but expected", Tile{10, 536, 358})
+		t.Fail()
+	}
+
+	// positions beyond the Web Mercator limits are clamped
+	tile, _ = FromLonLat(0, 89.9, 4)
+	if tile.Y != 0 {
+		t.Fail()
+	}
+	tile, _ = FromLonLat(0, -90, 4)
+	if tile.Y != 15 {
+		t.Fail()
+	}
+
+	// longitudes wrap, apart from 180 which stays in the last column
+	tile, _ = FromLonLat(190, 0, 2)
+	if tile.X != 0 {
+		t.Fail()
+	}
+	tile, _ = FromLonLat(180, 0, 2)
+	if tile.X != 3 {
+		t.Fail()
+	}
+
+	if _, err = FromLonLat(0, 0, 31); err == nil {
+		t.Fail()
+	}
+}
+
+func TestTileBbox(t *testing.T) {
+	bb := Tile{1, 0, 0}.Bbox()
+	expected := [4]float64{-180, 0, 0, MaxLatitude}
+	for i := range bb {
+		if math.Abs(bb[i]-expected[i]) > 1e-9 {
+			fmt.Println("recieved    ", bb)
+			fmt.Println("but expected", expected)
+			t.Fail()
+			break
+		}
+	}
+	ring := Tile{1, 0, 0}.Polygon().Coordinates[0]
+	if len(ring) != 5 || ring[0][0] != ring[4][0] || ring[0][1] != ring[4][1] {
+		t.Fail()
+	}
+}
+
+func TestQuadkey(t *testing.T) {
+	tile := Tile{3, 3, 5}
+	if tile.Quadkey() != "213" {
+		fmt.Println("recieved", tile.Quadkey())
+		t.Fail()
+	}
+	back, err := FromQuadkey("213")
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	if back != tile {
+		t.Fail()
+	}
+	if _, err = FromQuadkey("214"); err == nil {
+		t.Fail()
+	}
+	if (Tile{}).Quadkey() != "" {
+		t.Fail()
+	}
+}
+
+func TestParentChildren(t *testing.T) {
+	tile := Tile{3, 3, 5}
+	children, err := tile.Children()
+	if err != nil {
+		fmt.Println(err)
+		t.Error()
+	}
+	for _, child := range children {
+		parent, err := child.Parent()
+		if err != nil || parent != tile {
+			t.Fail()
+		}
+	}
+	if _, err = (Tile{}).Parent(); err == nil {
+		t.Fail()
+	}
+}
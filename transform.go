@@ -0,0 +1,298 @@
+/* functions for transforming the positions of geometries */
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Affine is a two dimensional affine transformation {a, b, xoff, d, e,
+// yoff}, which maps (x, y) to (a*x + b*y + xoff, d*x + e*y + yoff). The
+// six parameters are those of a world file, in the order A, B, C, D, E, F.
+type Affine [6]float64
+
+// Identity is the Affine transformation that leaves positions unchanged
+var Identity = Affine{1, 0, 0, 0, 1, 0}
+
+// Translation returns an Affine transformation that moves positions by dx
+// and dy
+func Translation(dx, dy float64) Affine {
+	return Affine{1, 0, dx, 0, 1, dy}
+}
+
+// Scaling returns an Affine transformation that scales positions by sx and
+// sy about the origin
+func Scaling(sx, sy float64) Affine {
+	return Affine{sx, 0, 0, 0, sy, 0}
+}
+
+// Rotation returns an Affine transformation that rotates positions
+// counter-clockwise about the origin by an angle in degrees
+func Rotation(angle float64) Affine {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return Affine{cos, -sin, 0, sin, cos, 0}
+}
+
+// Skewing returns an Affine transformation that shears positions along the
+// x and y axes by angles in degrees
+func Skewing(xAngle, yAngle float64) Affine {
+	return Affine{1, math.Tan(xAngle * math.Pi / 180), 0, math.Tan(yAngle * math.Pi / 180), 1, 0}
+}
+
+// About returns the transformation applied about the origin (x, y) rather
+// than (0, 0), such as to rotate or scale a geometry about its centroid
+func (m Affine) About(x, y float64) Affine {
+	return Translation(-x, -y).Then(m).Then(Translation(x, y))
+}
+
+// Then returns the transformation that applies m followed by next
+func (m Affine) Then(next Affine) Affine {
+	return Affine{
+		next[0]*m[0] + next[1]*m[3],
+		next[0]*m[1] + next[1]*m[4],
+		next[0]*m[2] + next[1]*m[5] + next[2],
+		next[3]*m[0] + next[4]*m[3],
+		next[3]*m[1] + next[4]*m[4],
+		next[3]*m[2] + next[4]*m[5] + next[5],
+	}
+}
+
+// Inverse returns the transformation that undoes m, or an error when m
+// collapses the plane onto a line or point
+func (m Affine) Inverse() (Affine, error) {
+	det := m[0]*m[4] - m[1]*m[3]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, errors.New("affine transformation is not invertible")
+	}
+	a, b, d, e := m[4]/det, -m[1]/det, -m[3]/det, m[0]/det
+	return Affine{a, b, -(a*m[2] + b*m[5]), d, e, -(d*m[2] + e*m[5])}, nil
+}
+
+// Apply returns a transformed copy of a position. Coordinates beyond the
+// second are copied unchanged.
+func (m Affine) Apply(pos []float64) []float64 {
+	out := append([]float64{}, pos...)
+	if len(pos) >= 2 {
+		out[0] = m[0]*pos[0] + m[1]*pos[1] + m[2]
+		out[1] = m[3]*pos[0] + m[4]*pos[1] + m[5]
+	}
+	return out
+}
+
+func mapPosition(pos []float64, f func([]float64) []float64) []float64 {
+	return f(append([]float64{}, pos...))
+}
+
+func mapPath(path [][]float64, f func([]float64) []float64) [][]float64 {
+	out := make([][]float64, len(path))
+	for i, pos := range path {
+		out[i] = mapPosition(pos, f)
+	}
+	return out
+}
+
+func mapPaths(paths [][][]float64, f func([]float64) []float64) [][][]float64 {
+	out := make([][][]float64, len(paths))
+	for i, path := range paths {
+		out[i] = mapPath(path, f)
+	}
+	return out
+}
+
+func mapGeo(g *Geo, f func([]float64) []float64) (*Geo, error) {
+	switch g.Type {
+	case "Point":
+		return &Geo{Type: "Point", Point: g.Point.MapPositions(f)}, nil
+	case "MultiPoint":
+		return &Geo{Type: "MultiPoint", MultiPoint: g.MultiPoint.MapPositions(f)}, nil
+	case "LineString":
+		return &Geo{Type: "LineString", LineString: g.LineString.MapPositions(f)}, nil
+	case "MultiLineString":
+		return &Geo{Type: "MultiLineString", MultiLineString: g.MultiLineString.MapPositions(f)}, nil
+	case "Polygon":
+		return &Geo{Type: "Polygon", Polygon: g.Polygon.MapPositions(f)}, nil
+	case "MultiPolygon":
+		return &Geo{Type: "MultiPolygon", MultiPolygon: g.MultiPolygon.MapPositions(f)}, nil
+	case "GeometryCollection":
+		coll := &GeometryCollection{g.GeometryCollection.CRSReferencable,
+			make([]*Geo, len(g.GeometryCollection.Geometries))}
+		for i, member := range g.GeometryCollection.Geometries {
+			mapped, err := mapGeo(member, f)
+			if err != nil {
+				return nil, err
+			}
+			coll.Geometries[i] = mapped
+		}
+		return &Geo{Type: "GeometryCollection", GeometryCollection: coll}, nil
+	case "Feature":
+		mapped, err := mapGeo(&g.Feature.Geometry, f)
+		if err != nil {
+			return nil, err
+		}
+		feature := *g.Feature
+		feature.Geometry = *mapped
+		return &Geo{Type: "Feature", Feature: &feature}, nil
+	case "FeatureCollection":
+		coll := &FeatureCollection{g.FeatureCollection.CRSReferencable,
+			make([]Feature, len(g.FeatureCollection.Features))}
+		for i, feature := range g.FeatureCollection.Features {
+			mapped, err := mapGeo(&feature.Geometry, f)
+			if err != nil {
+				return nil, err
+			}
+			feature.Geometry = *mapped
+			coll.Features[i] = feature
+		}
+		return &Geo{Type: "FeatureCollection", FeatureCollection: coll}, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
+// MapPositions returns a copy of the Point with its position replaced by
+// the result of f. See Geo.MapPositions.
+func (g *Point) MapPositions(f func([]float64) []float64) *Point {
+	return &Point{g.CRSReferencable, mapPosition(g.Coordinates, f)}
+}
+
+// MapPositions returns a copy of the LineString with every position
+// replaced by the result of f. See Geo.MapPositions.
+func (g *LineString) MapPositions(f func([]float64) []float64) *LineString {
+	return &LineString{g.CRSReferencable, mapPath(g.Coordinates, f)}
+}
+
+// MapPositions returns a copy of the Polygon with every position replaced
+// by the result of f. See Geo.MapPositions.
+func (g *Polygon) MapPositions(f func([]float64) []float64) *Polygon {
+	return &Polygon{g.CRSReferencable, mapPaths(g.Coordinates, f)}
+}
+
+// MapPositions returns a copy of the MultiPoint with every position
+// replaced by the result of f. See Geo.MapPositions.
+func (g *MultiPoint) MapPositions(f func([]float64) []float64) *MultiPoint {
+	return &MultiPoint{g.CRSReferencable, mapPath(g.Coordinates, f)}
+}
+
+// MapPositions returns a copy of the MultiLineString with every position
+// replaced by the result of f. See Geo.MapPositions.
+func (g *MultiLineString) MapPositions(f func([]float64) []float64) *MultiLineString {
+	return &MultiLineString{g.CRSReferencable, mapPaths(g.Coordinates, f)}
+}
+
+// MapPositions returns a copy of the MultiPolygon with every position
+// replaced by the result of f. See Geo.MapPositions.
+func (g *MultiPolygon) MapPositions(f func([]float64) []float64) *MultiPolygon {
+	coords := make([][][][]float64, len(g.Coordinates))
+	for i, poly := range g.Coordinates {
+		coords[i] = mapPaths(poly, f)
+	}
+	return &MultiPolygon{g.CRSReferencable, coords}
+}
+
+// MapPositions returns a copy of the GeometryCollection with every position
+// of every member replaced by the result of f. See Geo.MapPositions.
+func (coll *GeometryCollection) MapPositions(f func([]float64) []float64) (*GeometryCollection, error) {
+	mapped, err := mapGeo(&Geo{Type: "GeometryCollection", GeometryCollection: coll}, f)
+	if err != nil {
+		return nil, err
+	}
+	return mapped.GeometryCollection, nil
+}
+
+// MapPositions returns a copy of the Feature with every position of its
+// geometry replaced by the result of f. See Geo.MapPositions.
+func (f *Feature) MapPositions(fn func([]float64) []float64) (*Feature, error) {
+	mapped, err := mapGeo(&Geo{Type: "Feature", Feature: f}, fn)
+	if err != nil {
+		return nil, err
+	}
+	return mapped.Feature, nil
+}
+
+// MapPositions returns a copy of the FeatureCollection with every position
+// of every Feature replaced by the result of f. See Geo.MapPositions.
+func (coll *FeatureCollection) MapPositions(f func([]float64) []float64) (*FeatureCollection, error) {
+	mapped, err := mapGeo(&Geo{Type: "FeatureCollection", FeatureCollection: coll}, f)
+	if err != nil {
+		return nil, err
+	}
+	return mapped.FeatureCollection, nil
+}
+
+// MapPositions returns a copy of any geometry, Feature or FeatureCollection
+// with every position replaced by the result of calling f on it. f receives
+// a copy of each position, so it may modify and return its argument, and it
+// may change the number of coordinates, such as to drop or add a z
+// coordinate. Properties, identifiers and CRS members are kept; when f
+// changes the coordinate reference system, the CRS of the result should be
+// updated to match.
+func (g *Geo) MapPositions(f func([]float64) []float64) (*Geo, error) {
+	return mapGeo(g, f)
+}
+
+// Transform returns a copy of the Point with an Affine transformation
+// applied. See Geo.Transform.
+func (g *Point) Transform(m Affine) *Point {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the LineString with an Affine transformation
+// applied. See Geo.Transform.
+func (g *LineString) Transform(m Affine) *LineString {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the Polygon with an Affine transformation
+// applied. See Geo.Transform.
+func (g *Polygon) Transform(m Affine) *Polygon {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the MultiPoint with an Affine transformation
+// applied. See Geo.Transform.
+func (g *MultiPoint) Transform(m Affine) *MultiPoint {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the MultiLineString with an Affine
+// transformation applied. See Geo.Transform.
+func (g *MultiLineString) Transform(m Affine) *MultiLineString {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the MultiPolygon with an Affine
+// transformation applied. See Geo.Transform.
+func (g *MultiPolygon) Transform(m Affine) *MultiPolygon {
+	return g.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the GeometryCollection with an Affine
+// transformation applied to every member. See Geo.Transform.
+func (coll *GeometryCollection) Transform(m Affine) (*GeometryCollection, error) {
+	return coll.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the Feature with an Affine transformation
+// applied to its geometry. See Geo.Transform.
+func (f *Feature) Transform(m Affine) (*Feature, error) {
+	return f.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of the FeatureCollection with an Affine
+// transformation applied to every Feature. See Geo.Transform.
+func (coll *FeatureCollection) Transform(m Affine) (*FeatureCollection, error) {
+	return coll.MapPositions(m.Apply)
+}
+
+// Transform returns a copy of any geometry, Feature or FeatureCollection
+// with an Affine transformation applied to the x and y coordinates of every
+// position. Transformations are combined with Then, so that georeferencing
+// a drawing might be
+//
+//	g.Transform(Scaling(0.001, 0.001).Then(Rotation(theta)).Then(Translation(x0, y0)))
+//
+// A transformation that reflects positions also reverses the orientation of
+// rings.
+func (g *Geo) Transform(m Affine) (*Geo, error) {
+	return g.MapPositions(m.Apply)
+}
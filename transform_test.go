@@ -0,0 +1,115 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func closePositions(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if math.Abs(a[i][j]-b[i][j]) > 1e-12 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestAffine(t *testing.T) {
+	m := Scaling(2, 3).Then(Rotation(90)).Then(Translation(10, 20))
+	pos := m.Apply([]float64{1, 1, 5})
+	expected := []float64{7, 22, 5}
+	if !closePositions([][]float64{pos}, [][]float64{expected}) {
+		fmt.Println("recieved    ", pos)
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+
+	inv, err := m.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	back := inv.Apply(pos)
+	if !closePositions([][]float64{back}, [][]float64{{1, 1, 5}}) {
+		fmt.Println("recieved    ", back)
+		t.Fail()
+	}
+	if _, err = Scaling(1, 0).Inverse(); err == nil {
+		t.Fail()
+	}
+
+	// rotating about a point leaves it fixed
+	pos = Rotation(180).About(1, 1).Apply([]float64{2, 1})
+	if !closePositions([][]float64{pos}, [][]float64{{0, 1}}) {
+		fmt.Println("recieved    ", pos)
+		t.Fail()
+	}
+
+	pos = Skewing(45, 0).Apply([]float64{0, 2})
+	if !closePositions([][]float64{pos}, [][]float64{{2, 2}}) {
+		fmt.Println("recieved    ", pos)
+		t.Fail()
+	}
+}
+
+func TestTransformPolygon(t *testing.T) {
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}
+	moved := poly.Transform(Translation(2, 3))
+	expected := [][]float64{{2, 3}, {3, 3}, {3, 4}, {2, 4}, {2, 3}}
+	if !closePositions(moved.Coordinates[0], expected) {
+		fmt.Println("recieved    ", moved.Coordinates[0])
+		fmt.Println("but expected", expected)
+		t.Fail()
+	}
+	// the input is unchanged
+	if poly.Coordinates[0][1][0] != 1 {
+		t.Fail()
+	}
+	if scaled := poly.Transform(Scaling(2, 2)); scaled.Area() != 4 {
+		fmt.Println("recieved    ", scaled.Area())
+		t.Fail()
+	}
+}
+
+func TestMapPositionsFeatureCollection(t *testing.T) {
+	fc := &FeatureCollection{Features: []Feature{
+		{ID: "a", Properties: map[string]interface{}{"name": "a"},
+			Geometry: Geo{Type: "Point", Point: &Point{Coordinates: []float64{1, 2, 3}}}},
+		{ID: "b", Geometry: Geo{Type: "GeometryCollection", GeometryCollection: &GeometryCollection{
+			Geometries: []*Geo{{Type: "LineString", LineString: &LineString{Coordinates: [][]float64{{0, 0, 1}, {1, 1, 2}}}}},
+		}}},
+	}}
+	// drop the z coordinate and swap axes, modifying the argument in place
+	flat, err := fc.MapPositions(func(pos []float64) []float64 {
+		pos[0], pos[1] = pos[1], pos[0]
+		return pos[:2]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(flat.Features[0].Geometry.Point.Coordinates) != "[2 1]" || flat.Features[0].Properties["name"] != "a" {
+		fmt.Println("recieved    ", flat.Features[0])
+		t.Fail()
+	}
+	line := flat.Features[1].Geometry.GeometryCollection.Geometries[0].LineString
+	if fmt.Sprint(line.Coordinates) != "[[0 0] [1 1]]" || flat.Features[1].ID != "b" {
+		fmt.Println("recieved    ", line.Coordinates)
+		t.Fail()
+	}
+	if fmt.Sprint(fc.Features[0].Geometry.Point.Coordinates) != "[1 2 3]" {
+		fmt.Println("recieved    ", fc.Features[0].Geometry.Point.Coordinates)
+		t.Fail()
+	}
+
+	if _, err = (&Geo{Type: "Circle"}).Transform(Identity); err == nil {
+		t.Fail()
+	}
+}
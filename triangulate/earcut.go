@@ -0,0 +1,671 @@
+package triangulate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// Mesh is a triangulation laid out for upload to vertex and index buffers,
+// as used by WebGL's drawElements with gl.TRIANGLES
+type Mesh struct {
+	// Vertices holds the x and y coordinates of each vertex in turn
+	Vertices []float64
+	// Indices holds three vertex indices per counter-clockwise triangle
+	Indices []uint32
+}
+
+// GeometryCollection returns the triangles of the Mesh as closed,
+// counter-clockwise Polygons
+func (m *Mesh) GeometryCollection() *geojson.GeometryCollection {
+	coll := &geojson.GeometryCollection{Geometries: make([]*geojson.Geo, 0, len(m.Indices)/3)}
+	vertex := func(i uint32) []float64 {
+		return []float64{m.Vertices[2*i], m.Vertices[2*i+1]}
+	}
+	for k := 0; k+2 < len(m.Indices); k += 3 {
+		a, b, c := vertex(m.Indices[k]), vertex(m.Indices[k+1]), vertex(m.Indices[k+2])
+		coll.Geometries = append(coll.Geometries, &geojson.Geo{
+			Type:    "Polygon",
+			Polygon: &geojson.Polygon{Coordinates: [][][]float64{{a, b, c, {a[0], a[1]}}}},
+		})
+	}
+	return coll
+}
+
+// Earcut triangulates a Polygon or MultiPolygon, including holes, by ear
+// clipping with z-order hashing as in the earcut library (Agafonkin,
+// 2016). The vertices of the Mesh are the positions of each ring without
+// its closing position, in order, so that triangles can be related back to
+// the input. Triangulation is fast and handles holes, touching rings and
+// most self-intersections, but does not guarantee triangle quality.
+func Earcut(g *geojson.Geo) (*Mesh, error) {
+	var polygons [][][][]float64
+	switch g.Type {
+	case "Polygon":
+		polygons = [][][][]float64{g.Polygon.Coordinates}
+	case "MultiPolygon":
+		polygons = g.MultiPolygon.Coordinates
+	default:
+		return nil, fmt.Errorf("cannot triangulate a %s", g.Type)
+	}
+
+	mesh := &Mesh{Vertices: []float64{}, Indices: []uint32{}}
+	for _, rings := range polygons {
+		offset := len(mesh.Vertices) / 2
+		var holes []int
+		for i, ring := range rings {
+			n := len(ring)
+			if n > 1 && ring[0][0] == ring[n-1][0] && ring[0][1] == ring[n-1][1] {
+				n--
+			}
+			if i != 0 {
+				holes = append(holes, len(mesh.Vertices)/2-offset)
+			}
+			for _, pos := range ring[:n] {
+				if len(pos) < 2 {
+					return nil, errors.New("positions must have at least two coordinates")
+				}
+				mesh.Vertices = append(mesh.Vertices, pos[0], pos[1])
+			}
+		}
+		for _, i := range earcut(mesh.Vertices[2*offset:], holes) {
+			mesh.Indices = append(mesh.Indices, uint32(offset+i))
+		}
+	}
+	return mesh, nil
+}
+
+// earcutNode is a vertex in a circular doubly linked list of ring vertices,
+// also linked in z-order when hashing is used
+type earcutNode struct {
+	i            int
+	x, y         float64
+	prev, next   *earcutNode
+	z            int32
+	prevZ, nextZ *earcutNode
+	steiner      bool
+}
+
+// earcut triangulates a polygon given as flat x, y coordinates with holes
+// starting at the vertex indices in holes, returning vertex indices
+func earcut(data []float64, holes []int) []int {
+	outerLen := len(data)
+	if len(holes) != 0 {
+		outerLen = 2 * holes[0]
+	}
+	outer := linkedList(data, 0, outerLen, true)
+	var triangles []int
+	if outer == nil || outer.next == outer.prev {
+		return triangles
+	}
+	if len(holes) != 0 {
+		outer = eliminateHoles(data, holes, outer)
+	}
+
+	// hash vertices by z-order for shapes that are not too simple
+	var minX, minY, invSize float64
+	if len(data) > 80*2 {
+		minX, minY = data[0], data[1]
+		maxX, maxY := minX, minY
+		for i := 2; i < outerLen; i += 2 {
+			minX, maxX = math.Min(minX, data[i]), math.Max(maxX, data[i])
+			minY, maxY = math.Min(minY, data[i+1]), math.Max(maxY, data[i+1])
+		}
+		invSize = math.Max(maxX-minX, maxY-minY)
+		if invSize != 0 {
+			invSize = 32767 / invSize
+		}
+	}
+	return earcutLinked(outer, triangles, minX, minY, invSize, 0)
+}
+
+func signedArea(data []float64, start, end int) float64 {
+	var sum float64
+	for i, j := start, end-2; i < end; j, i = i, i+2 {
+		sum += (data[j] - data[i]) * (data[i+1] + data[j+1])
+	}
+	return sum
+}
+
+// linkedList links the vertices of a ring, counter-clockwise if ccw is true
+// and clockwise otherwise
+func linkedList(data []float64, start, end int, ccw bool) *earcutNode {
+	var last *earcutNode
+	if ccw == (signedArea(data, start, end) > 0) {
+		for i := start; i < end; i += 2 {
+			last = insertNode(i/2, data[i], data[i+1], last)
+		}
+	} else {
+		for i := end - 2; i >= start; i -= 2 {
+			last = insertNode(i/2, data[i], data[i+1], last)
+		}
+	}
+	if last != nil && equals(last, last.next) {
+		removeNode(last)
+		last = last.next
+	}
+	return last
+}
+
+// filterPoints removes duplicate and collinear vertices
+func filterPoints(start, end *earcutNode) *earcutNode {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+	p := start
+	for {
+		again := false
+		if !p.steiner && (equals(p, p.next) || area(p.prev, p, p.next) == 0) {
+			removeNode(p)
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+		if !again && p == end {
+			break
+		}
+	}
+	return end
+}
+
+// earcutLinked clips ears from a ring until it is used up. If no ear can be
+// found, the ring is filtered, then its local self-intersections cured,
+// and finally it is split in two.
+func earcutLinked(ear *earcutNode, triangles []int, minX, minY, invSize float64, pass int) []int {
+	if ear == nil {
+		return triangles
+	}
+	if pass == 0 && invSize != 0 {
+		indexCurve(ear, minX, minY, invSize)
+	}
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+		var isEarNode bool
+		if invSize != 0 {
+			isEarNode = isEarHashed(ear, minX, minY, invSize)
+		} else {
+			isEarNode = isEar(ear)
+		}
+		if isEarNode {
+			triangles = append(triangles, prev.i, ear.i, next.i)
+			removeNode(ear)
+			ear = next.next
+			stop = next.next
+			continue
+		}
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0:
+				triangles = earcutLinked(filterPoints(ear, nil), triangles, minX, minY, invSize, 1)
+			case 1:
+				ear, triangles = cureLocalIntersections(filterPoints(ear, nil), triangles)
+				triangles = earcutLinked(ear, triangles, minX, minY, invSize, 2)
+			case 2:
+				triangles = splitEarcut(ear, triangles, minX, minY, invSize)
+			}
+			break
+		}
+	}
+	return triangles
+}
+
+func triangleBounds(a, b, c *earcutNode) (x0, y0, x1, y1 float64) {
+	x0 = math.Min(a.x, math.Min(b.x, c.x))
+	y0 = math.Min(a.y, math.Min(b.y, c.y))
+	x1 = math.Max(a.x, math.Max(b.x, c.x))
+	y1 = math.Max(a.y, math.Max(b.y, c.y))
+	return
+}
+
+// isEar returns true if the vertex forms a convex corner with no other
+// reflex vertex inside its triangle
+func isEar(ear *earcutNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if area(a, b, c) >= 0 {
+		return false
+	}
+	x0, y0, x1, y1 := triangleBounds(a, b, c)
+	for p := c.next; p != a; p = p.next {
+		if p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 &&
+			pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) && area(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isEarHashed is isEar, searching only the vertices in the z-order range of
+// the triangle's bounding box
+func isEarHashed(ear *earcutNode, minX, minY, invSize float64) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if area(a, b, c) >= 0 {
+		return false
+	}
+	x0, y0, x1, y1 := triangleBounds(a, b, c)
+	minZ := zOrder(x0, y0, minX, minY, invSize)
+	maxZ := zOrder(x1, y1, minX, minY, invSize)
+	blocks := func(p *earcutNode) bool {
+		return p.x >= x0 && p.x <= x1 && p.y >= y0 && p.y <= y1 && p != a && p != c &&
+			pointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) && area(p.prev, p, p.next) >= 0
+	}
+
+	p, n := ear.prevZ, ear.nextZ
+	for p != nil && p.z >= minZ && n != nil && n.z <= maxZ {
+		if blocks(p) {
+			return false
+		}
+		p = p.prevZ
+		if blocks(n) {
+			return false
+		}
+		n = n.nextZ
+	}
+	for ; p != nil && p.z >= minZ; p = p.prevZ {
+		if blocks(p) {
+			return false
+		}
+	}
+	for ; n != nil && n.z <= maxZ; n = n.nextZ {
+		if blocks(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// cureLocalIntersections clips the triangle at each pair of adjacent edges
+// that cross
+func cureLocalIntersections(start *earcutNode, triangles []int) (*earcutNode, []int) {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !equals(a, b) && intersects(a, p, p.next, b) && locallyInside(a, b) && locallyInside(b, a) {
+			triangles = append(triangles, a.i, p.i, b.i)
+			removeNode(p)
+			removeNode(p.next)
+			p = b
+			start = b
+		}
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	return filterPoints(p, nil), triangles
+}
+
+// splitEarcut splits the ring along a valid diagonal and triangulates each
+// half
+func splitEarcut(start *earcutNode, triangles []int, minX, minY, invSize float64) []int {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && isValidDiagonal(a, b) {
+				c := splitPolygon(a, b)
+				a = filterPoints(a, a.next)
+				c = filterPoints(c, c.next)
+				triangles = earcutLinked(a, triangles, minX, minY, invSize, 0)
+				return earcutLinked(c, triangles, minX, minY, invSize, 0)
+			}
+		}
+		a = a.next
+		if a == start {
+			return triangles
+		}
+	}
+}
+
+// eliminateHoles joins each hole to the outer ring with a pair of
+// coincident edges, from left to right
+func eliminateHoles(data []float64, holes []int, outer *earcutNode) *earcutNode {
+	queue := make([]*earcutNode, 0, len(holes))
+	for k, h := range holes {
+		end := len(data)
+		if k+1 < len(holes) {
+			end = 2 * holes[k+1]
+		}
+		list := linkedList(data, 2*h, end, false)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, leftmost(list))
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].x < queue[j].x })
+	for _, hole := range queue {
+		outer = eliminateHole(hole, outer)
+	}
+	return outer
+}
+
+func eliminateHole(hole, outer *earcutNode) *earcutNode {
+	bridge := findHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+	bridgeReverse := splitPolygon(bridge, hole)
+	filterPoints(bridgeReverse, bridgeReverse.next)
+	return filterPoints(bridge, bridge.next)
+}
+
+// findHoleBridge returns a vertex of the outer ring that can be joined to
+// the leftmost vertex of a hole, using David Eberly's algorithm
+func findHoleBridge(hole, outer *earcutNode) *earcutNode {
+	hx, hy := hole.x, hole.y
+	qx := math.Inf(-1)
+	var m *earcutNode
+
+	// find a segment intersected by a ray from the hole's leftmost point to
+	// the left, and its endpoint with the lesser x as a candidate
+	p := outer
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m
+				}
+			}
+		}
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+	if m == nil {
+		return nil
+	}
+
+	// look for vertices inside the triangle of the hole point, the segment
+	// intersection and the candidate, and take the one with the least angle
+	// to the ray
+	stop := m
+	mx, my := m.x, m.y
+	tanMin := math.Inf(1)
+	p = m
+	for {
+		ax, cx := qx, hx
+		if hy < my {
+			ax, cx = hx, qx
+		}
+		if hx >= p.x && p.x >= mx && hx != p.x && pointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+			tan := math.Abs(hy-p.y) / (hx - p.x)
+			if locallyInside(p, hole) &&
+				(tan < tanMin || (tan == tanMin && (p.x > m.x || (p.x == m.x && sectorContainsSector(m, p))))) {
+				m = p
+				tanMin = tan
+			}
+		}
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+	return m
+}
+
+// sectorContainsSector returns true if the sector at p is inside the
+// sector at m
+func sectorContainsSector(m, p *earcutNode) bool {
+	return area(m.prev, m, p.prev) < 0 && area(p.next, m, m.next) < 0
+}
+
+// indexCurve links the vertices of a ring in z-order
+func indexCurve(start *earcutNode, minX, minY, invSize float64) {
+	p := start
+	for {
+		if p.z == 0 {
+			p.z = zOrder(p.x, p.y, minX, minY, invSize)
+		}
+		p.prevZ = p.prev
+		p.nextZ = p.next
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+	p.prevZ.nextZ = nil
+	p.prevZ = nil
+	sortLinked(p)
+}
+
+// sortLinked sorts a list linked by z-order using Simon Tatham's linked
+// list merge sort
+func sortLinked(list *earcutNode) *earcutNode {
+	inSize := 1
+	for {
+		p := list
+		list = nil
+		var tail *earcutNode
+		numMerges := 0
+		for p != nil {
+			numMerges++
+			q := p
+			pSize := 0
+			for i := 0; i < inSize; i++ {
+				pSize++
+				q = q.nextZ
+				if q == nil {
+					break
+				}
+			}
+			qSize := inSize
+			for pSize > 0 || (qSize > 0 && q != nil) {
+				var e *earcutNode
+				if pSize != 0 && (qSize == 0 || q == nil || p.z <= q.z) {
+					e = p
+					p = p.nextZ
+					pSize--
+				} else {
+					e = q
+					q = q.nextZ
+					qSize--
+				}
+				if tail != nil {
+					tail.nextZ = e
+				} else {
+					list = e
+				}
+				e.prevZ = tail
+				tail = e
+			}
+			p = q
+		}
+		tail.nextZ = nil
+		inSize *= 2
+		if numMerges <= 1 {
+			return list
+		}
+	}
+}
+
+// zOrder returns the z-order of a position from its coordinates scaled to
+// 15 bits
+func zOrder(x, y, minX, minY, invSize float64) int32 {
+	ix := int32((x - minX) * invSize)
+	iy := int32((y - minY) * invSize)
+	ix = (ix | (ix << 8)) & 0x00FF00FF
+	ix = (ix | (ix << 4)) & 0x0F0F0F0F
+	ix = (ix | (ix << 2)) & 0x33333333
+	ix = (ix | (ix << 1)) & 0x55555555
+	iy = (iy | (iy << 8)) & 0x00FF00FF
+	iy = (iy | (iy << 4)) & 0x0F0F0F0F
+	iy = (iy | (iy << 2)) & 0x33333333
+	iy = (iy | (iy << 1)) & 0x55555555
+	return ix | (iy << 1)
+}
+
+// leftmost returns the leftmost vertex of a ring
+func leftmost(start *earcutNode) *earcutNode {
+	p, left := start, start
+	for {
+		if p.x < left.x || (p.x == left.x && p.y < left.y) {
+			left = p
+		}
+		p = p.next
+		if p == start {
+			return left
+		}
+	}
+}
+
+// pointInTriangle returns true if p lies inside or on the triangle a, b, c
+func pointInTriangle(ax, ay, bx, by, cx, cy, px, py float64) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+// isValidDiagonal returns true if a diagonal from a to b lies inside the
+// ring without crossing it
+func isValidDiagonal(a, b *earcutNode) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !intersectsPolygon(a, b) &&
+		(locallyInside(a, b) && locallyInside(b, a) && middleInside(a, b) &&
+			(area(a.prev, a, b.prev) != 0 || area(a, b.prev, b) != 0) ||
+			equals(a, b) && area(a.prev, a, a.next) > 0 && area(b.prev, b, b.next) > 0)
+}
+
+// area returns twice the area of the triangle p, q, r, negative when it is
+// counter-clockwise
+func area(p, q, r *earcutNode) float64 {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+func equals(p, q *earcutNode) bool {
+	return p.x == q.x && p.y == q.y
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment returns true if q lies within the bounds of collinear p and r
+func onSegment(p, q, r *earcutNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+// intersects returns true if the segments p1 q1 and p2 q2 meet
+func intersects(p1, q1, p2, q2 *earcutNode) bool {
+	o1 := sign(area(p1, q1, p2))
+	o2 := sign(area(p1, q1, q2))
+	o3 := sign(area(p2, q2, p1))
+	o4 := sign(area(p2, q2, q1))
+	return o1 != o2 && o3 != o4 ||
+		o1 == 0 && onSegment(p1, p2, q1) ||
+		o2 == 0 && onSegment(p1, q2, q1) ||
+		o3 == 0 && onSegment(p2, p1, q2) ||
+		o4 == 0 && onSegment(p2, q1, q2)
+}
+
+// intersectsPolygon returns true if the diagonal from a to b crosses an
+// edge of the ring
+func intersectsPolygon(a, b *earcutNode) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i && intersects(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+// locallyInside returns true if the diagonal from a to b starts into the
+// interior of the ring
+func locallyInside(a, b *earcutNode) bool {
+	if area(a.prev, a, a.next) < 0 {
+		return area(a, b, a.next) >= 0 && area(a, a.prev, b) >= 0
+	}
+	return area(a, b, a.prev) < 0 || area(a, a.next, b) < 0
+}
+
+// middleInside returns true if the midpoint of the diagonal from a to b is
+// inside the ring
+func middleInside(a, b *earcutNode) bool {
+	p := a
+	inside := false
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y &&
+			px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+// splitPolygon joins vertices a and b with a diagonal, splitting the ring
+// in two, or joins a hole to the outer ring when a and b are in different
+// rings. It returns the copy of b in the second ring.
+func splitPolygon(a, b *earcutNode) *earcutNode {
+	a2 := &earcutNode{i: a.i, x: a.x, y: a.y}
+	b2 := &earcutNode{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next = b
+	b.prev = a
+	a2.next = an
+	an.prev = a2
+	b2.next = a2
+	a2.prev = b2
+	bp.next = b2
+	b2.prev = bp
+	return b2
+}
+
+func insertNode(i int, x, y float64, last *earcutNode) *earcutNode {
+	p := &earcutNode{i: i, x: x, y: y}
+	if last == nil {
+		p.prev = p
+		p.next = p
+	} else {
+		p.next = last.next
+		p.prev = last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+func removeNode(p *earcutNode) {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+	if p.prevZ != nil {
+		p.prevZ.nextZ = p.nextZ
+	}
+	if p.nextZ != nil {
+		p.nextZ.prevZ = p.prevZ
+	}
+}
@@ -0,0 +1,124 @@
+package triangulate
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+// meshArea returns the summed signed area of the triangles of a Mesh
+func meshArea(m *Mesh) float64 {
+	var area float64
+	for _, g := range m.GeometryCollection().Geometries {
+		ring := g.Polygon.Coordinates[0]
+		for i := 1; i < len(ring); i++ {
+			area += (ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]) / 2
+		}
+	}
+	return area
+}
+
+func TestEarcutSquareWithHole(t *testing.T) {
+	g := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+		{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+	}}}
+	mesh, err := Earcut(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mesh.Vertices) != 16 || len(mesh.Indices) != 8*3 {
+		fmt.Println("recieved    ", mesh.Vertices, mesh.Indices)
+		t.Fail()
+	}
+	// every triangle is counter-clockwise
+	for _, tri := range mesh.GeometryCollection().Geometries {
+		if tri.Area() <= 0 {
+			fmt.Println("recieved    ", tri.Polygon.Coordinates)
+			t.Fail()
+		}
+	}
+	if area := meshArea(mesh); area != 12 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", 12)
+		t.Fail()
+	}
+}
+
+func TestEarcutConcave(t *testing.T) {
+	// a clockwise comb with three teeth
+	ring := [][]float64{{0, 0}, {0, 3}, {1, 3}, {1, 1}, {2, 1}, {2, 3}, {3, 3}, {3, 1}, {4, 1}, {4, 3}, {5, 3}, {5, 0}, {0, 0}}
+	g := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{ring}}}
+	mesh, err := Earcut(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mesh.Indices) != 10*3 {
+		fmt.Println("recieved    ", len(mesh.Indices)/3)
+		fmt.Println("but expected", 10)
+		t.Fail()
+	}
+	if area := meshArea(mesh); area != 11 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", 11)
+		t.Fail()
+	}
+}
+
+func TestEarcutMultiPolygon(t *testing.T) {
+	g := &geojson.Geo{Type: "MultiPolygon", MultiPolygon: &geojson.MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		{{{2, 0}, {3, 0}, {3, 1}, {2, 1}, {2, 0}}},
+	}}}
+	mesh, err := Earcut(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mesh.Vertices) != 14 || len(mesh.Indices) != 9 {
+		fmt.Println("recieved    ", mesh.Vertices, mesh.Indices)
+		t.FailNow()
+	}
+	// indices of the second polygon follow the vertices of the first
+	for _, i := range mesh.Indices[3:] {
+		if i < 3 || i > 6 {
+			fmt.Println("recieved    ", mesh.Indices)
+			t.Fail()
+		}
+	}
+	if area := meshArea(mesh); area != 1.5 {
+		fmt.Println("recieved    ", area)
+		t.Fail()
+	}
+
+	if _, err = Earcut(&geojson.Geo{Type: "Point", Point: &geojson.Point{Coordinates: []float64{0, 0}}}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestEarcutCircle(t *testing.T) {
+	// enough vertices to use z-order hashing, with a hole offset from the
+	// centre
+	var shell, hole [][]float64
+	for i := 0; i <= 200; i++ {
+		a := 2 * math.Pi * float64(i%200) / 200
+		shell = append(shell, []float64{10 * math.Cos(a), 10 * math.Sin(a)})
+		hole = append(hole, []float64{3 + 2*math.Cos(-a), 2 * math.Sin(-a)})
+	}
+	poly := &geojson.Polygon{Coordinates: [][][]float64{shell, hole}}
+	mesh, err := Earcut(&geojson.Geo{Type: "Polygon", Polygon: poly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mesh.Indices) != 400*3 {
+		fmt.Println("recieved    ", len(mesh.Indices)/3)
+		fmt.Println("but expected", 400)
+		t.Fail()
+	}
+	if area := meshArea(mesh); math.Abs(area-poly.Area()) > 1e-9 {
+		fmt.Println("recieved    ", area)
+		fmt.Println("but expected", poly.Area())
+		t.Fail()
+	}
+}
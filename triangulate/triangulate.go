@@ -0,0 +1,47 @@
+// Package triangulate computes Delaunay triangulations and Voronoi diagrams
+// of the positions of a MultiPoint, and triangle meshes of Polygons for
+// rendering
+package triangulate
+
+import (
+	"errors"
+
+	"github.com/njwilson23/geojson.go"
+	"github.com/njwilson23/geojson.go/internal/delaunay"
+)
+
+// triangulation returns the Delaunay triangulation of the positions of a
+// MultiPoint
+func triangulation(mp *geojson.MultiPoint) (*delaunay.Triangulation, error) {
+	for _, pos := range mp.Coordinates {
+		if len(pos) < 2 {
+			return nil, errors.New("positions must have at least two coordinates")
+		}
+	}
+	return delaunay.Triangulate(mp.Coordinates), nil
+}
+
+// Delaunay returns the Delaunay triangulation of the positions of a
+// MultiPoint as a GeometryCollection of triangular Polygons, each closed
+// and counter-clockwise. No triangle has a position inside its
+// circumcircle, and together they cover the convex hull of the positions.
+// Duplicate positions are used once, and the collection is empty when there
+// are fewer than three distinct positions or they are all collinear.
+func Delaunay(mp *geojson.MultiPoint) (*geojson.GeometryCollection, error) {
+	t, err := triangulation(mp)
+	if err != nil {
+		return nil, err
+	}
+	coll := &geojson.GeometryCollection{
+		CRSReferencable: mp.CRSReferencable,
+		Geometries:      make([]*geojson.Geo, 0, len(t.Triangles)/3),
+	}
+	for e := 0; e < len(t.Triangles); e += 3 {
+		a, b, c := t.Points[t.Triangles[e]], t.Points[t.Triangles[e+1]], t.Points[t.Triangles[e+2]]
+		coll.Geometries = append(coll.Geometries, &geojson.Geo{
+			Type:    "Polygon",
+			Polygon: &geojson.Polygon{Coordinates: [][][]float64{{a, b, c, a}}},
+		})
+	}
+	return coll, nil
+}
@@ -0,0 +1,173 @@
+package triangulate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/njwilson23/geojson.go"
+)
+
+func TestDelaunay(t *testing.T) {
+	mp := &geojson.MultiPoint{Coordinates: [][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}, {1, 1}}}
+	coll, err := Delaunay(mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coll.Geometries) != 4 {
+		fmt.Println("recieved    ", len(coll.Geometries))
+		fmt.Println("but expected", 4)
+		t.Fail()
+	}
+	for _, g := range coll.Geometries {
+		ring := g.Polygon.Coordinates[0]
+		if len(ring) != 4 || ring[0][0] != ring[3][0] || ring[0][1] != ring[3][1] {
+			fmt.Println("recieved    ", ring)
+			t.Fail()
+		}
+		// counter-clockwise with unit area
+		var area float64
+		for i := 1; i < len(ring); i++ {
+			area += (ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]) / 2
+		}
+		if area != 1 {
+			fmt.Println("recieved    ", area)
+			t.Fail()
+		}
+	}
+}
+
+func TestDelaunayCollinear(t *testing.T) {
+	mp := &geojson.MultiPoint{Coordinates: [][]float64{{0, 0}, {1, 1}, {2, 2}}}
+	coll, err := Delaunay(mp)
+	if err != nil || len(coll.Geometries) != 0 {
+		t.Fail()
+	}
+	if _, err = Delaunay(&geojson.MultiPoint{Coordinates: [][]float64{{0}}}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestVoronoi(t *testing.T) {
+	mp := &geojson.MultiPoint{Coordinates: [][]float64{{1, 1}, {3, 1}, {1, 3}, {3, 3}, {3, 3}}}
+	cells, err := Voronoi(mp, geojson.NewBbox(0, 0, 4, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the repeated position has no cell
+	if len(cells) != 4 {
+		t.Fatal("expected 4 cells, got", len(cells))
+	}
+	for i, cell := range cells {
+		if cell.Index != i || cell.Geometry.Area() != 4 {
+			fmt.Println("recieved    ", cell.Index, cell.Geometry.Area())
+			t.Fail()
+		}
+		bb, _ := cell.Geometry.Bbox()
+		xmin, ymin, xmax, ymax := bb.Extent()
+		pos := mp.Coordinates[cell.Index]
+		if pos[0] < xmin || pos[0] > xmax || pos[1] < ymin || pos[1] > ymax {
+			fmt.Println("recieved    ", bb, "for", pos)
+			t.Fail()
+		}
+	}
+}
+
+func TestVoronoiRandom(t *testing.T) {
+	// cells of scattered points tile the box
+	var coords [][]float64
+	for i := 0; i < 50; i++ {
+		coords = append(coords, []float64{math.Mod(float64(i)*0.618034, 1) * 10, math.Mod(float64(i*i)*0.414214, 1) * 10})
+	}
+	mp := &geojson.MultiPoint{Coordinates: coords}
+	cells, err := Voronoi(mp, geojson.NewBbox(-1, -1, 11, 11))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var area float64
+	for _, cell := range cells {
+		area += cell.Geometry.Area()
+	}
+	if len(cells) != 50 || math.Abs(area-144) > 1e-9 {
+		fmt.Println("recieved    ", len(cells), area)
+		t.Fail()
+	}
+}
+
+func TestVoronoiDuplicates(t *testing.T) {
+	// repeated positions in random order keep one cell each, given to the
+	// first of the copies, and the cells still tile the box
+	r := rand.New(rand.NewSource(41))
+	for run := 0; run < 300; run++ {
+		var distinct [][]float64
+		for i := 0; i < 12; i++ {
+			distinct = append(distinct, []float64{r.Float64() * 10, r.Float64() * 10})
+		}
+		var coords [][]float64
+		for _, pos := range distinct {
+			for k := 0; k < 1+r.Intn(3); k++ {
+				coords = append(coords, []float64{pos[0], pos[1]})
+			}
+		}
+		r.Shuffle(len(coords), func(i, j int) { coords[i], coords[j] = coords[j], coords[i] })
+
+		cells, err := Voronoi(&geojson.MultiPoint{Coordinates: coords}, geojson.NewBbox(-1, -1, 11, 11))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var area float64
+		first := make(map[[2]float64]int)
+		for i := len(coords) - 1; i >= 0; i-- {
+			first[[2]float64{coords[i][0], coords[i][1]}] = i
+		}
+		for _, cell := range cells {
+			area += cell.Geometry.Area()
+			pos := coords[cell.Index]
+			if first[[2]float64{pos[0], pos[1]}] != cell.Index {
+				fmt.Println("cell given to repeated position", cell.Index)
+				t.Fail()
+			}
+		}
+		if len(cells) != len(distinct) || math.Abs(area-144) > 1e-9 {
+			fmt.Println("recieved    ", len(cells), area)
+			fmt.Println("but expected", len(distinct), 144)
+			t.Fatal()
+		}
+	}
+}
+
+func TestVoronoiTwoPoints(t *testing.T) {
+	mp := &geojson.MultiPoint{Coordinates: [][]float64{{1, 1}, {3, 1}}}
+	cells, err := Voronoi(mp, geojson.NewBbox(0, 0, 4, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 2 || cells[0].Geometry.Area() != 4 || cells[1].Geometry.Area() != 4 {
+		fmt.Println("recieved    ", cells)
+		t.Fail()
+	}
+}
+
+func TestVoronoiClipped(t *testing.T) {
+	// a U shape whose arms both lie in the cell of the top point
+	clip := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: [][][]float64{{
+		{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}, {0, 0},
+	}}}}
+	mp := &geojson.MultiPoint{Coordinates: [][]float64{{3, 1}, {3, 5}, {30, 30}}}
+	cells, err := VoronoiClipped(mp, clip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cells) != 2 {
+		t.Fatal("expected 2 cells, got", len(cells))
+	}
+	if cells[0].Index != 0 || cells[0].Geometry.Type != "Polygon" || cells[0].Geometry.Area() != 16 {
+		fmt.Println("recieved    ", cells[0].Index, cells[0].Geometry.Type, cells[0].Geometry.Area())
+		t.Fail()
+	}
+	if cells[1].Index != 1 || cells[1].Geometry.Type != "MultiPolygon" || cells[1].Geometry.Area() != 12 {
+		fmt.Println("recieved    ", cells[1].Index, cells[1].Geometry.Type, cells[1].Geometry.Area())
+		t.Fail()
+	}
+}
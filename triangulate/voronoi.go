@@ -0,0 +1,176 @@
+package triangulate
+
+import (
+	"errors"
+
+	"github.com/njwilson23/geojson.go"
+	"github.com/njwilson23/geojson.go/internal/delaunay"
+)
+
+// Cell is the region of a Voronoi diagram closer to one position of a
+// MultiPoint than to any other
+type Cell struct {
+	// Index is the index of the generating position in the MultiPoint
+	Index int
+	// Geometry is a Polygon, or a MultiPolygon where clipping to a Polygon
+	// divides the cell
+	Geometry *geojson.Geo
+}
+
+// neighbours returns the Delaunay neighbours of each point, which are the
+// points whose Voronoi cells share an edge with its own
+func neighbours(t *delaunay.Triangulation) [][]int {
+	adj := make([][]int, len(t.Points))
+	if len(t.Triangles) == 0 {
+		// collinear points neighbour those before and after them on the line
+		for i := 1; i < len(t.Hull); i++ {
+			a, b := t.Hull[i-1], t.Hull[i]
+			adj[a] = append(adj[a], b)
+			adj[b] = append(adj[b], a)
+		}
+		return adj
+	}
+	for e := range t.Triangles {
+		a, b := t.Triangles[e], t.Triangles[delaunay.Next(e)]
+		// each interior edge appears twice, once in each direction
+		if t.Halfedges[e] == -1 || a < b {
+			adj[a] = append(adj[a], b)
+			adj[b] = append(adj[b], a)
+		}
+	}
+	return adj
+}
+
+// clipHalfPlane clips a convex ring to the half of the plane closer to p
+// than to q
+func clipHalfPlane(ring [][]float64, p, q []float64) [][]float64 {
+	mx, my := (p[0]+q[0])/2, (p[1]+q[1])/2
+	nx, ny := q[0]-p[0], q[1]-p[1]
+	side := func(pos []float64) float64 {
+		return (pos[0]-mx)*nx + (pos[1]-my)*ny
+	}
+	var out [][]float64
+	for i := range ring {
+		cur, prev := ring[i], ring[(i+len(ring)-1)%len(ring)]
+		sc, sp := side(cur), side(prev)
+		if (sc <= 0) != (sp <= 0) {
+			t := sp / (sp - sc)
+			out = append(out, []float64{prev[0] + t*(cur[0]-prev[0]), prev[1] + t*(cur[1]-prev[1])})
+		}
+		if sc <= 0 {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// cells returns the open Voronoi cell rings of the distinct positions,
+// clipped to a rectangle. Positions repeating an earlier one have no cell.
+func cells(mp *geojson.MultiPoint, xmin, ymin, xmax, ymax float64) (map[int][][]float64, error) {
+	t, err := triangulation(mp)
+	if err != nil {
+		return nil, err
+	}
+	adj := neighbours(t)
+	// the triangulation keeps one of each set of repeated positions, which
+	// need not be the first, so the neighbours of a position are taken from
+	// whichever copy has them
+	neighboursOf := make(map[[2]float64][]int)
+	for i, pos := range t.Points {
+		key := [2]float64{pos[0], pos[1]}
+		if len(neighboursOf[key]) == 0 {
+			neighboursOf[key] = adj[i]
+		}
+	}
+	seen := make(map[[2]float64]bool)
+	rings := make(map[int][][]float64)
+	for i, pos := range t.Points {
+		key := [2]float64{pos[0], pos[1]}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ring := [][]float64{{xmin, ymin}, {xmax, ymin}, {xmax, ymax}, {xmin, ymax}}
+		for _, j := range neighboursOf[key] {
+			ring = clipHalfPlane(ring, pos, t.Points[j])
+		}
+		if len(ring) >= 3 {
+			rings[i] = ring
+		}
+	}
+	return rings, nil
+}
+
+func closeRing(ring [][]float64) [][][]float64 {
+	closed := append(append([][]float64{}, ring...), []float64{ring[0][0], ring[0][1]})
+	return [][][]float64{closed}
+}
+
+// Voronoi returns the Voronoi diagram of the positions of a MultiPoint
+// clipped to a Bbox, as counter-clockwise Polygon cells in the order of
+// the positions that generate them. Positions that repeat an earlier one,
+// and those whose cells lie outside the Bbox, have no cell.
+func Voronoi(mp *geojson.MultiPoint, bb *geojson.Bbox) ([]Cell, error) {
+	if bb == nil {
+		return nil, errors.New("nil Bbox")
+	}
+	xmin, ymin, xmax, ymax := bb.Extent()
+	if xmin >= xmax || ymin >= ymax {
+		return nil, errors.New("Bbox must have a positive area")
+	}
+	rings, err := cells(mp, xmin, ymin, xmax, ymax)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Cell, 0, len(rings))
+	for i := range mp.Coordinates {
+		if ring, ok := rings[i]; ok {
+			result = append(result, Cell{i, &geojson.Geo{
+				Type:    "Polygon",
+				Polygon: &geojson.Polygon{CRSReferencable: mp.CRSReferencable, Coordinates: closeRing(ring)},
+			}})
+		}
+	}
+	return result, nil
+}
+
+// VoronoiClipped returns the Voronoi diagram of the positions of a
+// MultiPoint clipped to a Polygon or MultiPolygon, such as a service area.
+// Cells that the boundary divides are MultiPolygons, and positions whose
+// cells lie outside it have no cell. See Voronoi.
+func VoronoiClipped(mp *geojson.MultiPoint, clip *geojson.Geo) ([]Cell, error) {
+	bb, err := clip.Bbox()
+	if err != nil {
+		return nil, err
+	}
+	xmin, ymin, xmax, ymax := bb.Extent()
+	if xmin >= xmax || ymin >= ymax {
+		return nil, errors.New("clipping geometry must have a positive area")
+	}
+	rings, err := cells(mp, xmin, ymin, xmax, ymax)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Cell, 0, len(rings))
+	for i := range mp.Coordinates {
+		ring, ok := rings[i]
+		if !ok {
+			continue
+		}
+		cell := &geojson.Geo{Type: "Polygon", Polygon: &geojson.Polygon{Coordinates: closeRing(ring)}}
+		clipped, err := geojson.Intersection(cell, clip)
+		if err != nil {
+			return nil, err
+		}
+		if clipped.Type == "MultiPolygon" {
+			if len(clipped.MultiPolygon.Coordinates) == 0 {
+				continue
+			}
+			clipped.MultiPolygon.CRSReferencable = mp.CRSReferencable
+		} else {
+			clipped.Polygon.CRSReferencable = mp.CRSReferencable
+		}
+		result = append(result, Cell{i, clipped})
+	}
+	return result, nil
+}
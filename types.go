@@ -26,6 +26,16 @@ type Bbox struct {
 	xmin, ymin, xmax, ymax float64
 }
 
+// NewBbox returns a Bbox with the given extent
+func NewBbox(xmin, ymin, xmax, ymax float64) *Bbox {
+	return &Bbox{xmin, ymin, xmax, ymax}
+}
+
+// Extent returns the minimum and maximum coordinates of the Bbox
+func (bb *Bbox) Extent() (xmin, ymin, xmax, ymax float64) {
+	return bb.xmin, bb.ymin, bb.xmax, bb.ymax
+}
+
 // Geo represents a GeoJSON entity
 type Geo struct {
 	Type               string
@@ -66,6 +76,31 @@ func (g *Geo) Bbox() (bb *Bbox, err error) {
 	return
 }
 
+// GetCRS returns the CRS member of the object, which is nil if it has none
+func (g *Geo) GetCRS() (*CRS, error) {
+	switch g.Type {
+	case "Point":
+		return g.Point.CRS, nil
+	case "LineString":
+		return g.LineString.CRS, nil
+	case "Polygon":
+		return g.Polygon.CRS, nil
+	case "MultiPoint":
+		return g.MultiPoint.CRS, nil
+	case "MultiLineString":
+		return g.MultiLineString.CRS, nil
+	case "MultiPolygon":
+		return g.MultiPolygon.CRS, nil
+	case "GeometryCollection":
+		return g.GeometryCollection.CRS, nil
+	case "Feature":
+		return g.Feature.CRS, nil
+	case "FeatureCollection":
+		return g.FeatureCollection.CRS, nil
+	}
+	return nil, fmt.Errorf("unhandled type: '%s'", g.Type)
+}
+
 type Boundable interface {
 	Bbox() (*Bbox, error)
 }
@@ -145,7 +180,7 @@ func (g *Polygon) Bbox() (*Bbox, error) {
 	xmax := g.Coordinates[0][0][0]
 	ymax := g.Coordinates[0][0][1]
 	var i int
-	for i = 1; i != len(g.Coordinates); i++ {
+	for i = 1; i != len(g.Coordinates[0]); i++ {
 		xmin = math.Min(g.Coordinates[0][i][0], xmin)
 		ymin = math.Min(g.Coordinates[0][i][1], ymin)
 		xmax = math.Max(g.Coordinates[0][i][0], xmax)
@@ -198,7 +233,7 @@ func (g *MultiPolygon) Bbox() (*Bbox, error) {
 	var i, j int
 	var position []float64
 	for i = 0; i != len(g.Coordinates); i++ {
-		for j = 0; j != len(g.Coordinates[i]); j++ {
+		for j = 0; j != len(g.Coordinates[i][0]); j++ {
 			position = g.Coordinates[i][0][j]
 			xmin = math.Min(position[0], xmin)
 			ymin = math.Min(position[1], ymin)
@@ -211,18 +246,15 @@ func (g *MultiPolygon) Bbox() (*Bbox, error) {
 }
 
 func (coll *GeometryCollection) Bbox() (bb *Bbox, err error) {
-	bboxes := make([]*Bbox, len(coll.Geometries))
+	bboxes := make([]*Bbox, 0, len(coll.Geometries))
 	for _, g := range coll.Geometries {
-		bb, err := g.Bbox()
+		bb, err = g.Bbox()
 		if err != nil {
-			break
+			return nil, err
 		}
 		bboxes = append(bboxes, bb)
 	}
-	if err == nil {
-		bb, err = unionBbox(bboxes)
-	}
-	return
+	return unionBbox(bboxes)
 }
 
 func (f *Feature) Bbox() (bb *Bbox, err error) {
@@ -231,18 +263,15 @@ func (f *Feature) Bbox() (bb *Bbox, err error) {
 }
 
 func (coll *FeatureCollection) Bbox() (bb *Bbox, err error) {
-	bboxes := make([]*Bbox, len(coll.Features))
+	bboxes := make([]*Bbox, 0, len(coll.Features))
 	for _, f := range coll.Features {
-		bb, err := f.Bbox()
+		bb, err = f.Bbox()
 		if err != nil {
-			break
+			return nil, err
 		}
 		bboxes = append(bboxes, bb)
 	}
-	if err == nil {
-		bb, err = unionBbox(bboxes)
-	}
-	return
+	return unionBbox(bboxes)
 }
 
 /* String methods */
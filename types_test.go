@@ -0,0 +1,63 @@
+package geojson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPolygonBbox(t *testing.T) {
+	// the extreme positions come after the first few, so the bounding box
+	// must look at every vertex of the shell
+	poly := &Polygon{Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {4, 3}, {-2, 5}, {0, 0}}}}
+	bb, err := poly.Bbox()
+	if err != nil || *bb != (Bbox{-2, 0, 4, 5}) {
+		fmt.Println("recieved    ", bb, err)
+		fmt.Println("but expected", Bbox{-2, 0, 4, 5})
+		t.Fail()
+	}
+
+	mp := &MultiPolygon{Coordinates: [][][][]float64{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{5, 5}, {6, 5}, {7, 8}, {5, 6}, {5, 5}}},
+	}}
+	bb, err = mp.Bbox()
+	if err != nil || *bb != (Bbox{0, 0, 7, 8}) {
+		fmt.Println("recieved    ", bb, err)
+		fmt.Println("but expected", Bbox{0, 0, 7, 8})
+		t.Fail()
+	}
+}
+
+func TestCollectionBbox(t *testing.T) {
+	pt := &Geo{Type: "Point", Point: &Point{Coordinates: []float64{-3, 2}}}
+	poly := &Geo{Type: "Polygon", Polygon: &Polygon{Coordinates: [][][]float64{
+		{{0, 0}, {1, 0}, {1, 1}, {4, 3}, {0, 0}}}}}
+	coll := &GeometryCollection{Geometries: []*Geo{pt, poly}}
+	bb, err := coll.Bbox()
+	if err != nil || *bb != (Bbox{-3, 0, 4, 3}) {
+		fmt.Println("recieved    ", bb, err)
+		fmt.Println("but expected", Bbox{-3, 0, 4, 3})
+		t.Fail()
+	}
+
+	fc := &FeatureCollection{Features: []Feature{{Geometry: *pt}, {Geometry: *poly}}}
+	bb, err = fc.Bbox()
+	if err != nil || *bb != (Bbox{-3, 0, 4, 3}) {
+		fmt.Println("recieved    ", bb, err)
+		fmt.Println("but expected", Bbox{-3, 0, 4, 3})
+		t.Fail()
+	}
+
+	// errors from members are returned rather than causing a panic
+	coll.Geometries = append(coll.Geometries, &Geo{Type: "Curve"})
+	if _, err = coll.Bbox(); err == nil {
+		t.Fail()
+	}
+	fc.Features = append(fc.Features, Feature{Geometry: Geo{Type: "Curve"}})
+	if _, err = fc.Bbox(); err == nil {
+		t.Fail()
+	}
+	if _, err = (&GeometryCollection{}).Bbox(); err == nil {
+		t.Fail()
+	}
+}
@@ -19,3 +19,58 @@ func unionBbox(bboxes []*Bbox) (*Bbox, error) {
 	}
 	return bb, nil
 }
+
+// components holds the positions of a geometry grouped by dimension
+type components struct {
+	points   [][]float64
+	lines    [][][]float64
+	polygons [][][][]float64
+}
+
+// dimension returns the highest dimension present, or -1 if there is nothing
+func (c *components) dimension() int {
+	switch {
+	case len(c.polygons) != 0:
+		return 2
+	case len(c.lines) != 0:
+		return 1
+	case len(c.points) != 0:
+		return 0
+	}
+	return -1
+}
+
+// add appends the components of any geometry, Feature or FeatureCollection
+func (c *components) add(g *Geo) error {
+	switch g.Type {
+	case "Point":
+		c.points = append(c.points, g.Point.Coordinates)
+	case "LineString":
+		c.lines = append(c.lines, g.LineString.Coordinates)
+	case "Polygon":
+		c.polygons = append(c.polygons, g.Polygon.Coordinates)
+	case "MultiPoint":
+		c.points = append(c.points, g.MultiPoint.Coordinates...)
+	case "MultiLineString":
+		c.lines = append(c.lines, g.MultiLineString.Coordinates...)
+	case "MultiPolygon":
+		c.polygons = append(c.polygons, g.MultiPolygon.Coordinates...)
+	case "GeometryCollection":
+		for _, geom := range g.GeometryCollection.Geometries {
+			if err := c.add(geom); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		return c.add(&g.Feature.Geometry)
+	case "FeatureCollection":
+		for i := range g.FeatureCollection.Features {
+			if err := c.add(&g.FeatureCollection.Features[i].Geometry); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unhandled type: '%s'", g.Type)
+	}
+	return nil
+}